@@ -0,0 +1,97 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+// aliasKey - derives the legacy key of a record. Virtual servers were keyed
+// by md5(ip:ports-load_balancer_ip) before ids became stable; the key is kept
+// as an alias so lookups by the old id and payloads without an id still
+// resolve. Every other table is still keyed this way.
+func (o *Common) aliasKey(d *DbRecord) (r string, err error) {
+	var key string
+	var data Data
+	err = shared.MarshalInterface(d.Data, &data)
+	if err != nil {
+		return "", err
+	}
+	switch o.Database.Table {
+	case "loadbalancers":
+		key = fmt.Sprintf("%s", d.LoadBalancerIP)
+	case "virtualservers":
+		portsEnc, err := shared.EncodePorts(data.Ports)
+		if err != nil {
+			return "", err
+		}
+		key = fmt.Sprintf("%s:%s-%s", data.IP, portsEnc, d.LoadBalancerIP)
+	default:
+		key = data.SourceUUID
+	}
+	return shared.GetMD5Hash(strings.TrimSpace(key)), nil
+}
+
+// idFromAlias - returns the id of the most recently modified virtual server
+// with the alias. Returns an empty string when there is no match.
+func (o *Common) idFromAlias(alias string) (r string, err error) {
+	if o.Database.Table != "virtualservers" {
+		return "", nil
+	}
+	err = o.Database.Client.Db.QueryRow(`SELECT id FROM public.virtualservers WHERE alias=$1 ORDER BY last_modified DESC LIMIT 1`, alias).Scan(&r)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return
+}
+
+// aliasIDs - maps the alias of every virtual server to its id.
+func (o *Common) aliasIDs() (r map[string]string, err error) {
+	r = make(map[string]string)
+	if o.Database.Table != "virtualservers" {
+		return
+	}
+	rows, err := o.Database.Client.Db.Query(`SELECT alias, id FROM public.virtualservers WHERE alias IS NOT NULL`)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var alias, id string
+		err = rows.Scan(&alias, &id)
+		if err != nil {
+			return
+		}
+		r[alias] = id
+	}
+	return r, rows.Err()
+}
+
+// promoteDbRecord - moves the record with id, and its status, to retiredID
+// and the record with tempID, and its status, to id in one transaction.
+func (o *Common) promoteDbRecord(id string, tempID string, retiredID string) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	tx, err := o.Database.Client.Db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	////////////////////////////////////////////////////////////////////////////
+	for _, table := range []string{o.Database.Table, "status"} {
+		_, err = tx.Exec(`UPDATE public.`+table+` SET id=$1 WHERE id=$2`, retiredID, id)
+		if err != nil {
+			return
+		}
+		_, err = tx.Exec(`UPDATE public.`+table+` SET id=$1 WHERE id=$2`, id, tempID)
+		if err != nil {
+			return
+		}
+	}
+	return tx.Commit()
+}
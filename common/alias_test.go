@@ -0,0 +1,46 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+func TestAliasKey(t *testing.T) {
+	vs := func(ip string, ports ...int) map[string]interface{} {
+		var p []map[string]interface{}
+		for _, v := range ports {
+			p = append(p, map[string]interface{}{"port": v})
+		}
+		return map[string]interface{}{"ip": ip, "ports": p, "_uuid": "virtualservice-1"}
+	}
+	ports, err := shared.EncodePorts([]map[string]interface{}{{"port": 443}, {"port": 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name   string
+		table  string
+		record DbRecord
+		want   string
+	}{
+		{"virtualserver", "virtualservers", DbRecord{LoadBalancerIP: "10.1.1.1", Data: vs("10.0.0.1", 80, 443)}, shared.GetMD5Hash("10.0.0.1:" + ports + "-10.1.1.1")},
+		{"virtualserver port order", "virtualservers", DbRecord{LoadBalancerIP: "10.1.1.1", Data: vs("10.0.0.1", 443, 80)}, shared.GetMD5Hash("10.0.0.1:" + ports + "-10.1.1.1")},
+		{"loadbalancer", "loadbalancers", DbRecord{LoadBalancerIP: "10.1.1.1", Data: vs("10.0.0.1")}, shared.GetMD5Hash("10.1.1.1")},
+		{"other tables", "pools", DbRecord{LoadBalancerIP: "10.1.1.1", Data: vs("10.0.0.1")}, shared.GetMD5Hash("virtualservice-1")},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Common{Database: &Database{Table: tt.table}}
+			r, err := o.aliasKey(&tt.record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/migrate"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+const (
+	// ApprovalPending - change request is waiting for a second user.
+	ApprovalPending = "pending"
+	// ApprovalApproved - change request was approved and released.
+	ApprovalApproved = "approved"
+	// ApprovalRejected - change request was rejected.
+	ApprovalRejected = "rejected"
+)
+
+// ChangeRequest - create, modify, delete, migrate or binding change of a
+// record with a protected product code, waiting for the approval of a second user. Change
+// requests are stored in the approvals table.
+type ChangeRequest struct {
+	// Action - create, modify, delete, migrate or binding.
+	Action string `json:"action"`
+	// Route - route the change is applied through.
+	Route string `json:"route"`
+	// RecordID - id of the record being changed. Empty for create.
+	RecordID string `json:"record_id,omitempty"`
+	// ProductCodes - protected product codes the change touches. The approver
+	// needs rights to each.
+	ProductCodes []int `json:"product_codes"`
+	// ETag - entity tag of the record when the change was requested. The
+	// change fails if the record has been modified since.
+	ETag string `json:"etag,omitempty"`
+	// Payload - create or modify request. Private keys are redacted and
+	// restored from secure storage when the change is released.
+	Payload interface{} `json:"payload,omitempty"`
+	// Binding - pool member to enable or disable for a binding change.
+	Binding *BindingChange `json:"binding,omitempty"`
+	// ApplyAt - time the change is scheduled for once approved.
+	ApplyAt string `json:"apply_at,omitempty"`
+	// Window - maintenance window the change is scheduled into once approved.
+	Window string `json:"window,omitempty"`
+	// Status - pending, approved, rejected or fail.
+	Status string `json:"status"`
+	// User - user that requested the change. The change is applied with the
+	// user's rights.
+	User string `json:"user"`
+	// Groups - groups of the user when the change was requested.
+	Groups []string `json:"groups,omitempty"`
+	// Token - scope of the API token the change was requested with.
+	Token *userenv.TokenScope `json:"token,omitempty"`
+	// Submitted - time the change was requested.
+	Submitted string `json:"submitted"`
+	// ReviewedBy - user that approved or rejected the change.
+	ReviewedBy string `json:"reviewed_by,omitempty"`
+	// Reviewed - time the change was approved or rejected.
+	Reviewed string `json:"reviewed,omitempty"`
+	// Reason - reason given for a rejection.
+	Reason string `json:"reason,omitempty"`
+	// OperationID - operation tracking the load balancer work once approved.
+	OperationID string `json:"operation_id,omitempty"`
+	// Result - record returned when the change was released. Private keys
+	// are redacted.
+	Result interface{} `json:"result,omitempty"`
+	// LastError - error that failed the release of the change.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// BindingChange - pool member enabled or disabled by a binding change.
+type BindingChange struct {
+	// Pool - name of the pool.
+	Pool string `json:"pool"`
+	// IP - ip of the pool member.
+	IP string `json:"ip"`
+	// State - binding state to apply.
+	State pool.MemberBinding `json:"state"`
+}
+
+// requestApproval - saves change as a change request when the record it
+// touches has a protected product code, unless oUser is acting on an approved
+// change. p is the payload of a create or modify. pending is false when the
+// request should carry on as normal.
+func (o *Common) requestApproval(change ChangeRequest, p []byte, oUser *userenv.User) (r DbRecord, pending bool, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if o.Database.Table != "virtualservers" || oUser.ApprovedBy != "" || !approvalEnabled() {
+		return r, false, nil
+	}
+	var (
+		request DbRecord
+		current DbRecord
+		codes   []int
+		lookup  error
+	)
+	switch change.Action {
+	case "create", "modify":
+		if json.Unmarshal(p, &request) != nil {
+			return r, false, nil
+		}
+		codes = append(codes, o.formatData(request.Data).ProductCode)
+		if change.Action == "modify" {
+			d := request
+			var exists bool
+			exists, lookup = o.dbRecordExists(&d, oUser)
+			if exists && lookup == nil {
+				current, lookup = o.fetchRecord(d.ID, oUser)
+			}
+		}
+	case "delete", "binding":
+		current, lookup = o.fetchRecord(change.RecordID, oUser)
+	case "migrate":
+		var staged DbRecord
+		staged, lookup = o.FetchStaged(change.RecordID, oUser)
+		var data migrate.Response
+		shared.MarshalInterface(staged.Data, &data)
+		var sourceData virtualserver.Data
+		shared.MarshalInterface(data.Source.VirtualServer, &sourceData)
+		codes = append(codes, sourceData.ProductCode)
+	}
+	if current.ID != "" {
+		codes = append(codes, o.formatData(current.Data).ProductCode)
+	}
+	change.ProductCodes = protectedCodes(codes)
+	if len(change.ProductCodes) == 0 {
+		return r, false, nil
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Check the request as it would be checked now.
+	////////////////////////////////////////////////////////////////////////////
+	r = request
+	for _, code := range codes {
+		err = oUser.HasRight(rightsAction(change.Action), strconv.Itoa(code))
+		if err != nil {
+			r.LastError = err.Error()
+			return r, true, err
+		}
+	}
+	if lookup != nil {
+		r.LastError = lookup.Error()
+		return r, true, lookup
+	}
+	switch change.Action {
+	case "create":
+		err = o.validateFields(&r)
+	case "modify":
+		validated := r
+		err = o.validate(&validated)
+		if err == nil && current.ID == "" {
+			err = fmt.Errorf("%w - no database record found with id %v", ErrNotFound, r.ID)
+		}
+	case "delete", "binding":
+		r = current
+	}
+	if err == nil && (change.Action == "create" || change.Action == "modify") {
+		err = stashKeys(&r, oUser)
+		change.Payload = r
+	}
+	if err != nil {
+		r.LastError = err.Error()
+		return r, true, err
+	}
+	if current.ID != "" {
+		change.RecordID = current.ID
+		change.ETag = ETag(current)
+	}
+	r.ID = change.RecordID
+	////////////////////////////////////////////////////////////////////////////
+	// Save.
+	////////////////////////////////////////////////////////////////////////////
+	change.Route = o.Route
+	change.Status = ApprovalPending
+	change.User = oUser.Username
+	change.Groups = oUser.Group
+	change.Token = oUser.Token
+	change.Submitted = time.Now().UTC().Format(time.RFC3339Nano)
+	r.ApprovalID, err = shared.NewUUID()
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(change)
+		if err == nil {
+			_, err = dao.GlobalDAO.Db.Exec(`INSERT INTO public.approvals (id, data, source, load_balancer_ip, last_modified, last_modified_by) VALUES ($1, $2, $3, $4, current_timestamp, $5)`, r.ApprovalID, string(data), o.Route, r.LoadBalancerIP, change.User)
+		}
+	}
+	if err != nil {
+		r.ApprovalID = ""
+		r.LastError = err.Error()
+		return r, true, err
+	}
+	o.audit("request_approval", nil, &DbRecord{ID: r.ID, LoadBalancerIP: r.LoadBalancerIP, Data: r.Data}, nil, oUser)
+	return r, true, nil
+}
+
+// requestBulkApproval - requestApproval for the item d of a bulk request.
+// Items that fail the check, including those that cannot be serialized, are
+// returned with last_error set and must not be applied.
+func (o *Common) requestBulkApproval(action string, d DbRecord, oUser *userenv.User) (r DbRecord, pending bool, err error) {
+	item, err := json.Marshal(d)
+	if err == nil {
+		r, pending, err = o.requestApproval(ChangeRequest{Action: action}, item, oUser)
+	}
+	if err != nil {
+		if r.ID == "" {
+			r.ID = d.ID
+		}
+		r.LastError = err.Error()
+	}
+	return r, pending, err
+}
+
+// Approve - approves the pending change request with id and releases it
+// through the matching route of targets as the user that requested it. The
+// record returned by the release is returned. The approver must be another
+// user with rights to every protected product code of the change. API tokens
+// cannot approve, and a change requested with a token cannot be approved by an
+// owner of its service account.
+func (o *Common) Approve(id string, targets map[string]*Common, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	change, err := o.review(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if strings.EqualFold(change.User, oUser.Username) {
+		err = fmt.Errorf("%w. a change must be approved by a user other than %s", userenv.ErrNotAuthorized, change.User)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - The approver does not own the service account that requested
+	// the change. Approvers are never service accounts, so the requester
+	// cannot own the approver's.
+	////////////////////////////////////////////////////////////////////////////
+	if change.Token != nil {
+		var owner bool
+		owner, err = ownsServiceAccount(change.Token.ServiceAccount, oUser.Username)
+		if err == nil && owner {
+			err = fmt.Errorf("%w. owners of service account %s cannot approve its changes", userenv.ErrNotAuthorized, change.Token.ServiceAccount)
+		}
+		if err != nil {
+			r.LastError = err.Error()
+			return r, err
+		}
+	}
+	change.Status = ApprovalApproved
+	change.ReviewedBy = oUser.Username
+	change.Reviewed = time.Now().UTC().Format(time.RFC3339Nano)
+	err = updateChangeRequest(id, change, true)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	o.audit("approve", nil, &DbRecord{ID: id, Data: change}, nil, oUser)
+	////////////////////////////////////////////////////////////////////////////
+	// Release.
+	////////////////////////////////////////////////////////////////////////////
+	r, err = release(change, targets[change.Route], oUser.Username)
+	change.OperationID = r.OperationID
+	change.Result = redact(r)
+	if err != nil {
+		change.Status = OperationFailed
+		change.LastError = err.Error()
+		r.LastError = err.Error()
+	}
+	if saveErr := updateChangeRequest(id, change, false); saveErr != nil {
+		o.Log.Warn(saveErr)
+	}
+	return r, err
+}
+
+// Reject - rejects the pending change request with id. Any user with rights
+// to its product codes, including the user that requested it, may reject it.
+// API tokens cannot.
+func (o *Common) Reject(id string, reason string, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	change, err := o.review(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	change.Status = ApprovalRejected
+	change.ReviewedBy = oUser.Username
+	change.Reviewed = time.Now().UTC().Format(time.RFC3339Nano)
+	change.Reason = reason
+	err = updateChangeRequest(id, change, true)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	r = DbRecord{ID: id, Source: change.Route, Data: change}
+	o.audit("reject", nil, &r, nil, oUser)
+	return r, nil
+}
+
+// review - returns the pending change request with id when oUser is not an
+// API token and has rights to every protected product code of it.
+func (o *Common) review(id string, oUser *userenv.User) (r ChangeRequest, err error) {
+	err = oUser.IsHuman()
+	if err != nil {
+		return
+	}
+	d, err := o.fetchRecord(id, oUser)
+	if err != nil {
+		return
+	}
+	err = shared.MarshalInterface(d.Data, &r)
+	if err != nil {
+		return
+	}
+	for _, code := range r.ProductCodes {
+		err = oUser.HasRight(rightsAction(r.Action), strconv.Itoa(code))
+		if err != nil {
+			return
+		}
+	}
+	if r.Status != ApprovalPending {
+		err = fmt.Errorf("%w - %s is %s", ErrNotPending, id, r.Status)
+	}
+	return
+}
+
+// release - submits the approved change through target as the user that
+// requested it.
+func release(change ChangeRequest, target *Common, approver string) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if target == nil {
+		return r, fmt.Errorf("changes to %s cannot be approved", change.Route)
+	}
+	err = activeToken(change.Token)
+	if err != nil {
+		return
+	}
+	oUser := &userenv.User{Username: change.User, Group: change.Groups, Token: change.Token, ApprovedBy: approver}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Record has not changed since the change was requested.
+	////////////////////////////////////////////////////////////////////////////
+	var current DbRecord
+	if change.ETag != "" {
+		current, err = target.fetchRecord(change.RecordID, oUser)
+		if err != nil {
+			return
+		}
+		if ETag(current) != change.ETag {
+			return r, ErrPreconditionFailed
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var p []byte
+	if change.Payload != nil {
+		p, err = unstashKeys(change.Payload, change.RecordID, current)
+		if err != nil {
+			return
+		}
+	}
+	if change.ApplyAt != "" || change.Window != "" {
+		return target.ScheduleChange(change.Action, change.RecordID, p, change.ApplyAt, change.Window, oUser)
+	}
+	switch change.Action {
+	case "create":
+		return target.Create(p, oUser)
+	case "modify":
+		return target.Modify(p, oUser)
+	case "delete":
+		return target.Delete(change.RecordID, oUser)
+	case "migrate":
+		return target.Migrate(change.RecordID, oUser)
+	case "binding":
+		if change.Binding != nil {
+			return target.ModifyBindingState(change.RecordID, change.Binding.Pool, change.Binding.IP, change.Binding.State, oUser)
+		}
+	}
+	return r, fmt.Errorf("%s cannot be approved", change.Action)
+}
+
+// updateChangeRequest - writes change over the change request with id. With
+// pending set only a pending change request is written, so that a change is
+// never reviewed twice.
+func updateChangeRequest(id string, change ChangeRequest, pending bool) (err error) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return
+	}
+	qry := `UPDATE public.approvals SET data=$2, last_modified=current_timestamp, last_modified_by=$3, last_error=$4 WHERE id=$1`
+	args := []interface{}{id, string(data), change.ReviewedBy, change.LastError}
+	if pending {
+		qry += ` AND data->>'status'=$5`
+		args = append(args, ApprovalPending)
+	}
+	result, err := dao.GlobalDAO.Db.Exec(qry, args...)
+	if err != nil {
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 && pending {
+		err = fmt.Errorf("%w - %s has already been reviewed", ErrNotPending, id)
+	}
+	return
+}
+
+// rightsAction - returns the right needed to request or review action. A
+// binding change needs the right to modify the record.
+func rightsAction(action string) string {
+	if action == "binding" {
+		return userenv.ActionModify
+	}
+	return action
+}
+
+// approvalEnabled - returns true when any change needs approval.
+func approvalEnabled() bool {
+	approval := config.GlobalConfig.Approval
+	return len(approval.ProductCodes) > 0 || len(approval.Environments) > 0
+}
+
+// protectedCodes - returns the codes whose changes need approval. Every code
+// is protected in a protected environment.
+func protectedCodes(codes []int) (r []int) {
+	////////////////////////////////////////////////////////////////////////////
+	protected := make(map[int]bool)
+	for _, v := range config.GlobalConfig.Approval.ProductCodes {
+		protected[v] = true
+	}
+	for _, v := range config.GlobalConfig.Approval.Environments {
+		if env := strings.TrimSpace(v); env != "" && strings.EqualFold(env, config.GlobalConfig.Lbm.Environment) {
+			return uniqueCodes(codes)
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var matched []int
+	for _, v := range codes {
+		if protected[v] {
+			matched = append(matched, v)
+		}
+	}
+	return uniqueCodes(matched)
+}
+
+// uniqueCodes - returns the distinct non-zero codes in order.
+func uniqueCodes(codes []int) (r []int) {
+	seen := make(map[int]bool)
+	for _, v := range codes {
+		if v != 0 && !seen[v] {
+			seen[v] = true
+			r = append(r, v)
+		}
+	}
+	return
+}
+
+// pendingApproval - returns true when any of records is waiting for approval.
+func pendingApproval(records []DbRecord) bool {
+	for _, v := range records {
+		if v.ApprovalID != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+func TestUniqueCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []int
+		want  []int
+	}{
+		{"empty", nil, nil},
+		{"zero dropped", []int{0}, nil},
+		{"order kept", []int{3, 1, 2}, []int{3, 1, 2}},
+		{"duplicates dropped", []int{1, 2, 1, 0, 2}, []int{1, 2}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := uniqueCodes(tt.codes); !reflect.DeepEqual(r, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestProtectedCodes(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	tests := []struct {
+		name        string
+		approval    config.Approval
+		environment string
+		codes       []int
+		want        []int
+		enabled     bool
+	}{
+		{"disabled", config.Approval{}, "prod", []int{1, 2}, nil, false},
+		{"protected codes", config.Approval{ProductCodes: []int{2, 3}}, "prod", []int{1, 2, 3, 2}, []int{2, 3}, true},
+		{"no protected code touched", config.Approval{ProductCodes: []int{2}}, "prod", []int{1}, nil, true},
+		{"protected environment", config.Approval{Environments: []string{" Prod "}}, "prod", []int{1, 0, 1}, []int{1}, true},
+		{"other environment", config.Approval{Environments: []string{"prod"}}, "dev", []int{1}, nil, true},
+		{"blank environment", config.Approval{Environments: []string{""}}, "", []int{1}, nil, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Approval: tt.approval, Lbm: config.Lbm{Environment: tt.environment}}
+			if r := protectedCodes(tt.codes); !reflect.DeepEqual(r, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+			if approvalEnabled() != tt.enabled {
+				t.Fatalf("expected enabled %v, got %v", tt.enabled, approvalEnabled())
+			}
+		})
+	}
+}
+
+func TestPendingApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []DbRecord
+		want    bool
+	}{
+		{"none", nil, false},
+		{"applied", []DbRecord{{ID: "a"}}, false},
+		{"pending", []DbRecord{{ID: "a"}, {ID: "b", ApprovalID: "c"}}, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := pendingApproval(tt.records); r != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestRequestBulkApprovalMarshalError(t *testing.T) {
+	o := New()
+	r, pending, err := o.requestBulkApproval("create", DbRecord{ID: "a", Data: make(chan int)}, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if pending {
+		t.Fatal("expected the item not to be pending")
+	}
+	if r.ID != "a" || r.LastError != err.Error() {
+		t.Fatalf("expected item a to carry %v, got %+v", err, r)
+	}
+}
+
+func TestRightsAction(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{"create", userenv.ActionCreate},
+		{"modify", userenv.ActionModify},
+		{"delete", userenv.ActionDelete},
+		{"migrate", userenv.ActionMigrate},
+		{"binding", userenv.ActionModify},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if r := rightsAction(tt.action); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// redacted - replaces secrets in audit documents.
+const redacted = "[redacted]"
+
+// redactedFields - fields holding private keys or their passphrases.
+var redactedFields = map[string]bool{
+	"private_key":    true,
+	"passphrase":     true,
+	"key_passphrase": true,
+}
+
+// AuditEntry - data of an audit record. Audit records are written once for
+// every create, modify, delete, migrate, import and backup request and are
+// never updated.
+type AuditEntry struct {
+	// Action - create, modify, delete, migrate, import, backup, purge,
+	// request_approval, approve or reject.
+	Action string `json:"action"`
+	// Route - route the request was made against.
+	Route string `json:"route"`
+	// RecordID - id of the record. Empty for import and backup.
+	RecordID string `json:"record_id,omitempty"`
+	// ProductCode - product code of the record.
+	ProductCode int `json:"product_code,omitempty"`
+	// User - user that made the request.
+	User string `json:"user"`
+	// SourceIP - address the request came from.
+	SourceIP string `json:"source_ip,omitempty"`
+	// Before - data of the record before the request. Private keys are
+	// redacted.
+	Before interface{} `json:"before,omitempty"`
+	// After - data of the record returned by the request. Private keys are
+	// redacted.
+	After interface{} `json:"after,omitempty"`
+	// Diff - fields that differ between before and after.
+	Diff []AuditChange `json:"diff,omitempty"`
+	// Count - number of records imported, backed up or purged.
+	Count int `json:"count,omitempty"`
+	// LastError - error returned by the request.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// AuditChange - single field of an audit diff.
+type AuditChange struct {
+	// Path - path of the field, e.g. pools[0].bindings[1].server.ip.
+	Path string `json:"path"`
+	// Op - add, remove or replace.
+	Op string `json:"op"`
+	// From - value before the request.
+	From interface{} `json:"from,omitempty"`
+	// To - value after the request.
+	To interface{} `json:"to,omitempty"`
+}
+
+// audit - writes the audit record of a request. before and after may be nil.
+// Failures to write the record are logged and never fail the request. Writes
+// to the status and migrate tables are covered by the records they belong to.
+func (o *Common) audit(action string, before *DbRecord, after *DbRecord, err error, oUser *userenv.User) {
+	if o.Database.Table == "status" || o.Database.Table == "migrate" {
+		return
+	}
+	var loadBalancerIP string
+	for _, d := range []*DbRecord{before, after} {
+		if d != nil && d.LoadBalancerIP != "" {
+			loadBalancerIP = d.LoadBalancerIP
+		}
+	}
+	o.writeAudit(o.newAuditEntry(action, before, after, err, oUser), loadBalancerIP)
+	////////////////////////////////////////////////////////////////////////////
+	// Keys redacted above are kept in secure storage for rollbacks.
+	////////////////////////////////////////////////////////////////////////////
+	if err == nil && after != nil && after.LastError == "" {
+		o.keepSecrets(after, oUser)
+	}
+}
+
+// auditBulk - writes an audit record for each of records, using befores to
+// look up the stored records by id. A single record carrying err is written
+// when the request failed before any record was processed. Records waiting for
+// approval were audited when the change request was saved.
+func (o *Common) auditBulk(action string, befores map[string]*DbRecord, records []DbRecord, err error, oUser *userenv.User) {
+	if len(records) == 0 {
+		o.audit(action, nil, nil, err, oUser)
+		return
+	}
+	for k := range records {
+		if records[k].ApprovalID != "" {
+			continue
+		}
+		o.audit(action, befores[records[k].ID], &records[k], nil, oUser)
+	}
+}
+
+// auditJob - writes the audit record of an import, backup or purge covering
+// count records.
+func (o *Common) auditJob(action string, count int, err error, oUser *userenv.User) {
+	e := o.newAuditEntry(action, nil, nil, err, oUser)
+	e.Count = count
+	o.writeAudit(e, "")
+}
+
+// auditRecord - returns the stored record with id, or nil if there is none.
+func (o *Common) auditRecord(id string, oUser *userenv.User) *DbRecord {
+	if id == "" {
+		return nil
+	}
+	collection, err := o.FetchByID(id, oUser)
+	if err != nil || len(collection.DbRecords) == 0 {
+		return nil
+	}
+	return &collection.DbRecords[0]
+}
+
+// newAuditEntry - describes a request.
+func (o *Common) newAuditEntry(action string, before *DbRecord, after *DbRecord, err error, oUser *userenv.User) (r AuditEntry) {
+	////////////////////////////////////////////////////////////////////////////
+	r = AuditEntry{Action: action, Route: o.Route}
+	if oUser != nil {
+		r.User = oUser.Username
+		if oUser.Context != nil {
+			r.SourceIP = oUser.Context.ClientIP()
+		}
+	}
+	if err != nil {
+		r.LastError = err.Error()
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, d := range []*DbRecord{before, after} {
+		if d == nil {
+			continue
+		}
+		if d.ID != "" {
+			r.RecordID = d.ID
+		}
+		var data Data
+		if shared.MarshalInterface(d.Data, &data) == nil && data.ProductCode != 0 {
+			r.ProductCode = data.ProductCode
+		}
+		if r.LastError == "" {
+			r.LastError = d.LastError
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if before != nil {
+		r.Before = redact(before.Data)
+	}
+	if after != nil {
+		r.After = redact(after.Data)
+	}
+	if before != nil || after != nil {
+		r.Diff = diffJSON("", r.Before, r.After, nil)
+	}
+	return
+}
+
+// writeAudit - inserts e into the audit table. The table rejects updates and
+// deletes.
+func (o *Common) writeAudit(e AuditEntry, loadBalancerIP string) {
+	////////////////////////////////////////////////////////////////////////////
+	log := o.Log
+	if log == nil {
+		log = logrus.NewEntry(logrus.New())
+	}
+	log = log.WithFields(logrus.Fields{"user": e.User, "handler": "audit", "action": e.Action})
+	////////////////////////////////////////////////////////////////////////////
+	id, err := shared.NewUUID()
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	p, err := json.Marshal(e)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`INSERT INTO public.audit (id, data, source, load_balancer_ip, last_modified, last_error, last_modified_by) VALUES ($1, $2, $3, $4, current_timestamp, $5, $6)`, id, string(p), o.Route, loadBalancerIP, e.LastError, e.User)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+// redact - returns v as generic json with every private key and passphrase
+// replaced.
+func redact(v interface{}) (r interface{}) {
+	p, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	if json.Unmarshal(p, &r) != nil {
+		return nil
+	}
+	return redactValue("", r)
+}
+
+// redactValue - redacts v, found under the field named key.
+func redactValue(key string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = redactValue(k, val)
+		}
+		return t
+	case []interface{}:
+		for k, val := range t {
+			t[k] = redactValue(key, val)
+		}
+		return t
+	case string:
+		if t != "" && (redactedFields[key] || key == "key") {
+			return redacted
+		}
+	}
+	return v
+}
+
+// diffJSON - appends the changes between the generic json values a and b to
+// r. Objects are compared by field and arrays by index.
+func diffJSON(path string, a interface{}, b interface{}, r []AuditChange) []AuditChange {
+	////////////////////////////////////////////////////////////////////////////
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if (aIsMap || a == nil) && (bIsMap || b == nil) && (aIsMap || bIsMap) {
+		keys := make(map[string]bool)
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range bm {
+			keys[k] = true
+		}
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			r = diffJSON(shared.JSONPath(path, k), am[k], bm[k], r)
+		}
+		return r
+	}
+	////////////////////////////////////////////////////////////////////////////
+	as, aIsSlice := a.([]interface{})
+	bs, bIsSlice := b.([]interface{})
+	if aIsSlice && bIsSlice {
+		n := len(as)
+		if len(bs) > n {
+			n = len(bs)
+		}
+		for i := 0; i < n; i++ {
+			var av, bv interface{}
+			if i < len(as) {
+				av = as[i]
+			}
+			if i < len(bs) {
+				bv = bs[i]
+			}
+			r = diffJSON(shared.JSONPath(path, i), av, bv, r)
+		}
+		return r
+	}
+	////////////////////////////////////////////////////////////////////////////
+	switch {
+	case reflect.DeepEqual(a, b):
+	case a == nil:
+		r = append(r, AuditChange{Path: path, Op: "add", To: b})
+	case b == nil:
+		r = append(r, AuditChange{Path: path, Op: "remove", From: a})
+	default:
+		r = append(r, AuditChange{Path: path, Op: "replace", From: a, To: b})
+	}
+	return r
+}
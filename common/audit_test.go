@@ -0,0 +1,69 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// generic - decodes s into generic json.
+func generic(t *testing.T, s string) (r interface{}) {
+	if err := json.Unmarshal([]byte(s), &r); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain fields", `{"name":"vip","port":80}`, `{"name":"vip","port":80}`},
+		{"private key", `{"private_key":"pem"}`, `{"private_key":"[redacted]"}`},
+		{"empty key is kept", `{"private_key":""}`, `{"private_key":""}`},
+		{"passphrases", `{"passphrase":"a","key_passphrase":"b"}`, `{"passphrase":"[redacted]","key_passphrase":"[redacted]"}`},
+		{"nested key", `{"certificates":[{"key":"pem","name":"cert"}]}`, `{"certificates":[{"key":"[redacted]","name":"cert"}]}`},
+		{"key object", `{"key":{"private_key":"pem"}}`, `{"key":{"private_key":"[redacted]"}}`},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := redact(generic(t, tt.in))
+			if want := generic(t, tt.want); !reflect.DeepEqual(r, want) {
+				t.Fatalf("expected %v, got %v", want, r)
+			}
+		})
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []AuditChange
+	}{
+		{"equal", `{"name":"vip"}`, `{"name":"vip"}`, nil},
+		{"replace", `{"port":80}`, `{"port":443}`, []AuditChange{{Path: "port", Op: "replace", From: 80.0, To: 443.0}}},
+		{"add", `{}`, `{"name":"vip"}`, []AuditChange{{Path: "name", Op: "add", To: "vip"}}},
+		{"remove", `{"name":"vip"}`, `{}`, []AuditChange{{Path: "name", Op: "remove", From: "vip"}}},
+		{"created", `null`, `{"name":"vip"}`, []AuditChange{{Path: "name", Op: "add", To: "vip"}}},
+		{"sorted fields", `{"b":1,"a":1}`, `{"b":2,"a":2}`, []AuditChange{{Path: "a", Op: "replace", From: 1.0, To: 2.0}, {Path: "b", Op: "replace", From: 1.0, To: 2.0}}},
+		{"nested array", `{"pools":[{"ip":"10.0.0.1"}]}`, `{"pools":[{"ip":"10.0.0.2"},{"ip":"10.0.0.3"}]}`, []AuditChange{
+			{Path: "pools[0].ip", Op: "replace", From: "10.0.0.1", To: "10.0.0.2"},
+			{Path: "pools[1].ip", Op: "add", To: "10.0.0.3"},
+		}},
+		{"type change", `{"ports":[80]}`, `{"ports":80}`, []AuditChange{{Path: "ports", Op: "replace", From: []interface{}{80.0}, To: 80.0}}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := diffJSON("", generic(t, tt.a), generic(t, tt.b), nil)
+			if !reflect.DeepEqual(r, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, r)
+			}
+		})
+	}
+}
@@ -12,8 +12,21 @@ func (o *Common) Backup(oUser *userenv.User) (err error) {
 	////////////////////////////////////////////////////////////////////////////
 	o.Log.Print(fmt.Sprintf("backing up data: %s", o.Route))
 	////////////////////////////////////////////////////////////////////////////
+	// Every record is backed up, so the right must hold for every product
+	// code.
+	////////////////////////////////////////////////////////////////////////////
+	if _, all := oUser.Codes(userenv.ActionBackup); !all {
+		err = fmt.Errorf("%w. backup needs a role allowing it for every product code", userenv.ErrNotAuthorized)
+		o.auditJob("backup", 0, err, oUser)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
 	m := make(map[string][]string)
 	r, err := o.Fetch(m, 0, oUser)
+	defer func() {
+		o.publishCompletion("backup_completed", len(r.DbRecords), err, oUser)
+		o.auditJob("backup", len(r.DbRecords), err, oUser)
+	}()
 	if err != nil {
 		return
 	}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/sdkfork"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// ModifyBindingState - enables or disables the members of a single pool that
+// point at ip. Only the pool member is changed on the load balancer; the rest
+// of the virtual server is left alone. The stored record is updated with the
+// new binding state. Changes to records with a protected product code are
+// held for approval.
+func (o *Common) ModifyBindingState(id string, poolName string, ip string, state pool.MemberBinding, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	log := logrus.NewEntry(logrus.New())
+	*log = *o.Log
+	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "binding", "route": o.Route})
+	////////////////////////////////////////////////////////////////////////////
+	if !o.ModifyLb {
+		err = fmt.Errorf("%s does not support binding state changes", o.Route)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Hold changes to protected product codes for approval.
+	////////////////////////////////////////////////////////////////////////////
+	change := ChangeRequest{Action: "binding", RecordID: id, Binding: &BindingChange{Pool: poolName, IP: ip, State: state}}
+	if d, pending, err := o.requestApproval(change, nil, oUser); pending || err != nil {
+		return d, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Get Record By Id. This operation queries the system db.
+	////////////////////////////////////////////////////////////////////////////
+	collection, err := o.FetchByID(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if len(collection.DbRecords) == 0 {
+		err = fmt.Errorf("%w - no records match %s", ErrNotFound, id)
+		r.LastError = err.Error()
+		return r, err
+	}
+	r = collection.DbRecords[0]
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right.
+	////////////////////////////////////////////////////////////////////////////
+	var data Data
+	shared.MarshalInterface(r.Data, &data)
+	err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(data.ProductCode))
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Validate record has not changed since the client fetched it.
+	////////////////////////////////////////////////////////////////////////////
+	ifMatch, err := o.checkIfMatch(r.ID, oUser)
+	var claim string
+	if err == nil {
+		claim, err = o.claimIfMatch(r.ID, ifMatch, oUser)
+	}
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Track progress of load balancer changes.
+	////////////////////////////////////////////////////////////////////////////
+	action := "binding disable"
+	if state.Enabled {
+		action = "binding enable"
+	}
+	op := o.newOperation(action, &r, oUser)
+	r.OperationID = op.OperationID()
+	defer func() {
+		if err != nil {
+			op.Finish(nil, err)
+			return
+		}
+		op.Finish(r, nil)
+	}()
+	err = o.applyBindingState(&r, poolName, ip, state, oUser, op, log)
+	if err != nil {
+		o.releaseIfMatch(r.ID, ifMatch, claim)
+		r.LastError = err.Error()
+		return r, err
+	}
+	return r, nil
+}
+
+// applyBindingState - changes the pool member on the load balancer and
+// writes the new binding state to the database.
+func (o *Common) applyBindingState(r *DbRecord, poolName string, ip string, state pool.MemberBinding, oUser *userenv.User, t shared.Tracker, log *logrus.Entry) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	// Set target.
+	////////////////////////////////////////////////////////////////////////////
+	sdkTarget := &sdkfork.SdkTarget{Address: r.LoadBalancerIP, Mfr: GlobalSources.Clusters[r.LoadBalancerIP].Mfr}
+	sdkConf := &sdkfork.SdkConf{
+		Target:  sdkTarget,
+		Log:     log,
+		Tracker: t,
+	}
+	sdk := sdkfork.New(sdkConf)
+	////////////////////////////////////////////////////////////////////////////
+	// Modify pool member.
+	////////////////////////////////////////////////////////////////////////////
+	done := shared.Track(t, "pool modify")
+	modified, err := sdk.ModifyBindingState(r.Data, poolName, ip, state, o.Route)
+	done(err)
+	if err != nil {
+		return err
+	}
+	if modified == nil {
+		return errors.New("error modifying binding on load balancer")
+	}
+	r.Data = modified
+	////////////////////////////////////////////////////////////////////////////
+	// Write record.
+	////////////////////////////////////////////////////////////////////////////
+	conf := &ModifyConf{
+		DbRecord: r,
+		User:     oUser,
+		Log:      log,
+	}
+	qry := o.etlDbRecordUpdate(conf)
+	done = shared.Track(t, "db write")
+	err = o.updateDbRecord(qry, conf)
+	done(err)
+	return err
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// BulkSkipped - status of the items that were not processed because another
+// item of an atomic request failed.
+const BulkSkipped = "skipped"
+
+// ErrBulkAborted - returned when an item of an atomic bulk request fails its
+// checks. No item is processed.
+var ErrBulkAborted = errors.New("bulk request aborted - no changes were made")
+
+// BulkRecord - response of the bulk routes.
+type BulkRecord struct {
+	// Results - outcome of every item, in request order.
+	Results []BulkResult `json:"results"`
+	// LastError - reason the request was aborted.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// BulkResult - outcome of one item of a bulk request.
+type BulkResult struct {
+	// ID - id of the record.
+	ID string `json:"id"`
+	// Status - running, complete, fail, skipped or pending.
+	Status string `json:"status"`
+	// OperationID - operation tracking the item when it completes in the background.
+	OperationID string `json:"_operation_id,omitempty"`
+	// ApprovalID - change request holding the item until a second user
+	// approves it.
+	ApprovalID string `json:"_approval_id,omitempty"`
+	// LastError - error returned for the item.
+	LastError string `json:"last_error,omitempty"`
+	// Errors - invalid fields of the item.
+	Errors []shared.FieldError `json:"errors,omitempty"`
+}
+
+// newBulkResult - converts the record returned for an item to its result.
+func newBulkResult(d DbRecord, err error) (r BulkResult) {
+	r = BulkResult{ID: d.ID, Status: OperationComplete, OperationID: d.OperationID, ApprovalID: d.ApprovalID, LastError: d.LastError}
+	if err != nil {
+		r.LastError = err.Error()
+	}
+	var validationErr *shared.ValidationError
+	if errors.As(err, &validationErr) {
+		r.Errors = validationErr.Fields
+	}
+	switch {
+	case r.LastError != "":
+		r.Status = OperationFailed
+	case r.OperationID != "":
+		r.Status = OperationRunning
+	case r.ApprovalID != "":
+		r.Status = ApprovalPending
+	}
+	return
+}
+
+// abortBulk - marks every item without an error as skipped and returns
+// ErrBulkAborted when any item failed.
+func abortBulk(r *BulkRecord) (err error) {
+	failed := 0
+	for k := range r.Results {
+		if r.Results[k].Status == OperationFailed {
+			failed++
+			continue
+		}
+		r.Results[k].Status = BulkSkipped
+	}
+	if failed == 0 {
+		return nil
+	}
+	err = fmt.Errorf("%w - %d of %d items failed", ErrBulkAborted, failed, len(r.Results))
+	r.LastError = err.Error()
+	return
+}
+
+// precheckModifyBulk - runs the checks of ModifyBulk on every record before
+// any record is changed: rights, validation and existence in the database.
+func (o *Common) precheckModifyBulk(dbRecords []DbRecord, oUser *userenv.User) (r BulkRecord, err error) {
+	for _, d := range dbRecords {
+		record := d
+		r.Results = append(r.Results, newBulkResult(record, o.precheckModify(&record, oUser)))
+	}
+	if err = abortBulk(&r); err == nil {
+		r = BulkRecord{}
+	}
+	return
+}
+
+// precheckModify - checks a single record of ModifyBulk.
+func (o *Common) precheckModify(d *DbRecord, oUser *userenv.User) (err error) {
+	var genericData Data
+	err = shared.MarshalInterface(d.Data, &genericData)
+	if err != nil {
+		return
+	}
+	err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(genericData.ProductCode))
+	if err != nil {
+		return
+	}
+	err = o.validate(d)
+	if err != nil {
+		return
+	}
+	databaseRecord := &DbRecord{ID: d.ID, Data: d.Data, LoadBalancerIP: d.LoadBalancerIP}
+	exists, err := o.dbRecordExists(databaseRecord, oUser)
+	if err != nil {
+		return
+	}
+	if !exists {
+		return fmt.Errorf("%w - no database record found with id %v", ErrNotFound, d.ID)
+	}
+	d.ID = databaseRecord.ID
+	return
+}
+
+// DeleteBulk - deletes every record in p, a json array of ids. When atomic is
+// set, every id is fetched and its rights checked before any record is
+// deleted, and the request is aborted if one fails.
+func (o *Common) DeleteBulk(p []byte, atomic bool, oUser *userenv.User) (r BulkRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	log := logrus.NewEntry(logrus.New())
+	*log = *o.Log
+	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "delete", "route": o.Route})
+	////////////////////////////////////////////////////////////////////////////
+	var ids []string
+	err = json.Unmarshal(p, &ids)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Every record can be deleted before any record is deleted.
+	////////////////////////////////////////////////////////////////////////////
+	if atomic {
+		for _, id := range ids {
+			d, err := o.deletable(id, oUser)
+			if d.ID == "" {
+				d.ID = id
+			}
+			r.Results = append(r.Results, newBulkResult(d, err))
+		}
+		err = abortBulk(&r)
+		if err != nil {
+			return
+		}
+		r = BulkRecord{}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, id := range ids {
+		d, err := o.Delete(id, oUser)
+		if err != nil {
+			log.Warn(err)
+		}
+		if d.ID == "" {
+			d.ID = id
+		}
+		r.Results = append(r.Results, newBulkResult(d, err))
+	}
+	return r, nil
+}
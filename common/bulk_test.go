@@ -0,0 +1,78 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+func TestNewBulkResult(t *testing.T) {
+	validationErr := shared.NewValidationError([]shared.FieldError{{Path: "data.name", Reason: "required"}})
+	tests := []struct {
+		name   string
+		record DbRecord
+		err    error
+		status string
+		errors int
+	}{
+		{"complete", DbRecord{ID: "a"}, nil, OperationComplete, 0},
+		{"running", DbRecord{ID: "a", OperationID: "op"}, nil, OperationRunning, 0},
+		{"failed", DbRecord{ID: "a"}, errors.New("lb unreachable"), OperationFailed, 0},
+		{"failed record", DbRecord{ID: "a", OperationID: "op", LastError: "lb unreachable"}, nil, OperationFailed, 0},
+		{"invalid", DbRecord{ID: "a"}, fmt.Errorf("item 1 - %w", validationErr), OperationFailed, 1},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newBulkResult(tt.record, tt.err)
+			if r.ID != tt.record.ID {
+				t.Fatalf("expected id %s, got %s", tt.record.ID, r.ID)
+			}
+			if r.Status != tt.status {
+				t.Fatalf("expected status %s, got %s", tt.status, r.Status)
+			}
+			if len(r.Errors) != tt.errors {
+				t.Fatalf("expected %d field errors, got %+v", tt.errors, r.Errors)
+			}
+			if tt.status == OperationFailed && r.LastError == "" {
+				t.Fatal("expected last error")
+			}
+		})
+	}
+}
+
+func TestAbortBulk(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     []string
+		aborted  bool
+	}{
+		{"nothing failed", []string{OperationComplete, OperationRunning}, []string{BulkSkipped, BulkSkipped}, false},
+		{"one failed", []string{OperationComplete, OperationFailed, OperationRunning}, []string{BulkSkipped, OperationFailed, BulkSkipped}, true},
+		{"empty", nil, nil, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r BulkRecord
+			for _, v := range tt.statuses {
+				r.Results = append(r.Results, BulkResult{Status: v})
+			}
+			err := abortBulk(&r)
+			if errors.Is(err, ErrBulkAborted) != tt.aborted {
+				t.Fatalf("expected aborted %v, got %v", tt.aborted, err)
+			}
+			if tt.aborted && r.LastError == "" {
+				t.Fatal("expected last error")
+			}
+			for k, v := range tt.want {
+				if r.Results[k].Status != v {
+					t.Fatalf("expected item %d to be %s, got %s", k, v, r.Results[k].Status)
+				}
+			}
+		})
+	}
+}
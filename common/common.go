@@ -2,8 +2,6 @@ package common
 
 import (
 	"errors"
-	"fmt"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/ticketmaster/lbapi/config"
@@ -27,7 +25,7 @@ func New() *Common {
 // dbRecordExists determines if payload matches database record.
 func (o *Common) dbRecordExists(dbRecord *DbRecord, oUser *userenv.User) (r bool, err error) {
 	if dbRecord.ID == "" {
-		r, err := o.SetPrimaryKey(dbRecord)
+		r, err := o.aliasKey(dbRecord)
 		if err != nil {
 			return false, err
 		}
@@ -40,6 +38,10 @@ func (o *Common) dbRecordExists(dbRecord *DbRecord, oUser *userenv.User) (r bool
 	if len(resp.DbRecords) == 0 {
 		return false, nil
 	}
+	////////////////////////////////////////////////////////////////////////////
+	// Aliases resolve to the record id.
+	////////////////////////////////////////////////////////////////////////////
+	dbRecord.ID = resp.DbRecords[0].ID
 
 	if o.Database.Table == "virtualservers" && resp.DbRecords[0].Status == "migrated" {
 		err := errors.New("record was migrated to Avi. updates are not allowed")
@@ -61,27 +63,18 @@ func (o *Common) GetRoute() string {
 	return o.Route
 }
 
-// SetPrimaryKey - pattern for creating database primary keys.
+// SetPrimaryKey - pattern for creating database primary keys. Virtual servers
+// get a random uuid that is kept for the life of the record; every other
+// table is keyed by aliasKey.
 func (o *Common) SetPrimaryKey(d *DbRecord) (r string, err error) {
-	var key string
-	var data Data
-	err = shared.MarshalInterface(d.Data, &data)
+	if o.Database.Table == "virtualservers" {
+		r, err = shared.NewUUID()
+	} else {
+		r, err = o.aliasKey(d)
+	}
 	if err != nil {
 		return "", err
 	}
-	switch o.Database.Table {
-	case "loadbalancers":
-		key = fmt.Sprintf("%s", d.LoadBalancerIP)
-	case "virtualservers":
-		portsEnc, err := shared.EncodePorts(data.Ports)
-		if err != nil {
-			return "", err
-		}
-		key = fmt.Sprintf("%s:%s-%s", data.IP, portsEnc, d.LoadBalancerIP)
-	default:
-		key = data.SourceUUID
-	}
-	r = shared.GetMD5Hash(strings.TrimSpace(key))
 	d.ID = r
 	return r, nil
 }
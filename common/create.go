@@ -41,6 +41,15 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	*log = *o.Log
 	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "create", "route": o.Route})
 	////////////////////////////////////////////////////////////////////////////
+	// Protected product codes wait for the approval of a second user.
+	////////////////////////////////////////////////////////////////////////////
+	if d, pending, err := o.requestApproval(ChangeRequest{Action: "create"}, p, oUser); pending || err != nil {
+		return d, err
+	}
+	defer func() {
+		o.audit("create", nil, &r, err, oUser)
+	}()
+	////////////////////////////////////////////////////////////////////////////
 	// Unmarshal payload to []DbRecord.
 	////////////////////////////////////////////////////////////////////////////
 	var clientDbRecord DbRecord
@@ -60,18 +69,38 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	////////////////////////////////////////////////////////////////////////
 	// Validate Right.
 	////////////////////////////////////////////////////////////////////////
-	err = oUser.HasAdminRight(strconv.Itoa(genericData.ProductCode))
+	err = oUser.HasRight(userenv.ActionCreate, strconv.Itoa(genericData.ProductCode))
 	if err != nil {
 		clientDbRecord.LastError = err.Error()
 		return clientDbRecord, err
 	}
+	////////////////////////////////////////////////////////////////////////
+	// Test - Validate payload before any changes are made. Virtual servers
+	// are checked again once the ip and load balancer are assigned.
+	////////////////////////////////////////////////////////////////////////
+	if o.ModifyLb {
+		err = o.validateFields(&clientDbRecord)
+	} else {
+		err = o.validate(&clientDbRecord)
+	}
+	if err != nil {
+		clientDbRecord.LastError = err.Error()
+		return clientDbRecord, err
+	}
+	////////////////////////////////////////////////////////////////////////
+	// Track progress of load balancer changes.
+	////////////////////////////////////////////////////////////////////////
+	var op *Operation
 	if o.ModifyLb {
+		op = o.newOperation("create", &clientDbRecord, oUser)
+		clientDbRecord.OperationID = op.OperationID()
 		////////////////////////////////////////////////////////////////////
 		var vsData virtualserver.Data
 		////////////////////////////////////////////////////////////////////
 		err = shared.MarshalInterface(clientDbRecord.Data, &vsData)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////
@@ -80,14 +109,16 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		err = o.CheckIPExists(&clientDbRecord, &vsData)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////
 		// Set IP address if not predefined by the client.
 		////////////////////////////////////////////////////////////////////
-		err = o.setIP(&clientDbRecord, &vsData)
+		err = o.setIP(&clientDbRecord, &vsData, op)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////////
@@ -96,12 +127,14 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		err = o.setLoadBalancer(&clientDbRecord, &vsData)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////
 		vsData.Name = shared.SetName(vsData.ProductCode, vsData.Name)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////
@@ -115,6 +148,17 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		_, err = o.SetPrimaryKey(&clientDbRecord)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
+			return clientDbRecord, err
+		}
+		op.SetRecord(&clientDbRecord)
+		////////////////////////////////////////////////////////////////////
+		// Test - Validate payload meets min requirements for submission.
+		////////////////////////////////////////////////////////////////////
+		err = o.validate(&clientDbRecord)
+		if err != nil {
+			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 		////////////////////////////////////////////////////////////////////
@@ -126,33 +170,22 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 			clientDbRecord.StatusID = 1
 			clientDbRecord.Status = Status[int(clientDbRecord.StatusID)]
 			clientDbRecord.LastError = err.Error()
+			op.Finish(nil, err)
 			return clientDbRecord, err
 		}
 	}
 	////////////////////////////////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////////
-	// Test - Validate payload meets min requirements for submission.
-	////////////////////////////////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////////
 	go func(clientDbRecord *DbRecord, o *Common, oUser *userenv.User) {
-		validated, err := o.Database.Validate(clientDbRecord)
-		if err != nil {
-			clientDbRecord.LastError = err.Error()
-			statusErr := o.setStatusDbRecord(clientDbRecord, 1, oUser)
-			if statusErr != nil {
-				log.Warn(err)
-			}
-			return
-		}
-		if !validated {
-			err = fmt.Errorf("create payload did not pass validation - %+v", clientDbRecord.Data)
-			clientDbRecord.LastError = err.Error()
-			statusErr := o.setStatusDbRecord(clientDbRecord, 1, oUser)
-			if statusErr != nil {
-				log.Warn(err)
+		////////////////////////////////////////////////////////////////////////
+		// Every failure below records last_error before returning.
+		////////////////////////////////////////////////////////////////////////
+		defer func() {
+			if clientDbRecord.LastError != "" {
+				op.Finish(nil, errors.New(clientDbRecord.LastError))
+				return
 			}
-			return
-		}
+			op.Finish(clientDbRecord, nil)
+		}()
 		////////////////////////////////////////////////////////////////////////
 		// Test - Record exists in lb.
 		////////////////////////////////////////////////////////////////////////
@@ -167,8 +200,9 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 				Mfr:     GlobalSources.Clusters[clientDbRecord.LoadBalancerIP].Mfr,
 			}
 			sdkForkConf := &sdkfork.SdkConf{
-				Target: sdkTarget,
-				Log:    log,
+				Target:  sdkTarget,
+				Log:     log,
+				Tracker: op,
 			}
 			sdk := sdkfork.New(sdkForkConf)
 			////////////////////////////////////////////////////////////////////
@@ -265,10 +299,13 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		////////////////////////////////////////////////////////////////////////
 		// Set DbData.
 		////////////////////////////////////////////////////////////////////////
+		done := shared.Track(op, "db write")
 		qry, id := o.etlDbRecordAdd(clientDbRecord, nil, oUser)
 		////////////////////////////////////////////////////////////////////////
 		err = o.setStatusDbRecord(clientDbRecord, 0, oUser)
 		if err != nil {
+			clientDbRecord.LastError = err.Error()
+			done(err)
 			return
 		}
 		////////////////////////////////////////////////////////////////////////////
@@ -278,9 +315,15 @@ func (o *Common) Create(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		toDb[id] = qry
 
 		err = o.addDbRecord(toDb, nil)
+		done(err)
 		if err != nil {
+			clientDbRecord.LastError = err.Error()
 			return
 		}
+		o.publishRecord("created", clientDbRecord, oUser)
+		if o.Created != nil {
+			o.Created(clientDbRecord, oUser)
+		}
 	}(&clientDbRecord, o, oUser)
 	clientDbRecord.Status = Status[int(clientDbRecord.StatusID)]
 	return clientDbRecord, nil
@@ -292,6 +335,9 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 	log := logrus.NewEntry(logrus.New())
 	*log = *o.Log
 	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "create", "route": o.Route})
+	defer func() {
+		o.auditBulk("create", nil, r.DbRecords, err, oUser)
+	}()
 	////////////////////////////////////////////////////////////////////////////
 	// Unmarshal payload to []DbRecord.
 	////////////////////////////////////////////////////////////////////////////
@@ -301,10 +347,27 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 		return
 	}
 	////////////////////////////////////////////////////////////////////////////
+	// Test - Validate every record before any changes are made.
+	////////////////////////////////////////////////////////////////////////////
+	err = o.validateBulkFields(dbRecords)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
 	// Collect records for submission to Database.
 	////////////////////////////////////////////////////////////////////////////
 	toDb := make(map[string]string)
 	for _, d := range dbRecords {
+		////////////////////////////////////////////////////////////////////////
+		// Protected product codes wait for the approval of a second user.
+		////////////////////////////////////////////////////////////////////////
+		if approval, pending, err := o.requestBulkApproval("create", d, oUser); pending || err != nil {
+			if err != nil {
+				log.Warn(err)
+			}
+			r.DbRecords = append(r.DbRecords, approval)
+			continue
+		}
 		////////////////////////////////////////////////////////////////////////
 		// Set dbRecord pointer.
 		////////////////////////////////////////////////////////////////////////
@@ -329,7 +392,7 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 		////////////////////////////////////////////////////////////////////////
 		// Validate Right.
 		////////////////////////////////////////////////////////////////////////
-		err = oUser.HasAdminRight(strconv.Itoa(genericData.ProductCode))
+		err = oUser.HasRight(userenv.ActionCreate, strconv.Itoa(genericData.ProductCode))
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *clientDbRecord)
@@ -360,7 +423,7 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 			////////////////////////////////////////////////////////////////////
 			// Set IP address if not predefined by the client.
 			////////////////////////////////////////////////////////////////////
-			err = o.setIP(clientDbRecord, &vsData)
+			err = o.setIP(clientDbRecord, &vsData, nil)
 			if err != nil {
 				log.Warn(err)
 				clientDbRecord.LastError = err.Error()
@@ -402,7 +465,7 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 		////////////////////////////////////////////////////////////////////////
 		// Test - Validate payload meets min requirements for submission.
 		////////////////////////////////////////////////////////////////////////
-		validated, err := o.Database.Validate(clientDbRecord)
+		err = o.validate(clientDbRecord)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *clientDbRecord)
@@ -414,18 +477,6 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 			}
 			continue
 		}
-		if !validated {
-			err = fmt.Errorf("create payload did not pass validation - %+v", clientDbRecord.Data)
-			clientDbRecord.LastError = err.Error()
-			r.DbRecords = append(r.DbRecords, *clientDbRecord)
-			log.Warn(err)
-
-			err = o.setStatusDbRecord(clientDbRecord, 1, oUser)
-			if err != nil {
-				log.Warn(err)
-			}
-			continue
-		}
 		////////////////////////////////////////////////////////////////////////
 		// Test - Record exists in lb.
 		////////////////////////////////////////////////////////////////////////
@@ -569,17 +620,25 @@ func (o *Common) CreateBulk(p []byte, oUser *userenv.User) (r DbRecordCollection
 	////////////////////////////////////////////////////////////////////////////
 	// Test - Validate there are records to post.
 	////////////////////////////////////////////////////////////////////////////
-	if len(toDb) == 0 {
+	if len(toDb) == 0 && !pendingApproval(r.DbRecords) {
 		err = errors.New("an error occured while creating this resource - create record is empty")
 		return r, err
 	}
 	////////////////////////////////////////////////////////////////////////////
 	// Prepare SQL statement for submission.
 	////////////////////////////////////////////////////////////////////////////
+	if len(toDb) == 0 {
+		return r, nil
+	}
 	err = o.addDbRecord(toDb, &r)
 	if err != nil {
 		return r, err
 	}
+	for k := range r.DbRecords {
+		if _, ok := toDb[r.DbRecords[k].ID]; ok {
+			o.publishRecord("created", &r.DbRecords[k], oUser)
+		}
+	}
 	return r, nil
 }
 
@@ -614,7 +673,7 @@ func (o *Common) createDbRecord(d *DbRecord, oUser *userenv.User) (err error) {
 	////////////////////////////////////////////////////////////////////////
 	// Validate Right.
 	////////////////////////////////////////////////////////////////////////
-	err = oUser.HasAdminRight(strconv.Itoa(genericData.ProductCode))
+	err = oUser.HasAnyRight(strconv.Itoa(genericData.ProductCode), userenv.ActionCreate, userenv.ActionModify, userenv.ActionDelete, userenv.ActionMigrate)
 	if err != nil {
 		d.LastError = err.Error()
 		return
@@ -725,7 +784,9 @@ func (o *Common) setStatusDbRecord(d *DbRecord, statusID int32, oUser *userenv.U
 	if err != nil {
 		return
 	}
+	oldStatusID := int32(-1)
 	if dbRecordExists {
+		oldStatusID = currentStatus(databaseRecord.ID)
 		err = fmt.Errorf("database record already exists %v - deleting existing record", databaseRecord.ID)
 		log.Warn(err)
 
@@ -751,11 +812,25 @@ func (o *Common) setStatusDbRecord(d *DbRecord, statusID int32, oUser *userenv.U
 	// Prepare SQL statement for submission.
 	////////////////////////////////////////////////////////////////////////////
 	err = statusDbo.addStatusDbRecord(toDb, nil)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Notify webhooks of the transition.
+	////////////////////////////////////////////////////////////////////////////
+	if oldStatusID != statusID {
+		o.publishStatus(request, oldStatusID, oUser)
+	}
 	return
 }
 
 // ImportAll object records derived from all loadbalancers.
 func (o *Common) ImportAll(oUser *userenv.User) (r DbRecordCollection, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	defer func() {
+		o.publishCompletion("import_completed", int(r.SQLMessage.RowsAffected), err, oUser)
+		o.auditJob("import", int(r.SQLMessage.RowsAffected), err, oUser)
+	}()
 	////////////////////////////////////////////////////////////////////////////
 	// Fetch
 	////////////////////////////////////////////////////////////////////////////
@@ -771,6 +846,17 @@ func (o *Common) ImportAll(oUser *userenv.User) (r DbRecordCollection, err error
 		createRequest = append(createRequest, val.DbRecords...)
 	}
 	////////////////////////////////////////////////////////////////////////////
+	// Keep the ids of records that are already in the database.
+	////////////////////////////////////////////////////////////////////////////
+	ids, err := o.aliasIDs()
+	if err != nil {
+		return
+	}
+	for k := range createRequest {
+		alias, _ := o.aliasKey(&createRequest[k])
+		createRequest[k].ID = ids[alias]
+	}
+	////////////////////////////////////////////////////////////////////////////
 	// Drop existing data.
 	////////////////////////////////////////////////////////////////////////////
 	o.Database.Client.PurgeData([]string{o.Database.Table})
@@ -796,8 +882,13 @@ func (o Common) etlDbRecordAdd(dbRecord *DbRecord, collection *DbRecordCollectio
 	loadBalancer := jsonLoadBalancer
 	loadBalancerIP := dbRecord.LoadBalancerIP
 	lastModifiedBy := oUser.Username
+	dbRecord.Md5Hash = md5Hash(jsonData)
 	////////////////////////////////////////////////////////////////////////////
-	r = fmt.Sprintf(`('%s','%s','%s',%s,'%s','%s','%s')`, id, data, source, lastModified, loadBalancer, loadBalancerIP, lastModifiedBy)
+	r = fmt.Sprintf(`('%s','%s','%s',%s,'%s','%s','%s','%s')`, id, data, source, lastModified, loadBalancer, loadBalancerIP, lastModifiedBy, dbRecord.Md5Hash)
+	if o.Database.Table == "virtualservers" {
+		alias, _ := o.aliasKey(dbRecord)
+		r = fmt.Sprintf(`('%s','%s','%s',%s,'%s','%s','%s','%s','%s')`, id, data, source, lastModified, loadBalancer, loadBalancerIP, lastModifiedBy, dbRecord.Md5Hash, alias)
+	}
 	if collection != nil {
 		collection.DbRecords = append(collection.DbRecords, *dbRecord)
 	}
@@ -840,7 +931,11 @@ func (o *Common) addDbRecord(toDb map[string]string, r *DbRecordCollection) (err
 		toDbVals = append(toDbVals, v)
 	}
 	insertStr := strings.Join(toDbVals, ",")
-	toSQL, err = o.Database.Client.Db.Prepare(`INSERT INTO public.` + o.Database.Table + ` (id, data, source, last_modified, load_balancer, load_balancer_ip, last_modified_by) VALUES ` + insertStr + ` RETURNING id`)
+	columns := `id, data, source, last_modified, load_balancer, load_balancer_ip, last_modified_by, md5hash`
+	if o.Database.Table == "virtualservers" {
+		columns = columns + `, alias`
+	}
+	toSQL, err = o.Database.Client.Db.Prepare(`INSERT INTO public.` + o.Database.Table + ` (` + columns + `) VALUES ` + insertStr + ` RETURNING id`)
 	if err != nil {
 		return
 	}
@@ -929,7 +1024,7 @@ func (o *Common) importRecords(dbRecords []DbRecord, oUser *userenv.User) (r DbR
 		////////////////////////////////////////////////////////////////////////
 		// Validate Right.
 		////////////////////////////////////////////////////////////////////////
-		err = oUser.HasAdminRight(strconv.Itoa(data.ProductCode))
+		err = oUser.HasRight(userenv.ActionImport, strconv.Itoa(data.ProductCode))
 		if err != nil {
 			dbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *dbRecord)
@@ -1041,7 +1136,7 @@ func (o *Common) setLoadBalancer(dbRecord *DbRecord, data *virtualserver.Data) (
 	return errors.New("unable to find a suitable load balancer")
 }
 
-func (o *Common) setIP(dbRecord *DbRecord, data *virtualserver.Data) (err error) {
+func (o *Common) setIP(dbRecord *DbRecord, data *virtualserver.Data, t shared.Tracker) (err error) {
 	////////////////////////////////////////////////////////////////////////////
 	if !o.ModifyLb {
 		err = errors.New("this function is only permitted for new virtual services")
@@ -1057,7 +1152,9 @@ func (o *Common) setIP(dbRecord *DbRecord, data *virtualserver.Data) (err error)
 	defer ibo.Client.Unset()
 	////////////////////////////////////////////////////////////////////////////
 	if data.IP != "" {
+		done := shared.Track(t, "dns")
 		dns, err := ibo.Create(data.IP, data.ProductCode, data.DNS)
+		done(err)
 		if err != nil {
 			return err
 		}
@@ -1108,19 +1205,21 @@ func (o *Common) setIP(dbRecord *DbRecord, data *virtualserver.Data) (err error)
 		return err
 	}
 	////////////////////////////////////////////////////////////////////////
+	done := shared.Track(t, "ip allocation")
 	data.IP, err = ibo.FetchIP(ipNet.String())
-	if err != nil {
-		return err
-	}
-	////////////////////////////////////////////////////////////////////////////
-	if data.IP == "" {
+	if err == nil && data.IP == "" {
 		err = fmt.Errorf("unable to automatically assign an ip on %s", ipNet.String())
+	}
+	done(err)
+	if err != nil {
 		return err
 	}
 	////////////////////////////////////////////////////////////////////////////
 	// Create dns entry to prevent accidental re-assignment of IP.
 	////////////////////////////////////////////////////////////////////////////
+	done = shared.Track(t, "dns")
 	dns, err := ibo.Create(data.IP, data.ProductCode, data.DNS)
+	done(err)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// page - ordering and paging clauses of a statement.
+type page struct {
+	// sortKey - text expression records are ordered on before id.
+	sortKey string
+	// seek - condition selecting the records after the cursor.
+	seek    string
+	orderBy string
+	limit   string
+	offset  string
+}
+
+// cursor - position of the last record of a page. Clients only see it as an
+// opaque token.
+type cursor struct {
+	// Value - sort key of the record.
+	Value string `json:"v"`
+	// ID - id of the record.
+	ID string `json:"id"`
+	// Order - orderCol and orderDirection the token was issued for.
+	Order string `json:"o"`
+}
+
+// NextCursor - returns the token of the page after the record with sortKey
+// and id.
+func (f Filter) NextCursor(sortKey string, id string) string {
+	p, _ := json.Marshal(cursor{Value: sortKey, ID: id, Order: f.order()})
+	return base64.RawURLEncoding.EncodeToString(p)
+}
+
+// order - orderCol and orderDirection of the request.
+func (f Filter) order() string {
+	return strings.Join(f.URLQueryParams["orderCol"], ",") + " " + strings.ToLower(strings.Join(f.URLQueryParams["orderDirection"], ","))
+}
+
+// decodeCursor - parses token and checks it was issued for order.
+func decodeCursor(token string, order string) (r cursor, err error) {
+	p, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(p, &r)
+	}
+	if err != nil {
+		return r, errors.New("cursor is not valid - start again without a cursor")
+	}
+	if r.Order != order {
+		return r, errors.New("cursor was issued for a different orderCol or orderDirection")
+	}
+	return
+}
+
+// setPaging - counts the records matching the filter and sets the link to
+// the next page. Requests with an offset page by offset; otherwise the link
+// carries a cursor after the last record returned, lastKey and lastID.
+func (o *Common) setPaging(filter *Filter, msg *SQLMessage, lastKey string, lastID string) (err error) {
+	countStmt, countArgs, err := filter.BuildCountStmt()
+	if err != nil {
+		return
+	}
+	rows, err := o.Database.Client.Db.Query(countStmt, countArgs...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&msg.Total)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	p := filter.URLQueryParams
+	if len(p["limit"]) == 0 {
+		return
+	}
+	lim, err := strconv.Atoi(p["limit"][0])
+	if err != nil {
+		return
+	}
+	if len(p["offset"]) != 0 {
+		offset, err := strconv.Atoi(p["offset"][0])
+		if err != nil {
+			return err
+		}
+		if offset+lim < msg.Total {
+			msg.Next = fmt.Sprintf("/api/v1/%s?%slimit=%v&offset=%v", o.Route, filter.SetQueryParams(), lim, offset+lim)
+		}
+		return nil
+	}
+	if lim > 0 && msg.Rows == lim {
+		msg.Cursor = filter.NextCursor(lastKey, lastID)
+		msg.Next = fmt.Sprintf("/api/v1/%s?%slimit=%v&cursor=%s", o.Route, filter.SetQueryParams(), lim, msg.Cursor)
+	}
+	return
+}
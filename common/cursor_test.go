@@ -0,0 +1,128 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	byName := Filter{URLQueryParams: map[string][]string{"orderCol": {"name"}, "orderDirection": {"DESC"}}}
+	byDefault := Filter{URLQueryParams: map[string][]string{}}
+	tests := []struct {
+		name   string
+		issued Filter
+		used   Filter
+		token  string
+		ok     bool
+	}{
+		{"default order", byDefault, byDefault, "", true},
+		{"same order", byName, byName, "", true},
+		{"direction case ignored", byName, Filter{URLQueryParams: map[string][]string{"orderCol": {"name"}, "orderDirection": {"desc"}}}, "", true},
+		{"different order", byName, byDefault, "", false},
+		{"not base64", byDefault, byDefault, "!!!", false},
+		{"not json", byDefault, byDefault, "bm90IGpzb24", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.token
+			if token == "" {
+				token = tt.issued.NextCursor("prd1-vip", "id-1")
+			}
+			c, err := decodeCursor(token, tt.used.order())
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected cursor to decode, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %+v", c)
+			case tt.ok && (c.Value != "prd1-vip" || c.ID != "id-1"):
+				t.Fatalf("expected prd1-vip/id-1, got %+v", c)
+			}
+		})
+	}
+}
+
+func TestPaging(t *testing.T) {
+	cursor := Filter{URLQueryParams: map[string][]string{"orderCol": {"name"}, "orderDirection": {"desc"}}}.NextCursor("prd1-vip", "id-1")
+	tests := []struct {
+		name    string
+		params  map[string][]string
+		orderBy string
+		seek    string
+		args    []interface{}
+		ok      bool
+	}{
+		{"default", map[string][]string{}, ` ORDER BY coalesce((data->>'product_code')::text, '') asc, id asc`, ``, nil, true},
+		{"limit and offset", map[string][]string{"limit": {"10"}, "offset": {"20"}}, ` ORDER BY coalesce((data->>'product_code')::text, '') asc, id asc`, ``, []interface{}{10, 20}, true},
+		{"negative limit", map[string][]string{"limit": {"-1"}}, ``, ``, nil, false},
+		{"bad offset", map[string][]string{"offset": {"x"}}, ``, ``, nil, false},
+		{"cursor desc", map[string][]string{"orderCol": {"name"}, "orderDirection": {"desc"}, "cursor": {cursor}}, ` ORDER BY coalesce((data->>'name')::text, '') desc, id desc`, `(coalesce((data->>'name')::text, ''), id) < ($1, $2)`, []interface{}{"prd1-vip", "id-1"}, true},
+		{"cursor for another order", map[string][]string{"orderCol": {"name"}, "cursor": {cursor}}, ``, ``, nil, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []interface{}
+			p, err := Filter{URLQueryParams: tt.params}.paging(&args)
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected paging to build, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %+v", p)
+			case !tt.ok:
+				return
+			}
+			if p.orderBy != tt.orderBy || p.seek != tt.seek {
+				t.Fatalf("expected %q %q, got %q %q", tt.orderBy, tt.seek, p.orderBy, p.seek)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(tt.args) {
+				t.Fatalf("expected args %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	var data interface{}
+	err := json.Unmarshal([]byte(`{"name":"prd1-vip","ip":"10.0.0.1","pools":[{"name":"a","bindings":[{"port":80,"server":{"ip":"10.0.1.1"}}]},{"name":"b"}]}`), &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		fields string
+		want   string
+		ok     bool
+	}{
+		{"one field", "name", `{"name":"prd1-vip"}`, true},
+		{"two fields", "name, ip", `{"ip":"10.0.0.1","name":"prd1-vip"}`, true},
+		{"nested arrays", "pools.bindings.server.ip", `{"pools":[{"bindings":[{"server":{"ip":"10.0.1.1"}}]},{}]}`, true},
+		{"whole and nested", "pools.name,pools.bindings.port", `{"pools":[{"bindings":[{"port":80}],"name":"a"},{"name":"b"}]}`, true},
+		{"unknown field", "secret", ``, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Table: "virtualservers", URLQueryParams: map[string][]string{"fields": {tt.fields}}}
+			paths, err := f.Projection()
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected fields to resolve, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %v", paths)
+			case !tt.ok:
+				return
+			}
+			var want interface{}
+			if err = json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if r := project(data, paths); !reflect.DeepEqual(r, want) {
+				p, _ := json.Marshal(r)
+				t.Fatalf("expected %s, got %s", tt.want, p)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,9 +18,10 @@ import (
 
 // DeleteConf - resource configuration.
 type DeleteConf struct {
-	User     *userenv.User
-	Log      *logrus.Entry
-	DbRecord *DbRecord
+	User      *userenv.User
+	Log       *logrus.Entry
+	DbRecord  *DbRecord
+	Operation *Operation
 }
 
 // NewDeleteConf - constructor for delete configuration params.
@@ -43,41 +45,34 @@ func (o *Common) Delete(id string, oUser *userenv.User) (r DbRecord, err error)
 	*log = *o.Log
 	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "delete", "route": o.Route})
 	////////////////////////////////////////////////////////////////////////////
-	// Get Record By Id. This operation queries the system db.
+	// Protected product codes wait for the approval of a second user.
 	////////////////////////////////////////////////////////////////////////////
-	collectionInterface, err := o.FetchByID(id, oUser)
-	if err != nil {
-		r.LastError = err.Error()
-		return r, err
+	if d, pending, err := o.requestApproval(ChangeRequest{Action: "delete", RecordID: id}, nil, oUser); pending || err != nil {
+		return d, err
 	}
+	defer func() {
+		before := r
+		if before.ID == "" {
+			before.ID = id
+		}
+		o.audit("delete", &before, nil, err, oUser)
+	}()
 	////////////////////////////////////////////////////////////////////////////
-	// shared.MarshalInterface.
+	// Get Record By Id and validate right.
 	////////////////////////////////////////////////////////////////////////////
-	collection := DbRecordCollection{}
-	err = shared.MarshalInterface(collectionInterface, &collection)
+	r, err = o.deletable(id, oUser)
 	if err != nil {
-		r.LastError = err.Error()
 		return r, err
 	}
-	if len(collection.DbRecords) == 0 {
-		err = fmt.Errorf("no records match %s", id)
-		r.LastError = err.Error()
-		return r, err
-	}
-	////////////////////////////////////////////////////////////////////////////
-	// Assign returned record to dbRecord
-	////////////////////////////////////////////////////////////////////////////
-	r = collection.DbRecords[0]
 	conf := NewDeleteConf(&r, oUser, log)
-	////////////////////////////////////////////////////////////////////////
-	// Unmarshal Data
-	////////////////////////////////////////////////////////////////////////
-	var data Data
-	shared.MarshalInterface(r.Data, &data)
 	////////////////////////////////////////////////////////////////////////////
-	// Validate Right.
+	// Validate record has not changed since the client fetched it.
 	////////////////////////////////////////////////////////////////////////////
-	err = oUser.HasAdminRight(strconv.Itoa(data.ProductCode))
+	ifMatch, err := o.checkIfMatch(r.ID, oUser)
+	var claim string
+	if err == nil {
+		claim, err = o.claimIfMatch(r.ID, ifMatch, oUser)
+	}
 	if err != nil {
 		r.LastError = err.Error()
 		return r, err
@@ -87,6 +82,7 @@ func (o *Common) Delete(id string, oUser *userenv.User) (r DbRecord, err error)
 	////////////////////////////////////////////////////////////////////////////
 	err = o.toRecycle(conf)
 	if err != nil {
+		o.releaseIfMatch(r.ID, ifMatch, claim)
 		r.LastError = err.Error()
 		return r, err
 	}
@@ -94,15 +90,26 @@ func (o *Common) Delete(id string, oUser *userenv.User) (r DbRecord, err error)
 	// Actions
 	////////////////////////////////////////////////////////////////////////////
 	if o.ModifyLb {
+		conf.Operation = o.newOperation("delete", &r, oUser)
+		r.OperationID = conf.Operation.OperationID()
 		r.StatusID = 7
 		r.Status = Status[int(r.StatusID)]
 		statusErr := o.setStatusDbRecord(&r, 7, oUser)
 		if statusErr != nil {
 			log.Warn(err)
 		}
-		go o.deleteModifyLb(conf)
+		go func() {
+			if o.deleteModifyLb(conf) != nil {
+				o.releaseIfMatch(r.ID, ifMatch, claim)
+			}
+		}()
 	} else {
 		err = o.deleteDbRecord(conf)
+		if err == nil {
+			o.publishRecord("deleted", &r, oUser)
+		} else {
+			o.releaseIfMatch(r.ID, ifMatch, claim)
+		}
 	}
 	if err != nil {
 		r.LastError = err.Error()
@@ -111,6 +118,56 @@ func (o *Common) Delete(id string, oUser *userenv.User) (r DbRecord, err error)
 	return r, err
 }
 
+// deletable - returns the record with id when oUser may delete it.
+func (o *Common) deletable(id string, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if id == "" {
+		err = errors.New("id is required")
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Get Record By Id. This operation queries the system db.
+	////////////////////////////////////////////////////////////////////////////
+	collectionInterface, err := o.FetchByID(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// shared.MarshalInterface.
+	////////////////////////////////////////////////////////////////////////////
+	collection := DbRecordCollection{}
+	err = shared.MarshalInterface(collectionInterface, &collection)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if len(collection.DbRecords) == 0 {
+		err = fmt.Errorf("%w - no records match %s", ErrNotFound, id)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Assign returned record to dbRecord
+	////////////////////////////////////////////////////////////////////////////
+	r = collection.DbRecords[0]
+	////////////////////////////////////////////////////////////////////////
+	// Unmarshal Data
+	////////////////////////////////////////////////////////////////////////
+	var data Data
+	shared.MarshalInterface(r.Data, &data)
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right.
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.HasRight(userenv.ActionDelete, strconv.Itoa(data.ProductCode))
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	return r, nil
+}
+
 // deleteDbRecord - deletes database record.
 func (o *Common) deleteDbRecord(conf *DeleteConf) (err error) {
 	////////////////////////////////////////////////////////////////////////////
@@ -151,6 +208,10 @@ func (o *Common) deleteInfobloxRecords(conf *DeleteConf) (err error) {
 	if !config.GlobalConfig.Infoblox.Enable || o.Database.Table != "virtualservers" {
 		return nil
 	}
+	done := shared.Track(conf.Operation, "dns")
+	defer func() {
+		done(err)
+	}()
 	////////////////////////////////////////////////////////////////////////////
 	var vsData virtualserver.Data
 	err = shared.MarshalInterface(dbRecord.Data, &vsData)
@@ -181,10 +242,17 @@ func (o *Common) deleteModifyLb(conf *DeleteConf) (err error) {
 	////////////////////////////////////////////////////////////////////////////
 	log := conf.Log
 	dbRecord := conf.DbRecord
+	op := conf.Operation
 	////////////////////////////////////////////////////////////////////////
 	if o.ModifyLb == false {
 		return nil
 	}
+	defer func() {
+		op.Finish(nil, err)
+		if err == nil {
+			o.publishRecord("deleted", dbRecord, conf.User)
+		}
+	}()
 	////////////////////////////////////////////////////////////////////////
 	target := &sdkfork.SdkTarget{
 		Address: dbRecord.LoadBalancerIP,
@@ -192,8 +260,9 @@ func (o *Common) deleteModifyLb(conf *DeleteConf) (err error) {
 	}
 	////////////////////////////////////////////////////////////////////////
 	sdkConf := &sdkfork.SdkConf{
-		Target: target,
-		Log:    log,
+		Target:  target,
+		Log:     log,
+		Tracker: op,
 	}
 	////////////////////////////////////////////////////////////////////////
 	s := sdkfork.New(sdkConf)
@@ -203,13 +272,18 @@ func (o *Common) deleteModifyLb(conf *DeleteConf) (err error) {
 	if err != nil {
 		log.Print(err)
 		if strings.Contains(err.Error(), "object not found!") {
-			return o.deleteDbRecord(conf)
+			done := shared.Track(op, "db write")
+			err = o.deleteDbRecord(conf)
+			done(err)
+			return err
 		}
 		return err
 	}
 	////////////////////////////////////////////////////////////////////////
 	if recordExists {
+		done := shared.Track(op, "vs delete")
 		err = s.Delete(dbRecord.Data, o.Route)
+		done(err)
 		if err != nil {
 			return err
 		}
@@ -220,7 +294,10 @@ func (o *Common) deleteModifyLb(conf *DeleteConf) (err error) {
 		return err
 	}
 	////////////////////////////////////////////////////////////////////////
-	return o.deleteDbRecord(conf)
+	done := shared.Track(op, "db write")
+	err = o.deleteDbRecord(conf)
+	done(err)
+	return err
 }
 
 // deleteStatusDbRecord - deletes status database record.
@@ -287,5 +364,10 @@ func (o *Common) toRecycle(conf *DeleteConf) (err error) {
 	dbo.Database.Client = dao.GlobalDAO
 	dbo.ModifyLb = false
 	dbo.Database.Table = "recycle"
-	return dbo.createDbRecord(dbRecord, oUser)
+	err = dbo.createDbRecord(dbRecord, oUser)
+	if err != nil {
+		return
+	}
+	o.publishRecord("recycled", dbRecord, oUser)
+	return nil
 }
@@ -0,0 +1,27 @@
+package common
+
+import "errors"
+
+// ErrPreconditionFailed - returned when the If-Match header supplied by the
+// client no longer matches the stored record.
+var ErrPreconditionFailed = errors.New("record was modified by another request - fetch the record and retry")
+
+// ErrNotFound - returned when no record matches the requested id.
+var ErrNotFound = errors.New("record not found")
+
+// ErrIdempotencyMismatch - returned when an Idempotency-Key is reused with a
+// different payload.
+var ErrIdempotencyMismatch = errors.New("idempotency key does not match the original request")
+
+// ErrIdempotencyInProgress - returned when a request is retried before the
+// original request with the same Idempotency-Key has finished.
+var ErrIdempotencyInProgress = errors.New("original request is still in progress")
+
+// ErrKeyNotStored - returned when a rollback or restore needs a certificate
+// private key that is not in secure storage.
+var ErrKeyNotStored = errors.New("private key of certificate is not in secure storage")
+
+// ErrNotPending - returned when a scheduled change that has already started,
+// finished or been cancelled is cancelled, or a change request that has
+// already been reviewed is approved or rejected.
+var ErrNotPending = errors.New("change is no longer pending")
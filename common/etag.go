@@ -0,0 +1,117 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// ETag - returns the entity tag of the record. The tag is the md5hash of the
+// record data.
+func ETag(dbRecord DbRecord) string {
+	return fmt.Sprintf(`"%s"`, dbRecord.Md5Hash)
+}
+
+// md5Hash - hashes serialized record data. Case is kept, so a change to the
+// case of a name or certificate changes the tag.
+func md5Hash(jsonData string) string {
+	return shared.GetMD5Hash(jsonData)
+}
+
+// setMd5Hash - populates the hash for records written before the md5hash
+// column was maintained.
+func setMd5Hash(dbRecord *DbRecord) {
+	if dbRecord.Md5Hash != "" {
+		return
+	}
+	dbRecord.Md5Hash = md5Hash(shared.ToJSON(dbRecord.Data))
+}
+
+// checkIfMatch - compares the If-Match header against the stored record.
+// Returns ErrPreconditionFailed when none of the supplied tags match.
+// Requests without an If-Match header are not checked. tag is the hash the
+// record had when it matched, to be claimed with claimIfMatch before the
+// change is made. It is empty when the change is unconditional.
+func (o *Common) checkIfMatch(id string, oUser *userenv.User) (tag string, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if oUser == nil || oUser.Context == nil {
+		return "", nil
+	}
+	header := oUser.Context.GetHeader("If-Match")
+	if header == "" {
+		return "", nil
+	}
+	////////////////////////////////////////////////////////////////////////////
+	resp, err := o.FetchByID(id, oUser)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.DbRecords) == 0 {
+		return "", ErrPreconditionFailed
+	}
+	current := strings.Trim(ETag(resp.DbRecords[0]), `"`)
+	////////////////////////////////////////////////////////////////////////////
+	tag, ok := matchIfMatch(header, current)
+	if !ok {
+		return "", ErrPreconditionFailed
+	}
+	return tag, nil
+}
+
+// matchIfMatch - compares the tags of an If-Match header against current.
+// Weak tags are accepted. tag is empty when the header matches any record.
+func matchIfMatch(header string, current string) (tag string, ok bool) {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" {
+			return "", true
+		}
+		v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+		if v == current {
+			return current, true
+		}
+	}
+	return "", false
+}
+
+// claimIfMatch - replaces the stored hash tag of the record with a new claim
+// hash in one conditional write, before the load balancer is touched. Of two
+// requests made with the same If-Match only one gets past it; the other gets
+// ErrPreconditionFailed. Records written before the md5hash column was
+// maintained have no stored hash and are claimed as well. Nothing is done
+// when tag is empty.
+func (o *Common) claimIfMatch(id string, tag string, oUser *userenv.User) (claim string, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if tag == "" {
+		return "", nil
+	}
+	uuid, err := shared.NewUUID()
+	if err != nil {
+		return "", err
+	}
+	claim = md5Hash(uuid)
+	////////////////////////////////////////////////////////////////////////////
+	result, err := o.Database.Client.Db.Exec(`UPDATE public.`+o.Database.Table+` SET md5hash=$3, last_modified=current_timestamp, last_modified_by=$4 WHERE id=$1 AND (md5hash=$2 OR md5hash IS NULL OR md5hash='')`, id, tag, claim, oUser.Username)
+	if err != nil {
+		return "", err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return "", ErrPreconditionFailed
+	}
+	return claim, nil
+}
+
+// releaseIfMatch - puts back the stored hash tag of a record claimed with
+// claimIfMatch when the change failed, unless the record has been written
+// since.
+func (o *Common) releaseIfMatch(id string, tag string, claim string) {
+	if claim == "" {
+		return
+	}
+	_, err := o.Database.Client.Db.Exec(`UPDATE public.`+o.Database.Table+` SET md5hash=$3 WHERE id=$1 AND md5hash=$2`, id, claim, tag)
+	if err != nil {
+		o.Log.Warn(err)
+	}
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+func TestMatchIfMatch(t *testing.T) {
+	current := "0123abcd"
+	tests := []struct {
+		name   string
+		header string
+		tag    string
+		ok     bool
+	}{
+		{"strong tag", `"0123abcd"`, current, true},
+		{"weak tag", `W/"0123abcd"`, current, true},
+		{"unquoted tag", `0123abcd`, current, true},
+		{"any", `*`, "", true},
+		{"one of several", `"ffff", "0123abcd"`, current, true},
+		{"any after a mismatch", `"ffff", *`, "", true},
+		{"stale tag", `"ffff"`, "", false},
+		{"case differs", `"0123ABCD"`, "", false},
+		{"empty tag", `""`, "", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, ok := matchIfMatch(tt.header, current)
+			if ok != tt.ok {
+				t.Fatalf("expected match %v, got %v", tt.ok, ok)
+			}
+			if tag != tt.tag {
+				t.Fatalf("expected tag %q, got %q", tt.tag, tag)
+			}
+		})
+	}
+}
+
+func TestSetMd5Hash(t *testing.T) {
+	data := map[string]interface{}{"name": "prd1-VIP"}
+	tests := []struct {
+		name   string
+		record DbRecord
+		want   string
+	}{
+		{"stored hash kept", DbRecord{Md5Hash: "abc", Data: data}, "abc"},
+		{"missing hash computed", DbRecord{Data: data}, md5Hash(shared.ToJSON(data))},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMd5Hash(&tt.record)
+			if tt.record.Md5Hash != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, tt.record.Md5Hash)
+			}
+			if ETag(tt.record) != `"`+tt.want+`"` {
+				t.Fatalf("expected quoted etag, got %s", ETag(tt.record))
+			}
+		})
+	}
+}
+
+func TestMd5HashKeepsCase(t *testing.T) {
+	if md5Hash(`{"name":"prd1-vip"}`) == md5Hash(`{"name":"prd1-VIP"}`) {
+		t.Fatal("expected a change of case to change the hash")
+	}
+}
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"time"
+
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+// eventStatus - status names sent in webhook events. They match
+// public.statusdescription, which is also what the status filter accepts.
+var eventStatus = map[int32]string{
+	0: "deployed",
+	1: "fail",
+	2: "partial",
+	3: "migrating",
+	4: "migrated",
+	5: "creating",
+	6: "updating",
+	7: "deleting",
+}
+
+// currentStatus - returns the status id stored for id, or -1 when the record
+// has no status yet.
+func currentStatus(id string) (r int32) {
+	err := dao.GlobalDAO.Db.QueryRow(`SELECT status_id FROM public.status WHERE id=$1`, id).Scan(&r)
+	if err != nil {
+		return -1
+	}
+	return
+}
+
+// publishStatus - raises <route>.status_changed for a record moving from
+// oldStatusID to d.StatusID.
+func (o *Common) publishStatus(d *DbRecord, oldStatusID int32, oUser *userenv.User) {
+	e := o.newEvent("status_changed", d, oUser)
+	e.OldStatus = eventStatus[oldStatusID]
+	e.NewStatus = eventStatus[d.StatusID]
+	publish(e)
+}
+
+// publishRecord - raises <route>.<action> for d. Writes to the status and
+// migrate tables are reported through status_changed instead.
+func (o *Common) publishRecord(action string, d *DbRecord, oUser *userenv.User) {
+	if o.Database.Table == "status" || o.Database.Table == "migrate" {
+		return
+	}
+	publish(o.newEvent(action, d, oUser))
+}
+
+// publishCompletion - raises <route>.<action> once a job covering count
+// records has finished. err is sent as last_error.
+func (o *Common) publishCompletion(action string, count int, err error, oUser *userenv.User) {
+	e := o.newEvent(action, nil, oUser)
+	e.Count = count
+	if err != nil {
+		e.LastError = err.Error()
+	}
+	publish(e)
+}
+
+// publish - sends e to the webhooks and to the event stream.
+func publish(e webhook.Event) {
+	webhook.Publish(e)
+	notify(e)
+}
+
+// newEvent - returns an event of type <route>.<action> describing d.
+func (o *Common) newEvent(action string, d *DbRecord, oUser *userenv.User) (r webhook.Event) {
+	r = webhook.Event{Type: o.Route + "." + action, Route: o.Route, Time: time.Now().UTC().Format(time.RFC3339Nano)}
+	r.ID, _ = shared.NewUUID()
+	if oUser != nil {
+		r.User = oUser.Username
+	}
+	if d == nil {
+		return
+	}
+	r.RecordID = d.ID
+	r.LastError = d.LastError
+	var data Data
+	if shared.MarshalInterface(d.Data, &data) == nil {
+		r.ProductCode = data.ProductCode
+	}
+	return
+}
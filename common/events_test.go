@@ -0,0 +1,41 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+func TestNewEvent(t *testing.T) {
+	o := &Common{Route: "virtualserver"}
+	tests := []struct {
+		name   string
+		action string
+		record *DbRecord
+		user   *userenv.User
+		want   webhook.Event
+	}{
+		{"record", "recycled", &DbRecord{ID: "a", LastError: "lb unreachable", Data: map[string]interface{}{"product_code": 1234}}, &userenv.User{Username: "jdoe"},
+			webhook.Event{Type: "virtualserver.recycled", Route: "virtualserver", RecordID: "a", ProductCode: 1234, User: "jdoe", LastError: "lb unreachable"}},
+		{"no record", "backup_completed", nil, &userenv.User{Username: "jdoe"},
+			webhook.Event{Type: "virtualserver.backup_completed", Route: "virtualserver", User: "jdoe"}},
+		{"no user", "import_completed", nil, nil,
+			webhook.Event{Type: "virtualserver.import_completed", Route: "virtualserver"}},
+		{"data without product code", "recycled", &DbRecord{ID: "a", Data: "not an object"}, nil,
+			webhook.Event{Type: "virtualserver.recycled", Route: "virtualserver", RecordID: "a"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := o.newEvent(tt.action, tt.record, tt.user)
+			if r.ID == "" || r.Time == "" {
+				t.Fatalf("expected id and time to be set, got %+v", r)
+			}
+			r.ID, r.Time = "", ""
+			if r != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, r)
+			}
+		})
+	}
+}
@@ -33,14 +33,20 @@ func (o *Common) Fetch(p map[string][]string, limit int, oUser *userenv.User) (r
 	filter := NewFilter()
 	filter.Table = o.Database.Table
 	filter.URLQueryParams = p
-	qry, err := filter.BuildSQLStmt()
+	filter.ProductCodes = fetchScope(oUser)
+	paths, err := filter.Projection()
 	if err != nil {
 		return
 	}
-	rows, err := o.Database.Client.Db.Query(qry)
+	qry, args, err := filter.BuildSQLStmt()
 	if err != nil {
 		return
 	}
+	rows, err := o.Database.Client.Db.Query(qry, args...)
+	if err != nil {
+		return
+	}
+	var lastKey, lastID string
 	for rows.Next() {
 		dbRecord := new(DbRecord)
 		dbResponseRecord := new(DbResponseRecord)
@@ -55,6 +61,7 @@ func (o *Common) Fetch(p map[string][]string, limit int, oUser *userenv.User) (r
 			&dbResponseRecord.Source,
 			&dbResponseRecord.Status,
 			&dbResponseRecord.LastError,
+			&dbResponseRecord.SortKey,
 		)
 		dbRecord.ID = dbResponseRecord.ID
 		json.Unmarshal(dbResponseRecord.Data, &dbRecord.Data)
@@ -66,6 +73,11 @@ func (o *Common) Fetch(p map[string][]string, limit int, oUser *userenv.User) (r
 		json.Unmarshal(dbResponseRecord.LoadBalancer, &dbRecord.LoadBalancer)
 		dbRecord.Source = dbResponseRecord.Source.String
 		dbRecord.LastError = dbResponseRecord.LastError.String
+		setMd5Hash(dbRecord)
+		if len(paths) > 0 {
+			dbRecord.Data = project(dbRecord.Data, paths)
+		}
+		lastKey, lastID = dbResponseRecord.SortKey.String, dbResponseRecord.ID
 		r.DbRecords = append(r.DbRecords, *dbRecord)
 	}
 	if len(r.DbRecords) > limit && limit != 0 {
@@ -80,41 +92,7 @@ func (o *Common) Fetch(p map[string][]string, limit int, oUser *userenv.User) (r
 	////////////////////////////////////////////////////////////////////////////
 	// Record Paging
 	////////////////////////////////////////////////////////////////////////////
-	var total int
-	var lim int
-	var offset int
-	var next int
-	var diff int
-
-	countStmt, err := filter.BuildCountStmt()
-	if err != nil {
-		return
-	}
-	rows, err = o.Database.Client.Db.Query(countStmt)
-	if err != nil {
-		return
-	}
-	for rows.Next() {
-		rows.Scan(&total)
-	}
-	if len(p["offset"]) != 0 {
-		offset, err = strconv.Atoi(p["offset"][0])
-		if err != nil {
-			return
-		}
-	}
-	if len(p["limit"]) != 0 {
-		lim, err = strconv.Atoi(p["limit"][0])
-		if err != nil {
-			return
-		}
-		diff = total - offset
-		if lim < diff {
-			next = offset + lim + 1
-			r.SQLMessage.Next = fmt.Sprintf("/api/v1/%s?%slimit=%v&offset=%v", o.Route, filter.SetQueryParams(), lim, next)
-		}
-		r.SQLMessage.Total = total
-	}
+	err = o.setPaging(filter, &r.SQLMessage, lastKey, lastID)
 	return
 }
 
@@ -130,14 +108,16 @@ func (o *Common) FetchVirtualServices(p map[string][]string, limit int, oUser *u
 	filter := NewFilter()
 	filter.Table = o.Database.Table
 	filter.URLQueryParams = p
-	qry, err := filter.BuildVsSQLStmt()
+	filter.ProductCodes = fetchScope(oUser)
+	qry, args, err := filter.BuildVsSQLStmt()
 	if err != nil {
 		return
 	}
-	rows, err := o.Database.Client.Db.Query(qry)
+	rows, err := o.Database.Client.Db.Query(qry, args...)
 	if err != nil {
 		return
 	}
+	var lastKey, lastID string
 	for rows.Next() {
 		vsdbRecord := new(VsDbRecord)
 		vsResponseRecord := new(VsDbResponseRecord)
@@ -147,12 +127,15 @@ func (o *Common) FetchVirtualServices(p map[string][]string, limit int, oUser *u
 			&vsResponseRecord.LoadBalancerIP,
 			&vsResponseRecord.Platform,
 			&vsResponseRecord.ServiceType,
+			&vsResponseRecord.ID,
+			&vsResponseRecord.SortKey,
 		)
 		vsdbRecord.Name = vsResponseRecord.Name.String
 		vsdbRecord.IP = vsResponseRecord.IP.String
 		vsdbRecord.LoadBalancerIP = vsResponseRecord.LoadBalancerIP.String
 		vsdbRecord.Platform = vsResponseRecord.Platform.String
 		vsdbRecord.ServiceType = vsResponseRecord.ServiceType.String
+		lastKey, lastID = vsResponseRecord.SortKey.String, vsResponseRecord.ID.String
 		r.VsDbRecords = append(r.VsDbRecords, *vsdbRecord)
 	}
 	if len(r.VsDbRecords) > limit && limit != 0 {
@@ -167,41 +150,7 @@ func (o *Common) FetchVirtualServices(p map[string][]string, limit int, oUser *u
 	////////////////////////////////////////////////////////////////////////////
 	// Record Paging
 	////////////////////////////////////////////////////////////////////////////
-	var total int
-	var lim int
-	var offset int
-	var next int
-	var diff int
-
-	countStmt, err := filter.BuildCountStmt()
-	if err != nil {
-		return
-	}
-	rows, err = o.Database.Client.Db.Query(countStmt)
-	if err != nil {
-		return
-	}
-	for rows.Next() {
-		rows.Scan(&total)
-	}
-	if len(p["offset"]) != 0 {
-		offset, err = strconv.Atoi(p["offset"][0])
-		if err != nil {
-			return
-		}
-	}
-	if len(p["limit"]) != 0 {
-		lim, err = strconv.Atoi(p["limit"][0])
-		if err != nil {
-			return
-		}
-		diff = total - offset
-		if lim < diff {
-			next = offset + lim + 1
-			r.SQLMessage.Next = fmt.Sprintf("/api/v1/%s?%slimit=%v&offset=%v", o.Route, filter.SetQueryParams(), lim, next)
-		}
-		r.SQLMessage.Total = total
-	}
+	err = o.setPaging(filter, &r.SQLMessage, lastKey, lastID)
 	return
 }
 
@@ -213,11 +162,11 @@ func (o Common) FetchFromDb(p map[string][]string, limit int) (r DbRecordCollect
 	filter := NewFilter()
 	filter.Table = o.Database.Table
 	filter.URLQueryParams = p
-	qry, err := filter.BuildSQLStmt()
+	qry, args, err := filter.BuildSQLStmt()
 	if err != nil {
 		return
 	}
-	rows, err := o.Database.Client.Db.Query(qry)
+	rows, err := o.Database.Client.Db.Query(qry, args...)
 	if err != nil {
 		err = fmt.Errorf("%s - %s", err.Error(), qry)
 		return
@@ -236,6 +185,7 @@ func (o Common) FetchFromDb(p map[string][]string, limit int) (r DbRecordCollect
 			&dbResponseRecord.Source,
 			&dbResponseRecord.Status,
 			&dbResponseRecord.LastError,
+			&dbResponseRecord.SortKey,
 		)
 		dbRecord.ID = dbResponseRecord.ID
 		json.Unmarshal(dbResponseRecord.Data, &dbRecord.Data)
@@ -247,6 +197,7 @@ func (o Common) FetchFromDb(p map[string][]string, limit int) (r DbRecordCollect
 		dbRecord.Source = dbResponseRecord.Source.String
 		dbRecord.Status = dbResponseRecord.Status.String
 		dbRecord.LastError = dbResponseRecord.LastError.String
+		setMd5Hash(dbRecord)
 		r.DbRecords = append(r.DbRecords, *dbRecord)
 	}
 	if len(r.DbRecords) > limit && limit != 0 {
@@ -379,12 +330,36 @@ func (o *Common) FetchAll(oUser *userenv.User) (r []LBRecordCollection, err erro
 }
 
 // FetchByID returns an object's local-sourced record based on id.
+// Virtual servers can also be fetched by alias.
 func (o *Common) FetchByID(id string, oUser *userenv.User) (r DbRecordCollection, err error) {
 	filter := make(map[string][]string)
 	filter["id"] = []string{id}
+	r, err = o.Fetch(filter, 0, oUser)
+	if err != nil || len(r.DbRecords) > 0 {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	aliasID, err := o.idFromAlias(id)
+	if err != nil || aliasID == "" {
+		return
+	}
+	filter["id"] = []string{aliasID}
 	return o.Fetch(filter, 0, oUser)
 }
 
+// fetchScope - returns the product codes oUser may fetch, or nil when every
+// product code.
+func fetchScope(oUser *userenv.User) []string {
+	codes, all := oUser.Codes(userenv.ActionFetch)
+	if all {
+		return nil
+	}
+	if codes == nil {
+		codes = []string{}
+	}
+	return codes
+}
+
 // GetLast30Day returns last 30 day metrics for the load balancer. Requires access to a Prometheus instance.
 func (o *Common) GetLast30Day(target string) (r map[string]int, err error) {
 	rec := GlobalSources.Clusters[target].DNS
@@ -0,0 +1,99 @@
+package common
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// idempotencyTTL - how long a key is remembered. An expired key may be
+// reused for a new request.
+const idempotencyTTL = "24 hours"
+
+// idempotencyReservationTTL - how long a key stays reserved while its request
+// is processed. A reservation that was never saved, e.g. because lbapi
+// stopped mid-request, is released after it so the request can be retried.
+const idempotencyReservationTTL = "10 minutes"
+
+// IdempotentResponse - response stored for an Idempotency-Key.
+type IdempotentResponse struct {
+	StatusCode int
+	Body       json.RawMessage
+}
+
+// ReserveIdempotencyKey - claims key for the request p before any change is
+// made. A nil response means the key is new and the request should proceed;
+// call SaveIdempotencyKey with its outcome. When the key was already used
+// with the same payload, the stored response is returned so it can be
+// replayed. Keys are scoped to the user and route.
+func (o *Common) ReserveIdempotencyKey(key string, p []byte, oUser *userenv.User) (r *IdempotentResponse, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if len(key) > 255 {
+		return nil, fmt.Errorf("%w - Idempotency-Key must not exceed 255 characters", ErrIdempotencyMismatch)
+	}
+	id := o.idempotencyID(key, oUser)
+	requestHash := shared.GetMD5Hash(string(p))
+	////////////////////////////////////////////////////////////////////////////
+	// Claim the key, or take over one that has expired or whose reservation
+	// was abandoned.
+	////////////////////////////////////////////////////////////////////////////
+	var claimed string
+	err = dao.GlobalDAO.Db.QueryRow(`
+	INSERT INTO public.idempotency (id, request_hash, source, last_modified, last_modified_by)
+	VALUES ($1, $2, $3, current_timestamp, $4)
+	ON CONFLICT (id) DO UPDATE SET
+		request_hash=EXCLUDED.request_hash,
+		status_code=NULL,
+		data=NULL,
+		last_modified=EXCLUDED.last_modified,
+		last_modified_by=EXCLUDED.last_modified_by
+	WHERE public.idempotency.last_modified < current_timestamp - interval '`+idempotencyTTL+`'
+	OR (public.idempotency.status_code IS NULL AND public.idempotency.last_modified < current_timestamp - interval '`+idempotencyReservationTTL+`')
+	RETURNING id`, id, requestHash, o.Route, oUser.Username).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Key is in use - replay the stored response.
+	////////////////////////////////////////////////////////////////////////////
+	var (
+		storedHash string
+		statusCode sql.NullInt64
+		data       []byte
+	)
+	err = dao.GlobalDAO.Db.QueryRow(`SELECT request_hash, status_code, data FROM public.idempotency WHERE id=$1`, id).Scan(&storedHash, &statusCode, &data)
+	if err != nil {
+		return nil, err
+	}
+	if storedHash != requestHash {
+		return nil, fmt.Errorf("%w - Idempotency-Key %s was used with a different payload", ErrIdempotencyMismatch, key)
+	}
+	if !statusCode.Valid {
+		return nil, fmt.Errorf("%w - a request with Idempotency-Key %s is still being processed", ErrIdempotencyInProgress, key)
+	}
+	return &IdempotentResponse{StatusCode: int(statusCode.Int64), Body: data}, nil
+}
+
+// SaveIdempotencyKey - stores the response returned for key so that retries
+// replay it.
+func (o *Common) SaveIdempotencyKey(key string, statusCode int, response interface{}, oUser *userenv.User) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	p, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`UPDATE public.idempotency SET status_code=$2, data=$3, last_modified=current_timestamp WHERE id=$1`, o.idempotencyID(key, oUser), statusCode, string(p))
+	return
+}
+
+// idempotencyID - id of the row storing key.
+func (o *Common) idempotencyID(key string, oUser *userenv.User) string {
+	return shared.GetMD5Hash(oUser.Username + "\n" + o.Route + "\n" + key)
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+func TestIdempotencyID(t *testing.T) {
+	vs := &Common{Route: "virtualserver"}
+	jdoe := &userenv.User{Username: "jdoe"}
+	id := vs.idempotencyID("key-1", jdoe)
+	tests := []struct {
+		name  string
+		route string
+		user  string
+		key   string
+		same  bool
+	}{
+		{"same request", "virtualserver", "jdoe", "key-1", true},
+		{"other key", "virtualserver", "jdoe", "key-2", false},
+		{"other user", "virtualserver", "asmith", "key-1", false},
+		{"other route", "loadbalancer", "jdoe", "key-1", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Common{Route: tt.route}
+			r := o.idempotencyID(tt.key, &userenv.User{Username: tt.user})
+			if (r == id) != tt.same {
+				t.Fatalf("expected same id %v, got %s and %s", tt.same, id, r)
+			}
+		})
+	}
+}
+
+func TestReserveIdempotencyKeyLength(t *testing.T) {
+	o := &Common{Route: "virtualserver"}
+	_, err := o.ReserveIdempotencyKey(strings.Repeat("k", 256), nil, &userenv.User{Username: "jdoe"})
+	if !errors.Is(err, ErrIdempotencyMismatch) {
+		t.Fatalf("expected ErrIdempotencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+// Maintenance - enables or disables a backend server in every virtual server
+// that binds it, across all clusters. The changes run in the background and
+// are tracked by an operation with one step per virtual server. Maintenance is
+// refused while any of the virtual servers has a protected product code; those
+// bindings must be changed one at a time so that each is approved.
+func (o *Common) Maintenance(ip string, state pool.MemberBinding, oUser *userenv.User) (r MaintenanceRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	log := logrus.NewEntry(logrus.New())
+	*log = *o.Log
+	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "maintenance", "route": o.Route})
+	////////////////////////////////////////////////////////////////////////////
+	r.IP = ip
+	r.Enabled = state.Enabled
+	if !o.ModifyLb {
+		err = fmt.Errorf("%s does not support maintenance", o.Route)
+		r.LastError = err.Error()
+		return r, err
+	}
+	if net.ParseIP(ip) == nil {
+		err = fmt.Errorf("%s is not a valid ip address", ip)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Find every virtual server that binds the ip.
+	////////////////////////////////////////////////////////////////////////////
+	filter := make(map[string][]string)
+	filter["binding_ip"] = []string{ip}
+	collection, err := o.Fetch(filter, 0, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if len(collection.DbRecords) == 0 {
+		err = fmt.Errorf("no virtual servers bind %s", ip)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right. All virtual servers must be writable before any are
+	// changed.
+	////////////////////////////////////////////////////////////////////////////
+	var (
+		poolKeys [][]string
+		codes    []int
+	)
+	for _, v := range collection.DbRecords {
+		var data virtualserver.Data
+		err = shared.MarshalInterface(v.Data, &data)
+		if err != nil {
+			r.LastError = err.Error()
+			return r, err
+		}
+		err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(data.ProductCode))
+		if err != nil {
+			r.LastError = err.Error()
+			return r, err
+		}
+		codes = append(codes, data.ProductCode)
+		target := MaintenanceTarget{
+			ID:             v.ID,
+			Name:           data.Name,
+			LoadBalancerIP: v.LoadBalancerIP,
+			Status:         OperationRunning,
+		}
+		var keys []string
+		for _, p := range data.Pools {
+			if !pool.HasBinding(&p, ip) {
+				continue
+			}
+			target.Pools = append(target.Pools, p.Name)
+			key := p.SourceUUID
+			if key == "" {
+				key = p.Name
+			}
+			keys = append(keys, key)
+		}
+		r.VirtualServers = append(r.VirtualServers, target)
+		poolKeys = append(poolKeys, keys)
+	}
+	if o.Database.Table == "virtualservers" && oUser.ApprovedBy == "" && approvalEnabled() {
+		if protected := protectedCodes(codes); len(protected) > 0 {
+			err = fmt.Errorf("%w. product codes %v need approval - change their bindings one virtual server at a time", userenv.ErrNotAuthorized, protected)
+			r.LastError = err.Error()
+			return r, err
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Track progress of load balancer changes.
+	////////////////////////////////////////////////////////////////////////////
+	action := "maintenance disable"
+	if state.Enabled {
+		action = "maintenance enable"
+	}
+	op := o.newOperation(action, nil, oUser)
+	r.OperationID = op.OperationID()
+	////////////////////////////////////////////////////////////////////////////
+	result := r
+	result.VirtualServers = make([]MaintenanceTarget, len(r.VirtualServers))
+	copy(result.VirtualServers, r.VirtualServers)
+	go o.maintenance(&result, collection.DbRecords, poolKeys, state, oUser, op, log)
+	return r, nil
+}
+
+// maintenance - applies the binding state to each virtual server. Clusters
+// are worked in parallel; virtual servers on the same cluster one at a time.
+func (o *Common) maintenance(r *MaintenanceRecord, dbRecords []DbRecord, poolKeys [][]string, state pool.MemberBinding, oUser *userenv.User, op *Operation, log *logrus.Entry) {
+	////////////////////////////////////////////////////////////////////////////
+	clusters := make(map[string][]int)
+	for k, v := range dbRecords {
+		clusters[v.LoadBalancerIP] = append(clusters[v.LoadBalancerIP], k)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var wg sync.WaitGroup
+	for lbIP, indexes := range clusters {
+		wg.Add(1)
+		// Each cluster gets its own entry - the sdk rewrites it in place.
+		go func(indexes []int, log *logrus.Entry) {
+			defer wg.Done()
+			for _, k := range indexes {
+				target := &r.VirtualServers[k]
+				done := shared.Track(op, fmt.Sprintf("%s on %s", target.Name, target.LoadBalancerIP))
+				var err error
+				for _, key := range poolKeys[k] {
+					err = o.applyBindingState(&dbRecords[k], key, r.IP, state, oUser, nil, log)
+					if err != nil {
+						break
+					}
+				}
+				done(err)
+				target.Status = OperationComplete
+				if err != nil {
+					target.Status = OperationFailed
+					target.LastError = err.Error()
+				}
+			}
+		}(indexes, log.WithField("load_balancer_ip", lbIP))
+	}
+	wg.Wait()
+	////////////////////////////////////////////////////////////////////////////
+	var failed int
+	for _, v := range r.VirtualServers {
+		if v.Status == OperationFailed {
+			failed++
+		}
+	}
+	var err error
+	if failed > 0 {
+		err = fmt.Errorf("%d of %d virtual servers failed", failed, len(r.VirtualServers))
+		r.LastError = err.Error()
+	}
+	op.Finish(r, err)
+}
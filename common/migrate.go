@@ -3,6 +3,7 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ticketmaster/nitro-go-sdk/model"
@@ -94,6 +95,15 @@ func (o *Common) StageMigration(body []byte, id string, oUser *userenv.User) (r
 	targetDbRecord := sourceDbRecord
 	var targetData virtualserver.Data
 	shared.MarshalInterface(targetDbRecord.Data, &targetData)
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right.
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.HasRight(userenv.ActionMigrate, strconv.Itoa(targetData.ProductCode))
+	if err != nil {
+		m.Response.ReadinessChecks.Error = err.Error()
+		r.Data = m.Response
+		return r, err
+	}
 	m.Response.SourceID = sourceDbRecord.ID
 	m.Response.Source.VirtualServer = sourceDbRecord.Data
 	////////////////////////////////////////////////////////////////////////////
@@ -185,6 +195,17 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	mDbo.Setting = config.GlobalConfig
 	mDbo.ModifyLb = false
 	////////////////////////////////////////////////////////////////////////////
+	// Protected product codes wait for the approval of a second user.
+	////////////////////////////////////////////////////////////////////////////
+	if d, pending, err := o.requestApproval(ChangeRequest{Action: "migrate", RecordID: id}, nil, oUser); pending || err != nil {
+		return d, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var before, after *DbRecord
+	defer func() {
+		o.audit("migrate", before, after, err, oUser)
+	}()
+	////////////////////////////////////////////////////////////////////////////
 	err = SetSources()
 	if err != nil {
 		return
@@ -203,6 +224,27 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	shared.MarshalInterface(data.Source.VirtualServer, &sourceData)
 	var targetData virtualserver.Data
 	shared.MarshalInterface(data.Target.VirtualServer, &targetData)
+	before = &DbRecord{ID: data.SourceID, LoadBalancerIP: data.SourceLoadBalancer, Data: sourceData}
+	after = &DbRecord{ID: data.SourceID, LoadBalancerIP: data.TargetLoadBalancer, Data: targetData}
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right.
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.HasRight(userenv.ActionMigrate, strconv.Itoa(sourceData.ProductCode))
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Track progress of load balancer changes.
+	////////////////////////////////////////////////////////////////////////////
+	op := o.newOperation("migrate", &DbRecord{ID: data.SourceID, LoadBalancerIP: data.TargetLoadBalancer, Data: targetData}, oUser)
+	defer func() {
+		r.OperationID = op.OperationID()
+		if err != nil {
+			op.Finish(nil, err)
+			return
+		}
+		op.Finish(r, nil)
+	}()
 	////////////////////////////////////////////////////////////////////////////
 	// Test Migrated or Migrating.
 	////////////////////////////////////////////////////////////////////////////
@@ -253,38 +295,9 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	////////////////////////////////////////////////////////////////////////////
 	// Disable vip
 	////////////////////////////////////////////////////////////////////////////
-	shared.MarshalInterface(data.Source.VirtualServer, &sourceData)
-	targets := []string{sourceData.SourceUUID}
-	targets = append(targets, data.ReadinessChecks.DependencyStatus.IPs...)
-	for _, v := range targets {
-		req := model.LbvserverDisable{
-			Lbvserver: model.LbvserverEnableDisableBody{
-				Name: v,
-			},
-		}
-		err = nsr.Client.DisableLbvserver(req)
-		if err != nil {
-			return
-		}
-	}
-	////////////////////////////////////////////////////////////////////////////
-	// Disable ip
-	////////////////////////////////////////////////////////////////////////////
-	nsip, err := nsr.Client.GetNsip(sourceData.IP)
-	if err != nil {
-		return
-	}
-	var nsipUpdate model.NsipUpdateBody
-	shared.MarshalInterface(nsip, &nsipUpdate)
-	nsipUpdate.Arp = "DISABLED"
-	nsipUpdate.Arpresponse = "NONE"
-	nsipUpdate.Icmpresponse = "NONE"
-	_, err = nsr.Client.UpdateNsip(model.NsipUpdate{Nsip: nsipUpdate})
-	if err != nil {
-		return
-	}
-	o.Log.Warningf("disabling ip on nsr %s %+v", sourceData.IP, nsipUpdate)
-	err = nsr.Client.DisableNsip(model.NsipDisable{Nsip: model.NsipEnableDisableBody{Ipaddress: sourceData.IP}})
+	done := shared.Track(op, "vs disable")
+	err = o.disableSource(nsr, &sourceData, data)
+	done(err)
 	if err != nil {
 		return
 	}
@@ -320,6 +333,8 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	// Create vip on target
 	////////////////////////////////////////////////////////////////////////////
 	aviVs := virtualserver.NewAvi(avi.Client, nil, nil)
+	aviVs.Tracker = op
+	aviVs.Pool.Tracker = op
 	err = aviVs.Create(&targetData)
 	if err != nil {
 		return
@@ -331,7 +346,12 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	if err != nil {
 		return
 	}
+	aviID, err := shared.NewUUID()
+	if err != nil {
+		return
+	}
 	aviDbRecord := &DbRecord{
+		ID:             aviID,
 		LoadBalancerIP: data.TargetLoadBalancer,
 		Data:           targetData,
 		Source:         "migrate",
@@ -340,8 +360,27 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	////////////////////////////////////////////////////////////////////////
 	// Prepare SQL statement for submission.
 	////////////////////////////////////////////////////////////////////////
+	done = shared.Track(op, "db write")
+	////////////////////////////////////////////////////////////////////////
+	// The Avi record is written under a new id, then takes over the id of
+	// the virtual server while the migrated Netscaler record moves to a new
+	// one, in one transaction. A failed write leaves the Netscaler record
+	// where it was.
+	////////////////////////////////////////////////////////////////////////
 	err = dbo.createDbRecord(aviDbRecord, oUser)
 	if err != nil {
+		done(err)
+		return
+	}
+	nsrID, err := shared.NewUUID()
+	if err == nil {
+		err = dbo.promoteDbRecord(data.SourceID, aviID, nsrID)
+	}
+	if err != nil {
+		if deleteErr := dbo.deleteDbRecord(NewDeleteConf(aviDbRecord, oUser, nil)); deleteErr != nil {
+			o.Log.Warn(deleteErr)
+		}
+		done(err)
 		return
 	}
 	////////////////////////////////////////////////////////////////////////
@@ -366,11 +405,12 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 	}
 	err = mDbo.modifyDbRecord(migrateModifyConf)
 	if err != nil {
+		done(err)
 		return
 	}
 	////////////////////////////////////////////////////////////////////////
 	nsrDbRecord = &DbRecord{
-		ID:             data.SourceID,
+		ID:             nsrID,
 		LoadBalancerIP: data.SourceLoadBalancer,
 		Data:           *nsrData,
 		Source:         "migrate",
@@ -386,12 +426,49 @@ func (o *Common) Migrate(id string, oUser *userenv.User) (r DbRecord, err error)
 		Log:      nil,
 	}
 	err = dbo.modifyDbRecord(nsrModifyConf)
+	done(err)
 	if err != nil {
 		return
 	}
 	return *migrateRecord, nil
 }
 
+// disableSource - disables the source vip, its dependencies and the nsip.
+func (o *Common) disableSource(nsr *sdkfork.Netscaler, sourceData *virtualserver.Data, data migrate.Response) (err error) {
+	shared.MarshalInterface(data.Source.VirtualServer, sourceData)
+	targets := []string{sourceData.SourceUUID}
+	targets = append(targets, data.ReadinessChecks.DependencyStatus.IPs...)
+	for _, v := range targets {
+		req := model.LbvserverDisable{
+			Lbvserver: model.LbvserverEnableDisableBody{
+				Name: v,
+			},
+		}
+		err = nsr.Client.DisableLbvserver(req)
+		if err != nil {
+			return
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Disable ip
+	////////////////////////////////////////////////////////////////////////////
+	nsip, err := nsr.Client.GetNsip(sourceData.IP)
+	if err != nil {
+		return
+	}
+	var nsipUpdate model.NsipUpdateBody
+	shared.MarshalInterface(nsip, &nsipUpdate)
+	nsipUpdate.Arp = "DISABLED"
+	nsipUpdate.Arpresponse = "NONE"
+	nsipUpdate.Icmpresponse = "NONE"
+	_, err = nsr.Client.UpdateNsip(model.NsipUpdate{Nsip: nsipUpdate})
+	if err != nil {
+		return
+	}
+	o.Log.Warningf("disabling ip on nsr %s %+v", sourceData.IP, nsipUpdate)
+	return nsr.Client.DisableNsip(model.NsipDisable{Nsip: model.NsipEnableDisableBody{Ipaddress: sourceData.IP}})
+}
+
 func migrateValidate(dbRecord *DbRecord) (ok bool, err error) {
 	////////////////////////////////////////////////////////////////////////////
 	// Marshal data interface.
@@ -5,6 +5,8 @@ import (
 
 	"github.com/ticketmaster/lbapi/config"
 	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,6 +18,8 @@ type Common struct {
 	Setting  *config.Setting
 	Routes   map[string]string
 	Log      *logrus.Entry
+	// Created - called once the Create pipeline has written a record.
+	Created func(d *DbRecord, oUser *userenv.User)
 }
 
 // Sources - resource configuration.
@@ -87,19 +91,72 @@ type VsDbRecordCollection struct {
 
 // DbRecord - fields associated with the default response.
 type DbRecord struct {
-	Data           interface{} `json:"data,omitempty"`
+	// Data - resource configuration. The schema depends on the route.
+	Data interface{} `json:"data,omitempty"`
+	// ID - id of the record. Leave empty on create.
+	ID string `json:"id,omitempty"`
+	// Platform - avi networks or netscaler.
+	Platform string `json:"platform,omitempty"`
+	// LastError [system] - error returned by the last request.
+	LastError string `json:"last_error,omitempty"`
+	// LastModified [system] - time the record was last written.
+	LastModified string `json:"last_modified,omitempty"`
+	// LoadBalancer [system] - cluster the record lives on.
+	LoadBalancer Cluster `json:"_load_balancer,omitempty"`
+	// LoadBalancerIP - cluster ip of the target load balancer.
+	LoadBalancerIP string `json:"load_balancer_ip,omitempty"`
+	// LastModifiedBy [system] - user that last wrote the record.
+	LastModifiedBy string `json:"last_modified_by,omitempty"`
+	// Md5Hash [system] - hash of the stored record. Returned as the ETag.
+	Md5Hash string `json:"_md5hash,omitempty"`
+	// OperationID [system] - operation tracking the request.
+	OperationID string `json:"_operation_id,omitempty"`
+	// ApprovalID [system] - change request waiting for approval instead of
+	// the request being applied.
+	ApprovalID string `json:"_approval_id,omitempty"`
+	// SQLMessage [system] - sql summary.
+	SQLMessage SQLMessage `json:"_sql_message,omitempty"`
+	// Source [system] - load balancer the record was read from.
+	Source string `json:"_source,omitempty"`
+	// Status [system] - status of the record.
+	Status string `json:"_source_status,omitempty"`
+	// StatusID [system] - status code of the record.
+	StatusID int32 `json:"_source_status_id,omitempty"`
+}
+
+// PlanRecord - dry run response. Describes the changes a modify would apply.
+type PlanRecord struct {
 	ID             string      `json:"id,omitempty"`
-	Platform       string      `json:"platform,omitempty"`
-	LastError      string      `json:"last_error,omitempty"`
-	LastModified   string      `json:"last_modified,omitempty"`
-	LoadBalancer   Cluster     `json:"_load_balancer,omitempty"`
 	LoadBalancerIP string      `json:"load_balancer_ip,omitempty"`
-	LastModifiedBy string      `json:"last_modified_by,omitempty"`
-	Md5Hash        string      `json:"_md5hash,omitempty"`
-	SQLMessage     SQLMessage  `json:"_sql_message,omitempty"`
-	Source         string      `json:"_source,omitempty"`
-	Status         string      `json:"_source_status,omitempty"`
-	StatusID       int32       `json:"_source_status_id,omitempty"`
+	Plan           interface{} `json:"plan,omitempty"`
+	LastError      string      `json:"last_error,omitempty"`
+}
+
+// ErrorRecord - response returned when a payload fails validation.
+type ErrorRecord struct {
+	// LastError - summary of every invalid field.
+	LastError string `json:"last_error"`
+	// Errors - invalid fields of the payload.
+	Errors []shared.FieldError `json:"errors,omitempty"`
+}
+
+// MaintenanceRecord - fleet wide binding state change response.
+type MaintenanceRecord struct {
+	IP             string              `json:"ip"`
+	Enabled        bool                `json:"enabled"`
+	OperationID    string              `json:"_operation_id,omitempty"`
+	VirtualServers []MaintenanceTarget `json:"virtual_servers,omitempty"`
+	LastError      string              `json:"last_error,omitempty"`
+}
+
+// MaintenanceTarget - virtual server changed by a maintenance request.
+type MaintenanceTarget struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	LoadBalancerIP string   `json:"load_balancer_ip"`
+	Pools          []string `json:"pools"`
+	Status         string   `json:"status"`
+	LastError      string   `json:"last_error,omitempty"`
 }
 
 // VsDbRecord - fields associated with the default response.
@@ -123,6 +180,7 @@ type DbResponseRecord struct {
 	Md5Hash        sql.NullString `json:"md5hash,omitempty"`
 	Source         sql.NullString `json:"_source,omitempty"`
 	Status         sql.NullString `json:"_source_status,omitempty"`
+	SortKey        sql.NullString `json:"-"`
 }
 
 // VsDbResponseRecord - database response.
@@ -132,6 +190,8 @@ type VsDbResponseRecord struct {
 	LoadBalancerIP sql.NullString `json:"load_balancer_ip,omitempty"`
 	Platform       sql.NullString `json:"platform,omitempty"`
 	ServiceType    sql.NullString `json:"service_type,omitempty"`
+	ID             sql.NullString `json:"-"`
+	SortKey        sql.NullString `json:"-"`
 }
 
 // SQLMessage - sql summary response.
@@ -139,6 +199,7 @@ type SQLMessage struct {
 	LastInsertId string `json:"_last_insert_id,omitempty"`
 	Rows         int    `json:"_rows,omitempty"`
 	Next         string `json:"_next,omitempty"`
+	Cursor       string `json:"_cursor,omitempty"`
 	Total        int    `json:"_total,omitempty"`
 	RowsAffected int64  `json:"_rows_affected,omitempty"`
 }
@@ -148,6 +209,9 @@ type Filter struct {
 	Table           string
 	URLQueryParams  map[string][]string
 	NextQueryParams *ParamCollection
+	// ProductCodes - when not nil, only records of these product codes are
+	// returned from the tables in scopedTables.
+	ProductCodes []string
 }
 
 // ParamCollection - used for recordset paging and filtering.
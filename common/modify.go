@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/ticketmaster/lbapi/virtualserver"
 
@@ -30,6 +29,16 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	*log = *o.Log
 	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "modify", "route": o.Route})
 	////////////////////////////////////////////////////////////////////////////
+	// Protected product codes wait for the approval of a second user.
+	////////////////////////////////////////////////////////////////////////////
+	if d, pending, err := o.requestApproval(ChangeRequest{Action: "modify"}, p, oUser); pending || err != nil {
+		return d, err
+	}
+	var before *DbRecord
+	defer func() {
+		o.audit("modify", before, &r, err, oUser)
+	}()
+	////////////////////////////////////////////////////////////////////////////
 	// Unmarshal payload to []DbRecord.
 	////////////////////////////////////////////////////////////////////////////
 	var clientDbRecord DbRecord
@@ -58,7 +67,7 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	////////////////////////////////////////////////////////////////////////
 	// Validate Right.
 	////////////////////////////////////////////////////////////////////////
-	err = oUser.HasAdminRight(strconv.Itoa(genericData.ProductCode))
+	err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(genericData.ProductCode))
 	if err != nil {
 		clientDbRecord.LastError = err.Error()
 		return clientDbRecord, err
@@ -66,16 +75,11 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	////////////////////////////////////////////////////////////////////////
 	// Test - Validate payload meets min requirements for submission.
 	////////////////////////////////////////////////////////////////////////
-	validated, err := o.Database.Validate(&clientDbRecord)
+	err = o.validate(&clientDbRecord)
 	if err != nil {
 		clientDbRecord.LastError = err.Error()
 		return clientDbRecord, err
 	}
-	if !validated {
-		err = fmt.Errorf("payload not validated - %+v", clientDbRecord.Data)
-		clientDbRecord.LastError = err.Error()
-		return clientDbRecord, err
-	}
 	////////////////////////////////////////////////////////////////////////
 	// LoadBalancer Operations - Forces loadbalancer to retrieve facts.
 	////////////////////////////////////////////////////////////////////////
@@ -96,7 +100,20 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 		return clientDbRecord, err
 	}
 	if !dbRecordExists {
-		err = fmt.Errorf("no database record found with id %v", clientDbRecord.ID)
+		err = fmt.Errorf("%w - no database record found with id %v", ErrNotFound, clientDbRecord.ID)
+		clientDbRecord.LastError = err.Error()
+		return clientDbRecord, err
+	}
+	clientDbRecord.ID = databaseRecord.ID
+	before = o.auditRecord(databaseRecord.ID, oUser)
+	////////////////////////////////////////////////////////////////////////
+	// Test - Record has not changed since the client fetched it.
+	////////////////////////////////////////////////////////////////////////
+	ifMatch, err := o.checkIfMatch(databaseRecord.ID, oUser)
+	if err == nil {
+		_, err = o.claimIfMatch(databaseRecord.ID, ifMatch, oUser)
+	}
+	if err != nil {
 		clientDbRecord.LastError = err.Error()
 		return clientDbRecord, err
 	}
@@ -104,7 +121,23 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	// Test - Record exists in lb.
 	////////////////////////////////////////////////////////////////////////////
 	if o.ModifyLb {
+		////////////////////////////////////////////////////////////////////////
+		// Track progress of load balancer changes.
+		////////////////////////////////////////////////////////////////////////
+		op := o.newOperation("modify", &clientDbRecord, oUser)
+		clientDbRecord.OperationID = op.OperationID()
+		sdk.Tracker = op
 		go func(clientDbRecord *DbRecord, o *Common, oUser *userenv.User) {
+			////////////////////////////////////////////////////////////////////
+			// Every failure below records last_error before returning.
+			////////////////////////////////////////////////////////////////////
+			defer func() {
+				if clientDbRecord.LastError != "" {
+					op.Finish(nil, errors.New(clientDbRecord.LastError))
+					return
+				}
+				op.Finish(clientDbRecord, nil)
+			}()
 			////////////////////////////////////////////////////////////////////
 			// Set updating status.
 			////////////////////////////////////////////////////////////////////
@@ -149,7 +182,9 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 				////////////////////////////////////////////////////////////////
 				// Modify only if the dbRecord is different from the lbRecord.
 				////////////////////////////////////////////////////////////////
+				done := shared.Track(op, "vs modify")
 				modified, err := sdk.Modify(clientDbRecord.Data, o.Route)
+				done(err)
 				if err != nil {
 					clientDbRecord.LastError = err.Error()
 					err = o.setStatusDbRecord(clientDbRecord, 1, oUser)
@@ -188,7 +223,9 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 			////////////////////////////////////////////////////////////////////////
 			// Prepare SQL statement for submission.
 			////////////////////////////////////////////////////////////////////////
-			err = o.updateDbRecord(qry, clientDbRecord)
+			done := shared.Track(op, "db write")
+			err = o.updateDbRecord(qry, conf)
+			done(err)
 			if err != nil {
 				clientDbRecord.LastError = err.Error()
 				err = o.setStatusDbRecord(clientDbRecord, 2, oUser)
@@ -209,7 +246,7 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 	////////////////////////////////////////////////////////////////////////
 	// Prepare SQL statement for submission.
 	////////////////////////////////////////////////////////////////////////
-	err = o.updateDbRecord(qry, &clientDbRecord)
+	err = o.updateDbRecord(qry, conf)
 	if err != nil {
 		clientDbRecord.LastError = err.Error()
 		return clientDbRecord, err
@@ -218,14 +255,20 @@ func (o *Common) Modify(p []byte, oUser *userenv.User) (r DbRecord, err error) {
 
 }
 
-// ModifyBulk updates object record.
-func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollection, err error) {
+// ModifyBulk updates every record in p and returns the outcome of each. When
+// atomic is set, every record is checked before any record is changed and the
+// request is aborted if one fails.
+func (o *Common) ModifyBulk(p []byte, atomic bool, oUser *userenv.User) (toR BulkRecord, err error) {
 	////////////////////////////////////////////////////////////////////////////
 	log := logrus.NewEntry(logrus.New())
 	*log = *o.Log
 	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "modify", "route": o.Route})
 	////////////////////////////////////////////////////////////////////////////
 	r := DbRecordCollection{}
+	befores := make(map[string]*DbRecord)
+	defer func() {
+		o.auditBulk("modify", befores, r.DbRecords, err, oUser)
+	}()
 	////////////////////////////////////////////////////////////////////////////
 	// Unmarshal payload to []DbRecord.
 	////////////////////////////////////////////////////////////////////////////
@@ -234,10 +277,38 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 	if err != nil {
 		return
 	}
+	for _, d := range dbRecords {
+		if before := o.auditRecord(d.ID, oUser); before != nil {
+			befores[d.ID] = before
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Validate every record before any changes are made.
+	////////////////////////////////////////////////////////////////////////////
+	err = o.validateBulkFields(dbRecords)
+	if err != nil {
+		return
+	}
+	if atomic {
+		toR, err = o.precheckModifyBulk(dbRecords, oUser)
+		if err != nil {
+			return
+		}
+	}
 	////////////////////////////////////////////////////////////////////////////
 	// Collect records for submission to Database.
 	////////////////////////////////////////////////////////////////////////////
 	for _, d := range dbRecords {
+		////////////////////////////////////////////////////////////////////////
+		// Protected product codes wait for the approval of a second user.
+		////////////////////////////////////////////////////////////////////////
+		if approval, pending, err := o.requestBulkApproval("modify", d, oUser); pending || err != nil {
+			if err != nil {
+				log.Warn(err)
+			}
+			r.DbRecords = append(r.DbRecords, approval)
+			continue
+		}
 		////////////////////////////////////////////////////////////////////////
 		// Set target.
 		////////////////////////////////////////////////////////////////////////
@@ -257,13 +328,6 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 			StatusID:       d.StatusID,
 		}
 		////////////////////////////////////////////////////////////////////////
-		// Set updating status.
-		////////////////////////////////////////////////////////////////////////
-		err = o.setStatusDbRecord(clientDbRecord, 6, oUser)
-		if err != nil {
-			log.Warn(err)
-		}
-		////////////////////////////////////////////////////////////////////////
 		// Unmarshal Data into generic genericData.
 		////////////////////////////////////////////////////////////////////////
 		var genericData Data
@@ -277,7 +341,7 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 		////////////////////////////////////////////////////////////////////////
 		// Validate Right.
 		////////////////////////////////////////////////////////////////////////
-		err = oUser.HasAdminRight(strconv.Itoa(genericData.ProductCode))
+		err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(genericData.ProductCode))
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *clientDbRecord)
@@ -287,20 +351,13 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 		////////////////////////////////////////////////////////////////////////
 		// Test - Validate payload meets min requirements for submission.
 		////////////////////////////////////////////////////////////////////////
-		validated, err := o.Database.Validate(clientDbRecord)
+		err = o.validate(clientDbRecord)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *clientDbRecord)
 			log.Warn(err)
 			continue
 		}
-		if !validated {
-			err = fmt.Errorf("payload not validated - %+v", clientDbRecord.Data)
-			clientDbRecord.LastError = err.Error()
-			r.DbRecords = append(r.DbRecords, *clientDbRecord)
-			log.Warn(err)
-			continue
-		}
 		////////////////////////////////////////////////////////////////////////
 		// LoadBalancer Operations - Forces loadbalancer to retrieve facts.
 		////////////////////////////////////////////////////////////////////////
@@ -328,12 +385,21 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 			continue
 		}
 		if !dbRecordExists {
-			err = fmt.Errorf("no database record found with id %v", clientDbRecord.ID)
+			err = fmt.Errorf("%w - no database record found with id %v", ErrNotFound, clientDbRecord.ID)
 			clientDbRecord.LastError = err.Error()
 			r.DbRecords = append(r.DbRecords, *clientDbRecord)
 			log.Warn(err)
 			continue
 		}
+		clientDbRecord.ID = databaseRecord.ID
+		////////////////////////////////////////////////////////////////////////
+		// Set updating status once the record is known to be the user's to
+		// change.
+		////////////////////////////////////////////////////////////////////////
+		err = o.setStatusDbRecord(clientDbRecord, 6, oUser)
+		if err != nil {
+			log.Warn(err)
+		}
 		////////////////////////////////////////////////////////////////////////
 		// Test - Record exists in lb.
 		////////////////////////////////////////////////////////////////////////
@@ -422,15 +488,17 @@ func (o *Common) ModifyBulk(p []byte, oUser *userenv.User) (toR DbRecordCollecti
 		////////////////////////////////////////////////////////////////////////
 		// Prepare SQL statement for submission.
 		////////////////////////////////////////////////////////////////////////
-		err = o.updateDbRecord(qry, clientDbRecord)
+		err = o.updateDbRecord(qry, conf)
 		if err != nil {
 			clientDbRecord.LastError = err.Error()
 			log.Warn(err)
 		}
 		r.DbRecords = append(r.DbRecords, *clientDbRecord)
 	}
-	toR = r
-	return
+	for _, v := range r.DbRecords {
+		toR.Results = append(toR.Results, newBulkResult(v, nil))
+	}
+	return toR, nil
 }
 
 // modifyDbRecord updates object record.
@@ -469,6 +537,7 @@ func (o *Common) modifyDbRecord(conf *ModifyConf) (err error) {
 		err = fmt.Errorf("database record %s does not exist", databaseRecord.ID)
 		return
 	}
+	request.ID = databaseRecord.ID
 	////////////////////////////////////////////////////////////////////////
 	// Set DbData.
 	////////////////////////////////////////////////////////////////////////
@@ -480,7 +549,7 @@ func (o *Common) modifyDbRecord(conf *ModifyConf) (err error) {
 	////////////////////////////////////////////////////////////////////////
 	*conf.DbRecord = *request
 	qry := o.etlDbRecordUpdate(conf)
-	return o.updateDbRecord(qry, conf.DbRecord)
+	return o.updateDbRecord(qry, conf)
 }
 
 // etlDbRecordUpdate prepares record for Db submission.
@@ -488,15 +557,22 @@ func (o *Common) etlDbRecordUpdate(conf *ModifyConf) (sql string) {
 	dbRecord := conf.DbRecord
 	jsonData := shared.ToJSON(dbRecord.Data)
 	jsonLoadBalancer := shared.ToJSON(GlobalSources.Clusters[dbRecord.LoadBalancerIP])
-	dbRecord.Md5Hash = shared.GetMD5Hash(strings.ToLower(jsonData))
+	dbRecord.Md5Hash = md5Hash(jsonData)
+	var alias string
+	if o.Database.Table == "virtualservers" {
+		key, _ := o.aliasKey(dbRecord)
+		alias = `
+		alias='` + key + `',`
+	}
 	sql = `
 	UPDATE public.` + o.Database.Table + `
-	SET
+	SET` + alias + `
 		data='` + jsonData + `',
 		last_modified=current_timestamp,
 		load_balancer_ip='` + dbRecord.LoadBalancerIP + `',
 		source='` + dbRecord.Source + `',
 		load_balancer='` + jsonLoadBalancer + `',
+		md5hash='` + dbRecord.Md5Hash + `',
 		last_modified_by='` + conf.User.Username + `'
 		WHERE
 		id='` + dbRecord.ID + `'`
@@ -504,7 +580,8 @@ func (o *Common) etlDbRecordUpdate(conf *ModifyConf) (sql string) {
 }
 
 // updateDbRecord updates database record.
-func (o *Common) updateDbRecord(qry string, dbRecord *DbRecord) (err error) {
+func (o *Common) updateDbRecord(qry string, conf *ModifyConf) (err error) {
+	dbRecord := conf.DbRecord
 	////////////////////////////////////////////////////////////////////////////
 	// Set database connection.
 	////////////////////////////////////////////////////////////////////////////
@@ -521,5 +598,8 @@ func (o *Common) updateDbRecord(qry string, dbRecord *DbRecord) (err error) {
 		return
 	}
 	dbRecord.SQLMessage.RowsAffected, _ = result.RowsAffected()
+	if dbRecord.SQLMessage.RowsAffected > 0 {
+		o.publishRecord("modified", dbRecord, conf.User)
+	}
 	return
 }
@@ -0,0 +1,217 @@
+package common
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+const (
+	// OperationRunning - operation or step is in progress.
+	OperationRunning = "running"
+	// OperationComplete - operation or step finished without error.
+	OperationComplete = "complete"
+	// OperationFailed - operation or step finished with an error.
+	OperationFailed = "fail"
+)
+
+// Operation - tracks the progress of an asynchronous create, modify, delete
+// or migrate request. Operations are stored in the operations table and
+// updated as each step starts and finishes.
+type Operation struct {
+	// ID - operation id.
+	ID string `json:"id"`
+	// Action - create, modify, delete, migrate, binding enable, binding
+	// disable, maintenance enable or maintenance disable.
+	Action string `json:"action"`
+	// Route - route the request was made against.
+	Route string `json:"route"`
+	// RecordID - id of the record being changed.
+	RecordID string `json:"record_id,omitempty"`
+	// LoadBalancerIP - cluster ip of the target load balancer.
+	LoadBalancerIP string `json:"load_balancer_ip,omitempty"`
+	// ProductCode - product code associated with the record.
+	ProductCode int `json:"product_code,omitempty"`
+	// User - user that submitted the request.
+	User string `json:"user"`
+	// Status - running, complete or fail.
+	Status string `json:"status"`
+	// Steps - steps in the order they were started.
+	Steps []OperationStep `json:"steps"`
+	// Result - resulting record once the operation has finished.
+	Result interface{} `json:"result,omitempty"`
+	// LastError - error that failed the operation.
+	LastError string `json:"last_error,omitempty"`
+	// Started - time the operation was submitted.
+	Started string `json:"started"`
+	// Finished - time the operation finished.
+	Finished string `json:"finished,omitempty"`
+	////////////////////////////////////////////////////////////////////////////
+	mu  sync.Mutex
+	log *logrus.Entry
+}
+
+// OperationStep - single step of an operation.
+type OperationStep struct {
+	// Name - ip allocation, dns, pool create, monitor create, vs create, db
+	// write etc.
+	Name string `json:"name"`
+	// Status - running, complete or fail.
+	Status string `json:"status"`
+	// Started - time the step started.
+	Started string `json:"started"`
+	// Finished - time the step finished.
+	Finished string `json:"finished,omitempty"`
+	// Error - error returned by the step.
+	Error string `json:"error,omitempty"`
+}
+
+// newOperation - registers an operation for a mutating request. Failures to
+// persist the operation are logged and never fail the request.
+func (o *Common) newOperation(action string, dbRecord *DbRecord, oUser *userenv.User) *Operation {
+	////////////////////////////////////////////////////////////////////////////
+	op := &Operation{
+		Action:  action,
+		Route:   o.Route,
+		User:    oUser.Username,
+		Status:  OperationRunning,
+		Steps:   []OperationStep{},
+		Started: operationTime(),
+		log:     o.Log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "operation", "action": action}),
+	}
+	op.setRecord(dbRecord)
+	////////////////////////////////////////////////////////////////////////////
+	var err error
+	op.ID, err = shared.NewUUID()
+	if err != nil {
+		op.log.Warn(err)
+		return op
+	}
+	op.log = op.log.WithField("operation", op.ID)
+	////////////////////////////////////////////////////////////////////////////
+	p, err := json.Marshal(op)
+	if err != nil {
+		op.log.Warn(err)
+		return op
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`INSERT INTO public.operations (id, data, source, last_modified, load_balancer_ip, last_modified_by) VALUES ($1, $2, $3, current_timestamp, $4, $5)`, op.ID, string(p), op.Route, op.LoadBalancerIP, op.User)
+	if err != nil {
+		op.log.Warn(err)
+	}
+	return op
+}
+
+// SetRecord - associates the operation with the record being changed.
+func (op *Operation) SetRecord(dbRecord *DbRecord) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	op.setRecord(dbRecord)
+	op.mu.Unlock()
+	op.save()
+}
+
+// Start - implements shared.Tracker.
+func (op *Operation) Start(step string) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	op.Steps = append(op.Steps, OperationStep{
+		Name:    step,
+		Status:  OperationRunning,
+		Started: operationTime(),
+	})
+	op.mu.Unlock()
+	op.save()
+}
+
+// Done - implements shared.Tracker. Completes the most recent running step
+// with a matching name.
+func (op *Operation) Done(step string, err error) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	for k := len(op.Steps) - 1; k >= 0; k-- {
+		if op.Steps[k].Name != step || op.Steps[k].Status != OperationRunning {
+			continue
+		}
+		op.Steps[k].Finished = operationTime()
+		op.Steps[k].Status = OperationComplete
+		if err != nil {
+			op.Steps[k].Status = OperationFailed
+			op.Steps[k].Error = err.Error()
+		}
+		break
+	}
+	op.mu.Unlock()
+	op.save()
+}
+
+// Finish - records the final result of the operation.
+func (op *Operation) Finish(result interface{}, err error) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	op.Result = result
+	op.Finished = operationTime()
+	op.Status = OperationComplete
+	if err != nil {
+		op.Status = OperationFailed
+		op.LastError = err.Error()
+	}
+	op.mu.Unlock()
+	op.save()
+}
+
+// OperationID - returns the id of the operation. Safe to call on nil.
+func (op *Operation) OperationID() string {
+	if op == nil {
+		return ""
+	}
+	return op.ID
+}
+
+// setRecord - copies record facts to the operation. Caller holds the lock.
+func (op *Operation) setRecord(dbRecord *DbRecord) {
+	if dbRecord == nil {
+		return
+	}
+	var data Data
+	shared.MarshalInterface(dbRecord.Data, &data)
+	op.RecordID = dbRecord.ID
+	op.LoadBalancerIP = dbRecord.LoadBalancerIP
+	op.ProductCode = data.ProductCode
+}
+
+// save - writes the operation to the database. The lock is held until the
+// write is done, so that concurrent steps never write an older snapshot over
+// a newer one.
+func (op *Operation) save() {
+	if op.ID == "" {
+		return
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	p, err := json.Marshal(op)
+	if err != nil {
+		op.log.Warn(err)
+		return
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`UPDATE public.operations SET data=$1, last_modified=current_timestamp, load_balancer_ip=$2, last_error=$3 WHERE id=$4`, string(p), op.LoadBalancerIP, op.LastError, op.ID)
+	if err != nil {
+		op.log.Warn(err)
+	}
+}
+
+func operationTime() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
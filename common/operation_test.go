@@ -0,0 +1,82 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOperationSteps(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    func(op *Operation)
+		steps  []OperationStep
+		status string
+	}{
+		{"no steps", func(op *Operation) {}, nil, OperationRunning},
+		{"step running", func(op *Operation) {
+			op.Start("dns")
+		}, []OperationStep{{Name: "dns", Status: OperationRunning}}, OperationRunning},
+		{"step complete", func(op *Operation) {
+			op.Start("dns")
+			op.Done("dns", nil)
+		}, []OperationStep{{Name: "dns", Status: OperationComplete}}, OperationRunning},
+		{"step failed", func(op *Operation) {
+			op.Start("dns")
+			op.Done("dns", errors.New("timeout"))
+		}, []OperationStep{{Name: "dns", Status: OperationFailed, Error: "timeout"}}, OperationRunning},
+		{"done completes the latest running step", func(op *Operation) {
+			op.Start("pool create")
+			op.Done("pool create", nil)
+			op.Start("pool create")
+			op.Done("pool create", nil)
+		}, []OperationStep{{Name: "pool create", Status: OperationComplete}, {Name: "pool create", Status: OperationComplete}}, OperationRunning},
+		{"done without start", func(op *Operation) {
+			op.Done("dns", nil)
+		}, nil, OperationRunning},
+		{"finish", func(op *Operation) {
+			op.Start("vs create")
+			op.Done("vs create", nil)
+			op.Finish("result", nil)
+		}, []OperationStep{{Name: "vs create", Status: OperationComplete}}, OperationComplete},
+		{"finish with error", func(op *Operation) {
+			op.Finish(nil, errors.New("lb unreachable"))
+		}, nil, OperationFailed},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Operations without an id are never saved.
+			op := &Operation{Status: OperationRunning}
+			tt.run(op)
+			if op.Status != tt.status {
+				t.Fatalf("expected status %s, got %s", tt.status, op.Status)
+			}
+			if len(op.Steps) != len(tt.steps) {
+				t.Fatalf("expected %d steps, got %+v", len(tt.steps), op.Steps)
+			}
+			for k, v := range tt.steps {
+				got := op.Steps[k]
+				if got.Name != v.Name || got.Status != v.Status || got.Error != v.Error {
+					t.Fatalf("expected step %+v, got %+v", v, got)
+				}
+				if got.Started == "" || (got.Status != OperationRunning && got.Finished == "") {
+					t.Fatalf("expected step times, got %+v", got)
+				}
+			}
+			if tt.status == OperationFailed && op.LastError == "" {
+				t.Fatal("expected last error")
+			}
+		})
+	}
+}
+
+func TestOperationNil(t *testing.T) {
+	var op *Operation
+	op.Start("dns")
+	op.Done("dns", nil)
+	op.Finish(nil, nil)
+	op.SetRecord(nil)
+	if op.OperationID() != "" {
+		t.Fatal("expected empty operation id")
+	}
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Patch - applies an RFC 7396 merge patch to the data of the stored record
+// and submits the result through Modify. Fields missing from the patch keep
+// their stored values; fields set to null are removed.
+func (o *Common) Patch(id string, p []byte, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	// Get Record By Id. This operation queries the system db.
+	////////////////////////////////////////////////////////////////////////////
+	collection, err := o.FetchByID(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if len(collection.DbRecords) == 0 {
+		err = fmt.Errorf("%w - no records match %s", ErrNotFound, id)
+		r.LastError = err.Error()
+		return r, err
+	}
+	r = collection.DbRecords[0]
+	////////////////////////////////////////////////////////////////////////////
+	// Merge patch onto the stored data.
+	////////////////////////////////////////////////////////////////////////////
+	var patch interface{}
+	err = json.Unmarshal(p, &patch)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if _, ok := patch.(map[string]interface{}); !ok {
+		err = fmt.Errorf("merge patch must be a json object")
+		r.LastError = err.Error()
+		return r, err
+	}
+	stored, err := json.Marshal(r.Data)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	merged, err := shared.MergePatch(stored, p)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Submit the merged record as a full modify request.
+	////////////////////////////////////////////////////////////////////////////
+	clientDbRecord := DbRecord{
+		ID:             r.ID,
+		LoadBalancerIP: r.LoadBalancerIP,
+		Platform:       r.Platform,
+		Source:         r.Source,
+		Data:           json.RawMessage(merged),
+	}
+	payload, err := json.Marshal(clientDbRecord)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	return o.Modify(payload, oUser)
+}
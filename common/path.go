@@ -0,0 +1,180 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/loadbalancer"
+	"github.com/ticketmaster/lbapi/migrate"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+// tableModels - model stored in the data column of each table. Path filters
+// are resolved against it.
+var tableModels = map[string]reflect.Type{
+	"virtualservers": reflect.TypeOf(virtualserver.Data{}),
+	"recycle":        reflect.TypeOf(virtualserver.Data{}),
+	"status":         reflect.TypeOf(virtualserver.Data{}),
+	"loadbalancers":  reflect.TypeOf(loadbalancer.Data{}),
+	"migrate":        reflect.TypeOf(migrate.Response{}),
+	"operations":     reflect.TypeOf(Operation{}),
+	"audit":          reflect.TypeOf(AuditEntry{}),
+	"schedule":       reflect.TypeOf(ScheduledChange{}),
+	"approvals":      reflect.TypeOf(ChangeRequest{}),
+}
+
+// formatPathQry - converts a dotted filter such as pools.bindings.server.ip
+// to a jsonpath matched with @?, which is served by the jsonb_path_ops GIN
+// index on data. Every segment must be a field of the table's model, and the
+// value is written to the jsonpath as a literal of the field's type. The
+// jsonpath itself is bound to args.
+func (f Filter) formatPathQry(field string, op string, val string, args *[]interface{}) (r string, err error) {
+	path, leaf, err := f.jsonPath(field)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var predicate string
+	negate := false
+	switch op {
+	case "", "ne":
+		negate = op == "ne"
+		if strings.Contains(val, "*") && leaf.Kind() == reflect.String {
+			var parts []string
+			for _, v := range strings.Split(val, "*") {
+				parts = append(parts, regexp.QuoteMeta(v))
+			}
+			predicate = "@ like_regex " + pathString("^"+strings.Join(parts, ".*")+"$")
+			break
+		}
+		literal, err := pathLiteral(field, leaf, val)
+		if err != nil {
+			return "", err
+		}
+		predicate = "@ == " + literal
+	case "in":
+		var terms []string
+		for _, v := range strings.Split(val, ",") {
+			literal, err := pathLiteral(field, leaf, strings.TrimSpace(v))
+			if err != nil {
+				return "", err
+			}
+			terms = append(terms, "@ == "+literal)
+		}
+		predicate = strings.Join(terms, " || ")
+	case "gt", "lt":
+		literal, err := pathLiteral(field, leaf, val)
+		if err != nil {
+			return "", err
+		}
+		cmp := " > "
+		if op == "lt" {
+			cmp = " < "
+		}
+		predicate = "@" + cmp + literal
+	case "regex":
+		if _, err = regexp.Compile(val); err != nil {
+			return "", fmt.Errorf("invalid regex for %s - %v", field, err)
+		}
+		predicate = "@ like_regex " + pathString(val)
+	case "exists":
+		exists, err := strconv.ParseBool(val)
+		if err != nil {
+			return "", fmt.Errorf("%s__exists must be true or false", field)
+		}
+		negate = !exists
+	default:
+		return "", fmt.Errorf("unknown operator %q - use ne, in, gt, lt, regex or exists", op)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if predicate != "" {
+		path = path + " ? (" + predicate + ")"
+	}
+	r = "data @? " + bind(args, path) + "::jsonpath"
+	if negate {
+		r = "NOT (" + r + ")"
+	}
+	return
+}
+
+// jsonPath - resolves a dotted field against the table's model and returns
+// the jsonpath of the field, with [*] after every array, and the type of the
+// value it points at.
+func (f Filter) jsonPath(field string) (r string, leaf reflect.Type, err error) {
+	t, ok := tableModels[f.Table]
+	if !ok {
+		return "", nil, fmt.Errorf("%s does not support path filters", f.Table)
+	}
+	r = "$"
+	for _, segment := range strings.Split(field, ".") {
+		t = elem(t)
+		if t.Kind() != reflect.Struct {
+			return "", nil, fmt.Errorf("%q is not a filterable field of %s", field, f.Table)
+		}
+		sf, ok := jsonField(t, segment)
+		if !ok {
+			return "", nil, fmt.Errorf("%q is not a filterable field of %s", field, f.Table)
+		}
+		r = r + "." + pathString(segment)
+		t = sf.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			r = r + "[*]"
+		}
+	}
+	return r, elem(t), nil
+}
+
+// jsonField - returns the field of t serialized as name.
+func jsonField(t reflect.Type, name string) (r reflect.StructField, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == name {
+			return t.Field(i), true
+		}
+	}
+	return
+}
+
+// elem - strips pointers, slices and arrays from t.
+func elem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// pathLiteral - returns val as a jsonpath literal of the leaf type. jsonpath
+// comparisons are strict, so "80" never matches the number 80.
+func pathLiteral(field string, leaf reflect.Type, val string) (r string, err error) {
+	switch leaf.Kind() {
+	case reflect.String:
+		return pathString(val), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return "", fmt.Errorf("%s must be true or false", field)
+		}
+		return strconv.FormatBool(b), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s must be a number", field)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("%s can only be filtered with __exists", field)
+}
+
+// pathString - quotes s as a jsonpath string literal.
+func pathString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
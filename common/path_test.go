@@ -0,0 +1,61 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormatPathQry(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		field string
+		op    string
+		val   string
+		want  string
+		path  string
+		ok    bool
+	}{
+		{"string", "virtualservers", "pools.bindings.server.ip", "", "10.0.0.1", `data @? $1::jsonpath`, `$."pools"[*]."bindings"[*]."server"."ip" ? (@ == "10.0.0.1")`, true},
+		{"number", "virtualservers", "ports.port", "", "443", `data @? $1::jsonpath`, `$."ports"[*]."port" ? (@ == 443)`, true},
+		{"not a number", "virtualservers", "ports.port", "", "https", ``, ``, false},
+		{"bool", "virtualservers", "pools.enabled", "", "false", `data @? $1::jsonpath`, `$."pools"[*]."enabled" ? (@ == false)`, true},
+		{"not a bool", "virtualservers", "pools.enabled", "", "no", ``, ``, false},
+		{"ne", "virtualservers", "pools.bindings.server.ip", "ne", "10.0.0.1", `NOT (data @? $1::jsonpath)`, `$."pools"[*]."bindings"[*]."server"."ip" ? (@ == "10.0.0.1")`, true},
+		{"wildcard", "virtualservers", "pools.name", "", "prd1-*.web", `data @? $1::jsonpath`, `$."pools"[*]."name" ? (@ like_regex "^prd1-.*\\.web$")`, true},
+		{"in", "virtualservers", "ports.port", "in", "80, 443", `data @? $1::jsonpath`, `$."ports"[*]."port" ? (@ == 80 || @ == 443)`, true},
+		{"gt", "virtualservers", "pools.default_port", "gt", "1024", `data @? $1::jsonpath`, `$."pools"[*]."default_port" ? (@ > 1024)`, true},
+		{"regex", "virtualservers", "pools.name", "regex", "^prd", `data @? $1::jsonpath`, `$."pools"[*]."name" ? (@ like_regex "^prd")`, true},
+		{"bad regex", "virtualservers", "pools.name", "regex", "(", ``, ``, false},
+		{"exists", "virtualservers", "pools.health_monitors", "exists", "true", `data @? $1::jsonpath`, `$."pools"[*]."health_monitors"[*]`, true},
+		{"not exists", "virtualservers", "pools.health_monitors", "exists", "false", `NOT (data @? $1::jsonpath)`, `$."pools"[*]."health_monitors"[*]`, true},
+		{"struct compared", "virtualservers", "pools.bindings", "", "x", ``, ``, false},
+		{"unknown segment", "virtualservers", "pools.secret", "", "x", ``, ``, false},
+		{"past a leaf", "virtualservers", "ip.octet", "", "x", ``, ``, false},
+		{"quotes stay in the literal", "virtualservers", "pools.name", "", `a") || (@ == "b`, `data @? $1::jsonpath`, `$."pools"[*]."name" ? (@ == "a\") || (@ == \"b")`, true},
+		{"unknown operator", "virtualservers", "pools.name", "like", "x", ``, ``, false},
+		{"table without a model", "approvals", "pools.name", "", "x", ``, ``, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Table: tt.table}
+			var args []interface{}
+			r, err := f.formatPathQry(tt.field, tt.op, tt.val, &args)
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected filter to build, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %s", r)
+			case !tt.ok:
+				return
+			}
+			if r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+			if fmt.Sprint(args) != fmt.Sprint([]interface{}{tt.path}) {
+				t.Fatalf("expected jsonpath %s, got %v", tt.path, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/sdkfork"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Plan validates a modify request and returns the changes it would apply to
+// the load balancer. Nothing is written to the load balancer or the database.
+func (o *Common) Plan(p []byte, oUser *userenv.User) (r PlanRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	log := logrus.NewEntry(logrus.New())
+	*log = *o.Log
+	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "plan", "route": o.Route})
+	////////////////////////////////////////////////////////////////////////////
+	// Unmarshal payload to DbRecord.
+	////////////////////////////////////////////////////////////////////////////
+	var clientDbRecord DbRecord
+	err = json.Unmarshal(p, &clientDbRecord)
+	if err != nil {
+		return r, err
+	}
+	r.ID = clientDbRecord.ID
+	r.LoadBalancerIP = clientDbRecord.LoadBalancerIP
+	////////////////////////////////////////////////////////////////////////////
+	if !o.ModifyLb {
+		err = fmt.Errorf("%s does not support dry runs", o.Route)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Unmarshal Data into generic genericData.
+	////////////////////////////////////////////////////////////////////////////
+	var genericData Data
+	err = shared.MarshalInterface(clientDbRecord.Data, &genericData)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Validate Right.
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(genericData.ProductCode))
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Validate payload meets min requirements for submission.
+	////////////////////////////////////////////////////////////////////////////
+	err = o.validate(&clientDbRecord)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Record exists in db.
+	////////////////////////////////////////////////////////////////////////////
+	databaseRecord := clientDbRecord
+	dbRecordExists, err := o.dbRecordExists(&databaseRecord, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if !dbRecordExists {
+		err = fmt.Errorf("%w - no database record found with id %v", ErrNotFound, clientDbRecord.ID)
+		r.LastError = err.Error()
+		return r, err
+	}
+	r.ID = databaseRecord.ID
+	////////////////////////////////////////////////////////////////////////////
+	// Set target.
+	////////////////////////////////////////////////////////////////////////////
+	sdkTarget := &sdkfork.SdkTarget{Address: clientDbRecord.LoadBalancerIP, Mfr: GlobalSources.Clusters[clientDbRecord.LoadBalancerIP].Mfr}
+	sdkConf := &sdkfork.SdkConf{
+		Target: sdkTarget,
+		Log:    log,
+	}
+	sdk := sdkfork.New(sdkConf)
+	////////////////////////////////////////////////////////////////////////////
+	// Compare against the load balancer.
+	////////////////////////////////////////////////////////////////////////////
+	plan, err := sdk.Plan(clientDbRecord.Data, o.Route)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	r.Plan = plan
+	return r, nil
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"strings"
+)
+
+// Projection - returns the paths listed in the fields param, split on ".".
+// Every path must resolve against the table's model, e.g. name or
+// pools.bindings.server.ip.
+func (f Filter) Projection() (r [][]string, err error) {
+	for _, v := range f.URLQueryParams["fields"] {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if _, _, err = f.jsonPath(field); err != nil {
+				return nil, err
+			}
+			r = append(r, strings.Split(field, "."))
+		}
+	}
+	return
+}
+
+// project - returns the parts of data under paths. Arrays are projected
+// element by element.
+func project(data interface{}, paths [][]string) interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for k := range v {
+			r[k] = project(v[k], paths)
+		}
+		return r
+	case map[string]interface{}:
+		whole := make(map[string]bool)
+		nested := make(map[string][][]string)
+		for _, path := range paths {
+			if len(path) == 1 {
+				whole[path[0]] = true
+				continue
+			}
+			nested[path[0]] = append(nested[path[0]], path[1:])
+		}
+		r := make(map[string]interface{})
+		for k, val := range v {
+			switch {
+			case whole[k]:
+				r[k] = val
+			case len(nested[k]) > 0:
+				r[k] = project(val, nested[k])
+			}
+		}
+		return r
+	}
+	return data
+}
@@ -0,0 +1,258 @@
+package common
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// defaultArchiveDir - directory purged records are archived to when
+// Lbm.ArchiveDir is not set.
+const defaultArchiveDir = "archive"
+
+// statusOwners - tables whose records write to the status table through the
+// shared create, modify and delete pipeline. A status record is only purged
+// once its id is in none of them.
+var statusOwners = []string{"virtualservers", "loadbalancers", "migrate"}
+
+// purgeTables - tables covered by the retention policy, in the order they are
+// purged, with the records that may be removed once past the window. Status
+// records of existing records and the staged migrations of records still
+// migrating are always kept.
+var purgeTables = []struct {
+	table string
+	where string
+}{
+	{"recycle", `TRUE`},
+	{"status", orphanedStatus()},
+	{"migrate", `id NOT IN (SELECT id FROM public.status WHERE status_id=3)`},
+}
+
+// orphanedStatus - condition matching status records whose id is in none of
+// statusOwners.
+func orphanedStatus() string {
+	var r []string
+	for _, v := range statusOwners {
+		r = append(r, `NOT EXISTS (SELECT 1 FROM public.`+v+` o WHERE o.id = t.id)`)
+	}
+	return strings.Join(r, ` AND `)
+}
+
+// PurgeRecord - response of the purge route.
+type PurgeRecord struct {
+	// DryRun - true when nothing was removed.
+	DryRun bool `json:"dry_run"`
+	// Tables - outcome for each table with a retention window.
+	Tables []PurgeTable `json:"tables"`
+	// LastError - error returned by the request.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// PurgeTable - outcome of a purge for one table.
+type PurgeTable struct {
+	// Table - recycle, status or migrate.
+	Table string `json:"table"`
+	// RetentionDays - records last modified before this many days ago are
+	// purged.
+	RetentionDays int `json:"retention_days"`
+	// Before - cut off of the retention window.
+	Before string `json:"before"`
+	// Count - number of records purged, or that would be purged.
+	Count int `json:"count"`
+	// Archive - file the purged records were written to.
+	Archive string `json:"archive,omitempty"`
+	// LastError - error that stopped the purge of this table.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Purge - archives and removes the records of every table that are older
+// than its retention window. With dryRun the records are only counted.
+// oUser must be an admin; the background job passes nil.
+func Purge(dryRun bool, oUser *userenv.User) (r PurgeRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r = PurgeRecord{DryRun: dryRun, Tables: []PurgeTable{}}
+	if oUser != nil {
+		err = oUser.IsAdmin()
+		if err != nil {
+			return
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, v := range purgeTables {
+		days := retentionDays(v.table)
+		if days <= 0 {
+			continue
+		}
+		t := PurgeTable{
+			Table:         v.table,
+			RetentionDays: days,
+			Before:        time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339),
+		}
+		var tableErr error
+		if dryRun {
+			tableErr = dao.GlobalDAO.Db.QueryRow(`SELECT count(*) FROM public.`+v.table+` t WHERE last_modified < $1 AND `+v.where, t.Before).Scan(&t.Count)
+		} else {
+			tableErr = purgeTable(v.table, v.where, &t, oUser)
+		}
+		if tableErr != nil {
+			t.LastError = tableErr.Error()
+			err = fmt.Errorf("unable to purge %s - %v", v.table, tableErr)
+		}
+		r.Tables = append(r.Tables, t)
+	}
+	return
+}
+
+// StartPurge - runs Purge every Lbm.PurgeIntervalHours in the background.
+func StartPurge() {
+	hours := config.GlobalConfig.Lbm.PurgeIntervalHours
+	if hours <= 0 {
+		return
+	}
+	log := logrus.New().WithFields(logrus.Fields{"handler": "purge"})
+	go func() {
+		for range time.Tick(time.Duration(hours) * time.Hour) {
+			r, err := Purge(false, nil)
+			if err != nil {
+				log.Warn(err)
+			}
+			for _, v := range r.Tables {
+				log.Infof("purged %d %s records older than %s", v.Count, v.Table, v.Before)
+			}
+		}
+	}()
+}
+
+// purgeTable - writes the records of table matching where and older than
+// t.Before to a gzipped json array in the archive directory, then deletes
+// them. Nothing is deleted unless the archive was written.
+func purgeTable(table string, where string, t *PurgeTable, oUser *userenv.User) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	defer func() {
+		if err == nil && t.Count == 0 {
+			return
+		}
+		dbo := New()
+		dbo.Route = table
+		dbo.Database.Table = table
+		dbo.auditJob("purge", t.Count, err, oUser)
+	}()
+	tx, err := dao.GlobalDAO.Db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+	////////////////////////////////////////////////////////////////////////////
+	rows, err := tx.Query(`SELECT id, row_to_json(t)::text FROM public.`+table+` t WHERE last_modified < $1 AND `+where+` FOR UPDATE`, t.Before)
+	if err != nil {
+		return
+	}
+	var (
+		ids     []string
+		records []string
+	)
+	for rows.Next() {
+		var id, record string
+		err = rows.Scan(&id, &record)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		ids = append(ids, id)
+		records = append(records, record)
+	}
+	rows.Close()
+	err = rows.Err()
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Archive.
+	////////////////////////////////////////////////////////////////////////////
+	t.Archive, err = writeArchive(table, records)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Delete.
+	////////////////////////////////////////////////////////////////////////////
+	result, err := tx.Exec(`DELETE FROM public.`+table+` WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	affected, _ := result.RowsAffected()
+	t.Count = int(affected)
+	return
+}
+
+// writeArchive - writes records, each a json object, to
+// <archive dir>/<table>-<time>.json.gz as a json array and returns the path.
+func writeArchive(table string, records []string) (r string, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	dir := config.GlobalConfig.Lbm.ArchiveDir
+	if dir == "" {
+		dir = defaultArchiveDir
+	}
+	err = os.MkdirAll(dir, 0750)
+	if err != nil {
+		return
+	}
+	r = filepath.Join(dir, fmt.Sprintf("%s-%s.json.gz", table, time.Now().UTC().Format("20060102T150405.000000000Z")))
+	////////////////////////////////////////////////////////////////////////////
+	// Written to a temporary file first so that a partial archive is never
+	// left under the final name.
+	////////////////////////////////////////////////////////////////////////////
+	f, err := os.OpenFile(r+".tmp", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	_, err = io.WriteString(gz, "["+strings.Join(records, ",\n")+"]\n")
+	if err != nil {
+		return
+	}
+	err = gz.Close()
+	if err != nil {
+		return
+	}
+	err = f.Sync()
+	if err != nil {
+		return
+	}
+	err = f.Close()
+	if err != nil {
+		return
+	}
+	err = os.Rename(f.Name(), r)
+	return
+}
+
+// retentionDays - retention window of table.
+func retentionDays(table string) int {
+	lbm := config.GlobalConfig.Lbm
+	switch table {
+	case "recycle":
+		return lbm.RecycleRetentionDays
+	case "status":
+		return lbm.StatusRetentionDays
+	case "migrate":
+		return lbm.MigrateRetentionDays
+	}
+	return 0
+}
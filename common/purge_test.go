@@ -0,0 +1,96 @@
+package common
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestOrphanedStatus(t *testing.T) {
+	r := orphanedStatus()
+	for _, v := range statusOwners {
+		if !strings.Contains(r, `NOT EXISTS (SELECT 1 FROM public.`+v+` o WHERE o.id = t.id)`) {
+			t.Fatalf("expected %s to be checked, got %s", v, r)
+		}
+	}
+	if n := strings.Count(r, " AND "); n != len(statusOwners)-1 {
+		t.Fatalf("expected %d conditions, got %s", len(statusOwners), r)
+	}
+}
+
+func TestRetentionDays(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{Lbm: config.Lbm{RecycleRetentionDays: 30, StatusRetentionDays: 7, MigrateRetentionDays: 90}}
+	tests := []struct {
+		table string
+		want  int
+	}{
+		{"recycle", 30},
+		{"status", 7},
+		{"migrate", 90},
+		{"virtualservers", 0},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if r := retentionDays(tt.table); r != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{Lbm: config.Lbm{ArchiveDir: dir}}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name    string
+		records []string
+	}{
+		{"single record", []string{`{"id":"a"}`}},
+		{"several records", []string{`{"id":"a"}`, `{"id":"b"}`}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := writeArchive("recycle", tt.records)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filepath.Dir(r) != dir || !strings.HasPrefix(filepath.Base(r), "recycle-") || !strings.HasSuffix(r, ".json.gz") {
+				t.Fatalf("expected an archive in %s, got %s", dir, r)
+			}
+			if _, err := os.Stat(r + ".tmp"); !os.IsNotExist(err) {
+				t.Fatalf("expected the temporary file to be removed, got %v", err)
+			}
+			f, err := os.Open(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var records []map[string]string
+			if err := json.NewDecoder(gz).Decode(&records); err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != len(tt.records) {
+				t.Fatalf("expected %d records, got %d", len(tt.records), len(records))
+			}
+		})
+	}
+}
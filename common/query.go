@@ -1,106 +1,281 @@
 package common
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/ticketmaster/lbapi/loadbalancer"
+	"github.com/ticketmaster/lbapi/migrate"
+	"github.com/ticketmaster/lbapi/virtualserver"
 )
 
+// reservedParams - query params used for paging rather than filtering.
+var reservedParams = map[string]bool{
+	"limit":          true,
+	"offset":         true,
+	"orderCol":       true,
+	"orderDirection": true,
+	"cursor":         true,
+	"fields":         true,
+}
+
+// carriedParams - reserved params copied to the next page link.
+var carriedParams = map[string]bool{
+	"orderCol":       true,
+	"orderDirection": true,
+	"fields":         true,
+}
+
+// columns - filterable columns shared by every table.
+var columns = map[string]string{
+	"id":               "id",
+	"source":           "source",
+	"status":           "status",
+	"last_error":       "last_error",
+	"last_modified":    "last_modified",
+	"md5hash":          "md5hash",
+	"load_balancer_ip": "load_balancer_ip",
+	"load_balancer":    "load_balancer::text",
+	"platform":         "load_balancer->>'mfr'",
+}
+
+// dataColumns - data fields filtered on instead of the shared column of the
+// same name, per table. Scheduled changes and change requests carry their own
+// status.
+var dataColumns = map[string]map[string]bool{
+	"schedule":  {"status": true},
+	"approvals": {"status": true},
+}
+
+// containsFields - virtual server arrays matched as a substring of the
+// flattened array.
+var containsFields = map[string]bool{
+	"dns":          true,
+	"certificates": true,
+	"pools":        true,
+	"ports":        true,
+}
+
+// vsFields - data fields of the tables holding virtual server records.
+var vsFields = jsonFields(virtualserver.Data{}, "binding_ip", "networksecuritypolicyname", "networksecuritypolicynameenable", "networksecuritypolicyaction", "networksecuritypolicyuuid")
+
+// tableFields - data fields that may be filtered on, per table. Filters on
+// any other field are rejected.
+var tableFields = map[string]map[string]bool{
+	"virtualservers": vsFields,
+	"recycle":        vsFields,
+	"status":         vsFields,
+	"loadbalancers":  jsonFields(loadbalancer.Data{}),
+	"migrate":        jsonFields(migrate.Response{}),
+	"operations":     jsonFields(Operation{}),
+	"audit":          jsonFields(AuditEntry{}),
+	"schedule":       jsonFields(ScheduledChange{}),
+	"approvals":      jsonFields(ChangeRequest{}),
+}
+
+// productCodeScope - records whose product_code is one of the bound codes.
+const productCodeScope = `(data->>'product_code') = ANY(%s)`
+
+// scopedTables - tables whose records belong to product codes in their data,
+// with the condition matching records of the bound codes. Change requests are
+// shown when any of their product codes is. Load balancers carry a product
+// code but are shared by every VIP.
+var scopedTables = map[string]string{
+	"virtualservers": productCodeScope,
+	"recycle":        productCodeScope,
+	"status":         productCodeScope,
+	"migrate":        productCodeScope,
+	"operations":     productCodeScope,
+	"audit":          productCodeScope,
+	"schedule":       productCodeScope,
+	"approvals":      `EXISTS (SELECT 1 FROM jsonb_array_elements_text(data->'product_codes') c WHERE c = ANY(%s))`,
+}
+
+// comparableFields - fields accepted by the __gt and __lt operators, with
+// the expression they are compared as.
+var comparableFields = map[string]string{
+	"last_modified": "last_modified",
+	"product_code":  "(data->>'product_code')::numeric",
+	"apply_at":      "(data->>'apply_at')::timestamptz",
+}
+
+var fieldRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
 func NewFilter() *Filter {
 	return &Filter{
 		NextQueryParams: new(ParamCollection),
 	}
 }
-func (f Filter) BuildFilter() (r string) {
+
+// BuildFilter - converts the url params to a WHERE clause. Values are never
+// written to the clause; they are returned in args and referenced as $1, $2,
+// etc. Params are ANDed together and repeated params are ORed. Records of
+// product codes outside ProductCodes are left out.
+func (f Filter) BuildFilter() (r string, args []interface{}, err error) {
 	f.NextQueryParams.Params = make(map[string][]string)
-	var filter string
-	var qAnd string
-	var subStr string
-	if len(f.URLQueryParams) > 0 {
-		i := 0
-		for k, v := range f.URLQueryParams {
-			if k == "limit" || k == "offset" || k == "orderCol" || k == "orderDirection" {
-				continue
-			}
+	var keys []string
+	for k, v := range f.URLQueryParams {
+		if carriedParams[k] {
 			f.NextQueryParams.Params[k] = v
-			subStr = ""
-			qOr := ""
-			for ii := 0; ii < len(v); ii++ {
-				if ii != 0 {
-					qOr = " OR "
-				}
-				searchTerm := f.FormatURLQry(k, v[ii])
-				if searchTerm != "" {
-					subStr = subStr + qOr + searchTerm
-				}
-			}
-			subStr = "(" + subStr + ")"
-			if i != 0 {
-				qAnd = " AND "
+		}
+		if !reservedParams[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	////////////////////////////////////////////////////////////////////////////
+	var clauses []string
+	for _, k := range keys {
+		v := f.URLQueryParams[k]
+		f.NextQueryParams.Params[k] = v
+		var terms []string
+		for ii := 0; ii < len(v); ii++ {
+			searchTerm, err := f.FormatURLQry(k, v[ii], &args)
+			if err != nil {
+				return "", nil, err
 			}
-			if len(subStr) > 2 {
-				filter = filter + qAnd + subStr
+			if searchTerm != "" {
+				terms = append(terms, searchTerm)
 			}
-			i++
 		}
-		if filter != "" {
-			r = " WHERE " + filter
+		if len(terms) > 0 {
+			clauses = append(clauses, "("+strings.Join(terms, " OR ")+")")
 		}
 	}
+	if scope, ok := scopedTables[f.Table]; ok && f.ProductCodes != nil {
+		clauses = append(clauses, fmt.Sprintf(scope, bind(&args, pq.Array(f.ProductCodes))))
+	}
+	if len(clauses) > 0 {
+		r = " WHERE " + strings.Join(clauses, " AND ")
+	}
 	return
 }
 
-// FormatURLQry converts a field+value pair to a SQL WHERE clause.
-func (f Filter) FormatURLQry(field string, val string) string {
-	var resp string
-	var eval string
+// FormatURLQry converts a field+value pair to a SQL WHERE clause. The field
+// may carry an operator suffix: __ne, __in (comma separated), __gt and __lt
+// (last_modified, apply_at and product_code only), __regex and __exists (true or
+// false). Without a suffix the value is matched exactly, or with like when
+// it contains *. Dotted fields such as pools.bindings.server.ip are matched
+// with formatPathQry. The value is appended to args.
+func (f Filter) FormatURLQry(key string, val string, args *[]interface{}) (r string, err error) {
+	field, op := key, ""
+	if i := strings.Index(key, "__"); i > 0 {
+		field, op = key[:i], key[i+2:]
+	}
 	field = strings.ToLower(field)
 	val = strings.TrimSpace(val)
-	if strings.Contains(val, "*") {
-		val = strings.Replace(val, "*", "%", -1)
-		eval = " like '" + val + "'"
-	} else {
-		eval = " = '" + val + "'"
+	if strings.Contains(field, ".") {
+		return f.formatPathQry(field, op, val, args)
+	}
+	expr, err := f.fieldExpr(field)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if containsFields[field] || strings.HasPrefix(field, "networksecuritypolicy") || field == "binding_ip" {
+		if op != "" {
+			return "", fmt.Errorf("operator %q is not supported for %s", op, field)
+		}
+		return f.formatDocumentQry(field, val, args)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	switch op {
+	case "":
+		if strings.Contains(val, "*") {
+			return expr + " like " + bind(args, strings.Replace(val, "*", "%", -1)), nil
+		}
+		return expr + " = " + bind(args, val), nil
+	case "ne":
+		if strings.Contains(val, "*") {
+			return "(" + expr + " IS NULL OR " + expr + " not like " + bind(args, strings.Replace(val, "*", "%", -1)) + ")", nil
+		}
+		return expr + " IS DISTINCT FROM " + bind(args, val), nil
+	case "in":
+		var values []string
+		for _, v := range strings.Split(val, ",") {
+			values = append(values, strings.TrimSpace(v))
+		}
+		return expr + " = ANY(" + bind(args, pq.Array(values)) + ")", nil
+	case "gt", "lt":
+		comparable, ok := comparableFields[field]
+		if !ok {
+			return "", fmt.Errorf("operator %q is only supported for last_modified, apply_at and product_code", op)
+		}
+		value, err := comparableValue(field, val)
+		if err != nil {
+			return "", err
+		}
+		cmp := " > "
+		if op == "lt" {
+			cmp = " < "
+		}
+		return comparable + cmp + bind(args, value), nil
+	case "regex":
+		if _, err = regexp.Compile(val); err != nil {
+			return "", fmt.Errorf("invalid regex for %s - %v", field, err)
+		}
+		return expr + " ~ " + bind(args, val), nil
+	case "exists":
+		exists, err := strconv.ParseBool(val)
+		if err != nil {
+			return "", fmt.Errorf("%s__exists must be true or false", field)
+		}
+		if exists {
+			return expr + " IS NOT NULL", nil
+		}
+		return expr + " IS NULL", nil
 	}
+	return "", fmt.Errorf("unknown operator %q - use ne, in, gt, lt, regex or exists", op)
+}
+
+// fieldExpr - returns the sql expression of field, or an error when the
+// field is not filterable on the table.
+func (f Filter) fieldExpr(field string) (r string, err error) {
+	if column, ok := columns[field]; ok && !dataColumns[f.Table][field] {
+		return column, nil
+	}
+	if !fieldRegex.MatchString(field) || !tableFields[f.Table][field] {
+		return "", fmt.Errorf("%q is not a filterable field of %s", field, f.Table)
+	}
+	return "data->>'" + field + "'", nil
+}
+
+// formatDocumentQry - filters on virtual server arrays and nested documents.
+func (f Filter) formatDocumentQry(field string, val string, args *[]interface{}) (r string, err error) {
+	var doc interface{}
 	switch field {
-	case "source":
-		resp = field + eval
-	case "status":
-		resp = `status` + eval
-	case "enabled":
-		resp = `data->>'enabled'` + eval
-	case "id":
-		resp = field + eval
-	case "last_modified":
-		resp = field + eval
-	case "md5hash":
-		resp = field + eval
-	case "platform":
-		resp = `load_balancer->>'mfr'` + eval
-	case "load_balancer_ip":
-		resp = field + eval
-	case "load_balancer":
-		resp = field + eval
-	case "dns":
-		resp = `regexp_replace(regexp_replace(regexp_replace(data->>'dns','\[','{'),'\]','}'),'("|\s)','','g') like '%` + val + `%'`
-	case "certificates":
-		resp = `regexp_replace(regexp_replace(regexp_replace(data->>'certificates','\[','{'),'\]','}'),'("|\s)','','g') like '%` + val + `%'`
-	case "pools":
-		resp = `regexp_replace(regexp_replace(regexp_replace(data->>'pools','\[','{'),'\]','}'),'("|\s)','','g')  like '%` + val + `%'`
-	case "ports":
-		resp = `regexp_replace(regexp_replace(regexp_replace(data->>'ports','\[','{'),'\]','}'),'("|\s)','','g') like '%` + val + `%'`
+	case "binding_ip":
+		doc = map[string]interface{}{"pools": []interface{}{map[string]interface{}{"bindings": []interface{}{map[string]interface{}{"server": map[string]interface{}{"ip": val}}}}}}
 	case "networksecuritypolicyname":
-		resp = `data->'networksecuritypolicy'->>'name'` + eval
+		return `data->'networksecuritypolicy'->>'name' = ` + bind(args, val), nil
+	case "networksecuritypolicyuuid":
+		return `data->'networksecuritypolicy'->>'uuid' = ` + bind(args, val), nil
 	case "networksecuritypolicynameenable":
-		resp = `data @> '{"networksecuritypolicy":{"rules":[{"enable":` + val + `}]}}'`
+		enable, err := strconv.ParseBool(val)
+		if err != nil {
+			return "", fmt.Errorf("%s must be true or false", field)
+		}
+		doc = map[string]interface{}{"networksecuritypolicy": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"enable": enable}}}}
 	case "networksecuritypolicyaction":
-		resp = `data @> '{"networksecuritypolicy":{"rules":[{"action":"` + val + `"}]}}'`
-	case "networksecuritypolicyuuid":
-		resp = `data->'networksecuritypolicy'->>'uuid'` + eval
+		doc = map[string]interface{}{"networksecuritypolicy": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"action": val}}}}
 	default:
-		resp = `data->>'` + field + `'` + eval
+		return `regexp_replace(regexp_replace(regexp_replace(data->>'` + field + `','\[','{'),'\]','}'),'("|\s)','','g') like ` + bind(args, "%"+val+"%"), nil
 	}
-	return resp
+	p, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	return "data @> " + bind(args, string(p)) + "::jsonb", nil
 }
+
 func (f Filter) SetOrderBy(in string) (r string) {
 	switch in {
 	case "load_balancer_ip":
@@ -129,25 +304,34 @@ func (f Filter) SetOrderBy(in string) (r string) {
 		r = "data->>'enabled'"
 	case "platform":
 		r = "load_balancer->>'mfr'"
+	case "last_modified":
+		r = "last_modified"
 	default:
 		r = "data->>'load_balancer_ip'"
 	}
 	return
 }
 
-// BuildSQLStmt generates a SQL statment using URL Params provided by the.
-// Filter and DbTable objects.
-func (f Filter) BuildSQLStmt() (r string, err error) {
-	var limit string
-	var offset string
+// paging - returns the sort key, ORDER BY, LIMIT and OFFSET clauses, and the
+// seek clause of the cursor param. Records are ordered on orderCol and then
+// id so every page is stable. limit and offset must be integers and are bound
+// to args.
+func (f Filter) paging(args *[]interface{}) (r page, err error) {
 	orderDirection := "asc"
-	orderBy := "order by data->>'product_code' asc"
-	whereClause := f.BuildFilter()
+	sortExpr := "data->>'product_code'"
 	if len(f.URLQueryParams["limit"]) == 1 {
-		limit = fmt.Sprintf(" LIMIT %s ", f.URLQueryParams["limit"][0])
+		n, err := strconv.Atoi(f.URLQueryParams["limit"][0])
+		if err != nil || n < 0 {
+			return r, fmt.Errorf("limit must be a positive integer")
+		}
+		r.limit = " LIMIT " + bind(args, n) + " "
 	}
 	if len(f.URLQueryParams["offset"]) == 1 {
-		offset = fmt.Sprintf(" OFFSET %s ", f.URLQueryParams["offset"][0])
+		n, err := strconv.Atoi(f.URLQueryParams["offset"][0])
+		if err != nil || n < 0 {
+			return r, fmt.Errorf("offset must be a positive integer")
+		}
+		r.offset = " OFFSET " + bind(args, n) + " "
 	}
 	if len(f.URLQueryParams["orderCol"]) == 1 {
 		if len(f.URLQueryParams["orderDirection"]) == 1 {
@@ -161,9 +345,51 @@ func (f Filter) BuildSQLStmt() (r string, err error) {
 				orderDirection = "asc"
 			}
 		}
-		orderBy = fmt.Sprintf(" ORDER BY %s %s", f.SetOrderBy(f.URLQueryParams["orderCol"][0]), orderDirection)
+		sortExpr = f.SetOrderBy(f.URLQueryParams["orderCol"][0])
+	}
+	r.sortKey = "coalesce((" + sortExpr + ")::text, '')"
+	r.orderBy = fmt.Sprintf(" ORDER BY %s %s, id %s", r.sortKey, orderDirection, orderDirection)
+	////////////////////////////////////////////////////////////////////////////
+	// Seek past the last record of the previous page.
+	////////////////////////////////////////////////////////////////////////////
+	if token := f.URLQueryParams["cursor"]; len(token) == 1 && token[0] != "" {
+		c, err := decodeCursor(token[0], f.order())
+		if err != nil {
+			return r, err
+		}
+		cmp := ">"
+		if orderDirection == "desc" {
+			cmp = "<"
+		}
+		r.seek = fmt.Sprintf("(%s, id) %s (%s, %s)", r.sortKey, cmp, bind(args, c.Value), bind(args, c.ID))
+	}
+	return
+}
+
+// where - joins the seek clause of p to whereClause.
+func (p page) where(whereClause string) string {
+	switch {
+	case p.seek == "":
+		return whereClause
+	case whereClause == "":
+		return " WHERE " + p.seek
+	}
+	return whereClause + " AND " + p.seek
+}
+
+// BuildSQLStmt generates a SQL statment using URL Params provided by the.
+// Filter and DbTable objects. args must be passed to Query with the
+// statement.
+func (f Filter) BuildSQLStmt() (r string, args []interface{}, err error) {
+	whereClause, args, err := f.BuildFilter()
+	if err != nil {
+		return
+	}
+	page, err := f.paging(&args)
+	if err != nil {
+		return
 	}
-	r = fmt.Sprintf(`SELECT * FROM
+	r = fmt.Sprintf(`SELECT *, %s as sort_key FROM
 	(
 		SELECT 
 			a.id, 
@@ -184,46 +410,32 @@ func (f Filter) BuildSQLStmt() (r string, err error) {
 		ON a.id=s.id
 		LEFT JOIN public.statusdescription as d  
 		ON s.status_id=d.id
-		) as d %s %s %s %s`, f.Table, whereClause, orderBy, limit, offset)
+		) as d %s %s %s %s`, page.sortKey, f.Table, page.where(whereClause), page.orderBy, page.limit, page.offset)
 	// Convert sql to all lowercase.
 	//r = strings.ToLower(r)
 	return
 }
 
 // BuildVsSQLStmt generates a SQL statment using URL Params provided by the.
-// Filter and DbTable objects.
-func (f Filter) BuildVsSQLStmt() (r string, err error) {
-	var limit string
-	var offset string
-	orderDirection := "asc"
-	orderBy := "order by data->>'product_code' asc"
-	whereClause := f.BuildFilter()
-	if len(f.URLQueryParams["limit"]) == 1 {
-		limit = fmt.Sprintf(" LIMIT %s ", f.URLQueryParams["limit"][0])
+// Filter and DbTable objects. args must be passed to Query with the
+// statement.
+func (f Filter) BuildVsSQLStmt() (r string, args []interface{}, err error) {
+	whereClause, args, err := f.BuildFilter()
+	if err != nil {
+		return
 	}
-	if len(f.URLQueryParams["offset"]) == 1 {
-		offset = fmt.Sprintf(" OFFSET %s ", f.URLQueryParams["offset"][0])
-	}
-	if len(f.URLQueryParams["orderCol"]) == 1 {
-		if len(f.URLQueryParams["orderDirection"]) == 1 {
-			direction := strings.ToLower(f.URLQueryParams["orderDirection"][0])
-			switch direction {
-			case "asc":
-				orderDirection = "asc"
-			case "desc":
-				orderDirection = "desc"
-			default:
-				orderDirection = "asc"
-			}
-		}
-		orderBy = fmt.Sprintf(" ORDER BY %s %s", f.SetOrderBy(f.URLQueryParams["orderCol"][0]), orderDirection)
+	page, err := f.paging(&args)
+	if err != nil {
+		return
 	}
 	r = fmt.Sprintf(`SELECT 
 	data->>'name' as name,
 	data->>'ip' as ip,
 	load_balancer_ip,
 	data->>'platform' as platform,
-	data->>'service_type' as service_type
+	data->>'service_type' as service_type,
+	id,
+	%s as sort_key
 	FROM
 	(
 		SELECT 
@@ -246,16 +458,20 @@ func (f Filter) BuildVsSQLStmt() (r string, err error) {
 		LEFT JOIN public.statusdescription as d  
 		ON s.status_id=d.id
 		WHERE a.data->>'ip' != '0.0.0.0'
-		) as d %s %s %s %s`, f.Table, whereClause, orderBy, limit, offset)
+		) as d %s %s %s %s`, page.sortKey, f.Table, page.where(whereClause), page.orderBy, page.limit, page.offset)
 	// Convert sql to all lowercase.
 	//r = strings.ToLower(r)
 	return
 }
 
 // BuildCountStmt generates a SQL statment using URL Params provided by the.
-// Filter and DbTable objects.
-func (f Filter) BuildCountStmt() (r string, err error) {
-	whereClause := f.BuildFilter()
+// Filter and DbTable objects. args must be passed to Query with the
+// statement.
+func (f Filter) BuildCountStmt() (r string, args []interface{}, err error) {
+	whereClause, args, err := f.BuildFilter()
+	if err != nil {
+		return
+	}
 	r = fmt.Sprintf(`SELECT count(*) as total FROM
 	(
 		SELECT 
@@ -282,16 +498,52 @@ func (f Filter) BuildCountStmt() (r string, err error) {
 	//r = strings.ToLower(r)
 	return
 }
+
 func (f Filter) SetQueryParams() (r string) {
-	m := []string{}
-	for k, v := range f.NextQueryParams.Params {
-		for _, vv := range v {
-			m = append(m, fmt.Sprintf("%s=%s", k, vv))
-		}
-	}
-	r = strings.Join(m, "&")
+	r = url.Values(f.NextQueryParams.Params).Encode()
 	if r != "" {
 		r = fmt.Sprintf("%s&", r)
 	}
 	return
 }
+
+// bind - appends v to args and returns its placeholder.
+func bind(args *[]interface{}, v interface{}) string {
+	*args = append(*args, v)
+	return "$" + strconv.Itoa(len(*args))
+}
+
+// comparableValue - parses the value of a __gt or __lt filter.
+func comparableValue(field string, val string) (r interface{}, err error) {
+	switch field {
+	case "product_code":
+		r, err = strconv.Atoi(val)
+		if err != nil {
+			err = fmt.Errorf("product_code must be an integer")
+		}
+	case "last_modified", "apply_at":
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, parseErr := time.Parse(layout, val); parseErr == nil {
+				return t, nil
+			}
+		}
+		err = fmt.Errorf("%s must be an RFC 3339 time or a date (yyyy-mm-dd)", field)
+	}
+	return
+}
+
+// jsonFields - returns the json names of the fields of v plus extra.
+func jsonFields(v interface{}, extra ...string) (r map[string]bool) {
+	r = make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			r[name] = true
+		}
+	}
+	for _, v := range extra {
+		r[v] = true
+	}
+	return
+}
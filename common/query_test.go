@@ -0,0 +1,101 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string][]string
+		clause string
+		args   []interface{}
+		ok     bool
+	}{
+		{"no params", nil, ``, nil, true},
+		{"paging params ignored", map[string][]string{"limit": {"10"}, "orderCol": {"name"}}, ``, nil, true},
+		{"exact", map[string][]string{"name": {"prd1-vip"}}, `(data->>'name' = $1)`, []interface{}{"prd1-vip"}, true},
+		{"wildcard", map[string][]string{"name": {"prd1-*"}}, `(data->>'name' like $1)`, []interface{}{"prd1-%"}, true},
+		{"column", map[string][]string{"load_balancer_ip": {"10.1.1.1"}}, `(load_balancer_ip = $1)`, []interface{}{"10.1.1.1"}, true},
+		{"repeated params are ored", map[string][]string{"ip": {"10.0.0.1", "10.0.0.2"}}, `(data->>'ip' = $1 OR data->>'ip' = $2)`, []interface{}{"10.0.0.1", "10.0.0.2"}, true},
+		{"params are anded in order", map[string][]string{"name": {"a"}, "ip": {"b"}}, `(data->>'ip' = $1) AND (data->>'name' = $2)`, []interface{}{"b", "a"}, true},
+		{"ne", map[string][]string{"name__ne": {"a"}}, `(data->>'name' IS DISTINCT FROM $1)`, []interface{}{"a"}, true},
+		{"ne wildcard", map[string][]string{"name__ne": {"a*"}}, `((data->>'name' IS NULL OR data->>'name' not like $1))`, []interface{}{"a%"}, true},
+		{"gt product code", map[string][]string{"product_code__gt": {"100"}}, `((data->>'product_code')::numeric > $1)`, []interface{}{100}, true},
+		{"lt not comparable", map[string][]string{"name__lt": {"a"}}, ``, nil, false},
+		{"regex", map[string][]string{"name__regex": {"^prd1-"}}, `(data->>'name' ~ $1)`, []interface{}{"^prd1-"}, true},
+		{"bad regex", map[string][]string{"name__regex": {"("}}, ``, nil, false},
+		{"exists on arrays", map[string][]string{"dns__exists": {"true"}}, ``, nil, false},
+		{"not exists", map[string][]string{"name__exists": {"false"}}, `(data->>'name' IS NULL)`, nil, true},
+		{"unknown operator", map[string][]string{"name__like": {"a"}}, ``, nil, false},
+		{"unknown field", map[string][]string{"secret": {"a"}}, ``, nil, false},
+		{"injected field", map[string][]string{"name' OR '1'='1": {"a"}}, ``, nil, false},
+		{"injected value", map[string][]string{"name": {"a' OR '1'='1"}}, `(data->>'name' = $1)`, []interface{}{"a' OR '1'='1"}, true},
+		{"contains", map[string][]string{"dns": {"www"}}, `(regexp_replace(regexp_replace(regexp_replace(data->>'dns','\[','{'),'\]','}'),'("|\s)','','g') like $1)`, []interface{}{"%www%"}, true},
+		{"binding ip", map[string][]string{"binding_ip": {"10.0.0.1"}}, `(data @> $1::jsonb)`, []interface{}{`{"pools":[{"bindings":[{"server":{"ip":"10.0.0.1"}}]}]}`}, true},
+		{"binding ip quotes stay in the parameter", map[string][]string{"binding_ip": {`10.0.0.1"}]}]}' OR '1'='1`}}, `(data @> $1::jsonb)`, []interface{}{`{"pools":[{"bindings":[{"server":{"ip":"10.0.0.1\"}]}]}' OR '1'='1"}}]}]}`}, true},
+		{"binding ip operator", map[string][]string{"binding_ip__ne": {"10.0.0.1"}}, ``, nil, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFilter()
+			f.Table = "virtualservers"
+			f.URLQueryParams = tt.params
+			r, args, err := f.BuildFilter()
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected filter to build, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %s", r)
+			case !tt.ok:
+				return
+			}
+			want := ""
+			if tt.clause != "" {
+				want = " WHERE " + tt.clause
+			}
+			if r != want {
+				t.Fatalf("expected %q, got %q", want, r)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(tt.args) {
+				t.Fatalf("expected args %v, got %v", tt.args, args)
+			}
+			if strings.Contains(r, "'1'='1") {
+				t.Fatalf("expected value to be bound, got %s", r)
+			}
+		})
+	}
+}
+
+func TestBuildFilterScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		codes  []string
+		clause string
+	}{
+		{"unscoped user", "virtualservers", nil, ``},
+		{"virtual servers", "virtualservers", []string{"1"}, ` WHERE (data->>'product_code') = ANY($1)`},
+		{"no codes matches nothing", "virtualservers", []string{}, ` WHERE (data->>'product_code') = ANY($1)`},
+		{"change requests", "approvals", []string{"1"}, ` WHERE EXISTS (SELECT 1 FROM jsonb_array_elements_text(data->'product_codes') c WHERE c = ANY($1))`},
+		{"load balancers are shared", "loadbalancers", []string{"1"}, ``},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFilter()
+			f.Table = tt.table
+			f.ProductCodes = tt.codes
+			r, _, err := f.BuildFilter()
+			if err != nil {
+				t.Fatalf("expected filter to build, got %v", err)
+			}
+			if r != tt.clause {
+				t.Fatalf("expected %q, got %q", tt.clause, r)
+			}
+		})
+	}
+}
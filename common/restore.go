@@ -0,0 +1,128 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/infoblox"
+	"github.com/ticketmaster/lbapi/secret"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+// Restore - recreates the virtual server held by the recycle record with id
+// through the Create pipeline of target. The vip is placed on loadBalancerIP,
+// or on the load balancer it was deleted from when empty, and keeps its ip if
+// infoblox shows it is still free. The recycle record is removed once the
+// load balancer change succeeds.
+func (o *Common) Restore(id string, loadBalancerIP string, target *Common, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	log := logrus.NewEntry(logrus.New())
+	*log = *o.Log
+	log = log.WithFields(logrus.Fields{"user": oUser.Username, "handler": "restore", "route": o.Route})
+	////////////////////////////////////////////////////////////////////////////
+	// Get Record By Id. This operation queries the system db.
+	////////////////////////////////////////////////////////////////////////////
+	recycled, err := o.fetchRecord(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if recycled.Source != target.Route {
+		err = fmt.Errorf("%s was deleted from %s and cannot be restored as a %s", id, recycled.Source, target.Route)
+		r.LastError = err.Error()
+		return r, err
+	}
+	var data virtualserver.Data
+	err = shared.MarshalInterface(recycled.Data, &data)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	clearSourceIDs(&data)
+	////////////////////////////////////////////////////////////////////////////
+	// Private keys are never read back from the load balancer.
+	////////////////////////////////////////////////////////////////////////////
+	for k, v := range data.Certificates {
+		if v.Key.PrivateKey != "" {
+			continue
+		}
+		key, lookupErr := secret.Lookup(v.Certificate)
+		if lookupErr != nil {
+			err = fmt.Errorf("%w - %s - %v", ErrKeyNotStored, v.Name, lookupErr)
+			r.LastError = err.Error()
+			return r, err
+		}
+		data.Certificates[k].Key = key
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Keep the ip if nothing else has claimed it, otherwise let Create assign
+	// a new one. Aliases are recreated either way.
+	////////////////////////////////////////////////////////////////////////////
+	if data.IP != "" && target.Setting != nil && target.Setting.Infoblox.Enable {
+		ibo := infoblox.NewInfoblox()
+		available, ibErr := ibo.Available(data.IP, data.ProductCode, data.DNS)
+		ibo.Client.Unset()
+		if ibErr != nil || !available {
+			log.Warnf("ip %s is no longer free - assigning a new ip - %v", data.IP, ibErr)
+			primary := ibo.PrimaryName(data.IP, data.ProductCode)
+			var aliases []string
+			for _, v := range data.DNS {
+				if v != primary {
+					aliases = append(aliases, v)
+				}
+			}
+			data.DNS = aliases
+			data.IP = ""
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Submit as a create request.
+	////////////////////////////////////////////////////////////////////////////
+	if loadBalancerIP == "" {
+		loadBalancerIP = recycled.LoadBalancerIP
+	}
+	clientDbRecord := DbRecord{
+		LoadBalancerIP: loadBalancerIP,
+		Platform:       recycled.Platform,
+		Data:           data,
+	}
+	payload, err := json.Marshal(clientDbRecord)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	target.Created = func(d *DbRecord, oUser *userenv.User) {
+		err := o.deleteDbRecord(NewDeleteConf(&recycled, oUser, log))
+		if err != nil {
+			log.Warn(err)
+			return
+		}
+		o.publishRecord("restored", d, oUser)
+	}
+	return target.Create(payload, oUser)
+}
+
+// clearSourceIDs - removes the load balancer ids of data so that every
+// object is created again.
+func clearSourceIDs(data *virtualserver.Data) {
+	data.SourceUUID = ""
+	data.SourcePoolGroupUUID = ""
+	data.SourceStatus = ""
+	for k := range data.Pools {
+		data.Pools[k].SourceUUID = ""
+		data.Pools[k].SourceStatus = ""
+		data.Pools[k].Persistence.SourceUUID = ""
+		for kk := range data.Pools[k].Bindings {
+			data.Pools[k].Bindings[kk].Server.SourceUUID = ""
+		}
+		for kk := range data.Pools[k].HealthMonitors {
+			data.Pools[k].HealthMonitors[kk].SourceUUID = ""
+		}
+	}
+	for k := range data.Certificates {
+		data.Certificates[k].SourceUUID = ""
+	}
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/certificate"
+	"github.com/ticketmaster/lbapi/monitor"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+func TestClearSourceIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		data virtualserver.Data
+		want virtualserver.Data
+	}{
+		{
+			"virtual server",
+			virtualserver.Data{Name: "vip", SourceUUID: "vs-1", SourcePoolGroupUUID: "pg-1", SourceStatus: "up"},
+			virtualserver.Data{Name: "vip"},
+		},
+		{
+			"pools",
+			virtualserver.Data{Pools: []pool.Data{{
+				Name:           "pool",
+				SourceUUID:     "pool-1",
+				SourceStatus:   "up",
+				Bindings:       []pool.MemberBinding{{Port: 80, Server: pool.Server{IP: "10.0.0.1", SourceUUID: "server-1"}}},
+				HealthMonitors: []monitor.Data{{Type: "http", SourceUUID: "monitor-1"}},
+			}}},
+			virtualserver.Data{Pools: []pool.Data{{
+				Name:           "pool",
+				Bindings:       []pool.MemberBinding{{Port: 80, Server: pool.Server{IP: "10.0.0.1"}}},
+				HealthMonitors: []monitor.Data{{Type: "http"}},
+			}}},
+		},
+		{
+			"certificates keep their keys",
+			virtualserver.Data{Certificates: []certificate.Data{{Name: "cert", SourceUUID: "cert-1", Key: certificate.Key{PrivateKey: "key"}}}},
+			virtualserver.Data{Certificates: []certificate.Data{{Name: "cert", Key: certificate.Key{PrivateKey: "key"}}}},
+		},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearSourceIDs(&tt.data)
+			if !reflect.DeepEqual(tt.data, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, tt.data)
+			}
+		})
+	}
+}
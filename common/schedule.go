@@ -0,0 +1,521 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/secret"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+const (
+	// SchedulePending - change is waiting for its time.
+	SchedulePending = "pending"
+	// ScheduleCancelled - change was cancelled before it started.
+	ScheduleCancelled = "cancelled"
+	// scheduleTimeFormat - format of apply_at and deadline. Fixed width so
+	// that the stored strings sort in time order.
+	scheduleTimeFormat = "2006-01-02T15:04:05Z"
+	// operationTimeout - how long the scheduler waits for the load balancer
+	// work of a change to finish.
+	operationTimeout = time.Hour
+)
+
+// ScheduledChange - modify or delete persisted to be applied later. Scheduled
+// changes are stored in the schedule table.
+type ScheduledChange struct {
+	// Action - modify or delete.
+	Action string `json:"action"`
+	// Route - route the change is applied through.
+	Route string `json:"route"`
+	// RecordID - id of the record being changed.
+	RecordID string `json:"record_id"`
+	// ProductCode - product code of the record.
+	ProductCode int `json:"product_code,omitempty"`
+	// Window - maintenance window the change was scheduled into.
+	Window string `json:"window,omitempty"`
+	// ApplyAt - time the change is applied.
+	ApplyAt string `json:"apply_at"`
+	// Deadline - end of the window. A change not started by then fails.
+	Deadline string `json:"deadline,omitempty"`
+	// ETag - entity tag of the record when the change was scheduled. The
+	// change fails if the record has been modified since.
+	ETag string `json:"etag"`
+	// Payload - modify request. Private keys are redacted and restored from
+	// secure storage when the change is applied.
+	Payload interface{} `json:"payload,omitempty"`
+	// Status - pending, running, complete, fail or cancelled.
+	Status string `json:"status"`
+	// User - user that scheduled the change. The change is applied with the
+	// user's rights.
+	User string `json:"user"`
+	// Groups - groups of the user when the change was scheduled.
+	Groups []string `json:"groups,omitempty"`
+	// Token - scope of the API token the change was scheduled with.
+	Token *userenv.TokenScope `json:"token,omitempty"`
+	// ApprovedBy - user that approved the change when the record's product
+	// code is protected.
+	ApprovedBy string `json:"approved_by,omitempty"`
+	// Submitted - time the change was scheduled.
+	Submitted string `json:"submitted"`
+	// CancelledBy - user that cancelled the change.
+	CancelledBy string `json:"cancelled_by,omitempty"`
+	// OperationID - operation tracking the load balancer work.
+	OperationID string `json:"operation_id,omitempty"`
+	// Result - record returned once the change was applied. Private keys are
+	// redacted.
+	Result interface{} `json:"result,omitempty"`
+	// LastError - error that failed the change.
+	LastError string `json:"last_error,omitempty"`
+	// Finished - time the change finished.
+	Finished string `json:"finished,omitempty"`
+}
+
+// ScheduleChange - persists a modify (p is the modify payload) or delete of
+// the record with id to be applied at applyAt, an RFC 3339 time, or at the
+// next opening of the maintenance window named window. Rights, payload and
+// If-Match are checked now and again when the change is applied.
+func (o *Common) ScheduleChange(action string, id string, p []byte, applyAt string, window string, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r.Source = "schedule"
+	change := ScheduledChange{
+		Action:     action,
+		Route:      o.Route,
+		Window:     window,
+		Status:     SchedulePending,
+		User:       oUser.Username,
+		Groups:     oUser.Group,
+		Token:      oUser.Token,
+		ApprovedBy: oUser.ApprovedBy,
+		Submitted:  time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	start, deadline, err := scheduleTime(applyAt, window, time.Now())
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Protected product codes are scheduled once a second user approves.
+	////////////////////////////////////////////////////////////////////////////
+	if d, pending, err := o.requestApproval(ChangeRequest{Action: action, RecordID: id, ApplyAt: applyAt, Window: window}, p, oUser); pending || err != nil {
+		return d, err
+	}
+	change.ApplyAt = start.UTC().Format(scheduleTimeFormat)
+	if !deadline.IsZero() {
+		change.Deadline = deadline.UTC().Format(scheduleTimeFormat)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Check the request as modify or delete would.
+	////////////////////////////////////////////////////////////////////////////
+	var current DbRecord
+	switch action {
+	case "modify":
+		current, change.Payload, err = o.schedulableModify(p, oUser)
+	case "delete":
+		current, err = o.deletable(id, oUser)
+	default:
+		err = fmt.Errorf("%s cannot be scheduled", action)
+	}
+	if err == nil {
+		_, err = o.checkIfMatch(current.ID, oUser)
+	}
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	change.RecordID = current.ID
+	change.ETag = ETag(current)
+	change.ProductCode = o.formatData(current.Data).ProductCode
+	r.LoadBalancerIP = current.LoadBalancerIP
+	////////////////////////////////////////////////////////////////////////////
+	// Save.
+	////////////////////////////////////////////////////////////////////////////
+	r.ID, err = shared.NewUUID()
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`INSERT INTO public.schedule (id, data, source, load_balancer_ip, last_modified, last_modified_by) VALUES ($1, $2, $3, $4, current_timestamp, $5)`, r.ID, string(data), o.Route, r.LoadBalancerIP, change.User)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	r.Data = change
+	return r, nil
+}
+
+// schedulableModify - checks the modify payload p and returns the record it
+// changes along with the payload to store. Private keys are moved to secure
+// storage.
+func (o *Common) schedulableModify(p []byte, oUser *userenv.User) (current DbRecord, r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	err = json.Unmarshal(p, &r)
+	if err != nil {
+		return
+	}
+	err = oUser.HasRight(userenv.ActionModify, strconv.Itoa(o.formatData(r.Data).ProductCode))
+	if err != nil {
+		return
+	}
+	validated := r
+	err = o.validate(&validated)
+	if err != nil {
+		return
+	}
+	exists, err := o.dbRecordExists(&r, oUser)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = fmt.Errorf("%w - no database record found with id %v", ErrNotFound, r.ID)
+		return
+	}
+	current, err = o.fetchRecord(r.ID, oUser)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Private keys are never stored in the schedule table.
+	////////////////////////////////////////////////////////////////////////////
+	err = stashKeys(&r, oUser)
+	return
+}
+
+// stashKeys - moves the certificate private keys of d to secure storage,
+// leaving them redacted in d.
+func stashKeys(d *DbRecord, oUser *userenv.User) (err error) {
+	var data virtualserver.Data
+	err = shared.MarshalInterface(d.Data, &data)
+	if err != nil {
+		return
+	}
+	for k, v := range data.Certificates {
+		if v.Key.PrivateKey == "" || v.Key.PrivateKey == redacted {
+			continue
+		}
+		if !secret.Enabled() {
+			return fmt.Errorf("%w - %s - %v", ErrKeyNotStored, v.Name, secret.ErrDisabled)
+		}
+		err = secret.Store(v.Certificate, v.Key, oUser.Username)
+		if err != nil {
+			return
+		}
+		data.Certificates[k].Key.PrivateKey = redacted
+		if v.Key.PassPhrase != "" {
+			data.Certificates[k].Key.PassPhrase = redacted
+		}
+	}
+	d.Data = data
+	return
+}
+
+// unstashKeys - returns payload, a stored request, as a request for the
+// record with id with the private keys restored from secure storage. Keys
+// that are not stored are left out when the certificate is unchanged on
+// current.
+func unstashKeys(payload interface{}, id string, current DbRecord) (r []byte, err error) {
+	var clientDbRecord DbRecord
+	err = shared.MarshalInterface(payload, &clientDbRecord)
+	if err != nil {
+		return
+	}
+	var data, currentData virtualserver.Data
+	err = shared.MarshalInterface(clientDbRecord.Data, &data)
+	if err != nil {
+		return
+	}
+	shared.MarshalInterface(current.Data, &currentData)
+	err = rehydrate(&data, &currentData)
+	if err != nil {
+		return
+	}
+	clientDbRecord.ID = id
+	clientDbRecord.Data = data
+	return json.Marshal(clientDbRecord)
+}
+
+// CancelChange - cancels the pending scheduled change with id.
+func (o *Common) CancelChange(id string, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r, err = o.fetchRecord(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	var change ScheduledChange
+	err = shared.MarshalInterface(r.Data, &change)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	err = oUser.HasRight(change.Action, strconv.Itoa(change.ProductCode))
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if change.Status != SchedulePending {
+		err = fmt.Errorf("%w - %s is %s", ErrNotPending, id, change.Status)
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Only pending changes are updated, so a change the scheduler has just
+	// claimed is never cancelled.
+	////////////////////////////////////////////////////////////////////////////
+	change.Status = ScheduleCancelled
+	change.CancelledBy = oUser.Username
+	change.Finished = time.Now().UTC().Format(time.RFC3339Nano)
+	data, err := json.Marshal(change)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	result, err := dao.GlobalDAO.Db.Exec(`UPDATE public.schedule SET data=$2, last_modified=current_timestamp, last_modified_by=$3 WHERE id=$1 AND data->>'status'=$4`, r.ID, string(data), oUser.Username, SchedulePending)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		err = fmt.Errorf("%w - %s has started", ErrNotPending, id)
+		r.LastError = err.Error()
+		return r, err
+	}
+	r.Data = change
+	return r, nil
+}
+
+// StartScheduler - applies due changes through targets, keyed by route,
+// every Schedule.IntervalSeconds in the background.
+func (o *Common) StartScheduler(targets map[string]*Common) {
+	interval := time.Duration(config.GlobalConfig.Schedule.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go func() {
+		for range time.Tick(interval) {
+			o.applyDue(targets)
+		}
+	}()
+}
+
+// applyDue - claims every pending change whose time has come and applies
+// each in its own goroutine. Claimed changes are marked running in the same
+// statement, so each change is applied by one instance only.
+func (o *Common) applyDue(targets map[string]*Common) {
+	////////////////////////////////////////////////////////////////////////////
+	log := o.Log.WithFields(logrus.Fields{"handler": "schedule"})
+	rows, err := dao.GlobalDAO.Db.Query(`UPDATE public.schedule SET data=jsonb_set(data, '{status}', to_jsonb($1::text)), last_modified=current_timestamp
+		WHERE id IN (SELECT id FROM public.schedule WHERE data->>'status'=$2 AND data->>'apply_at'<=$3 FOR UPDATE SKIP LOCKED)
+		RETURNING id, data`, OperationRunning, SchedulePending, time.Now().UTC().Format(scheduleTimeFormat))
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	defer rows.Close()
+	////////////////////////////////////////////////////////////////////////////
+	for rows.Next() {
+		var (
+			id     string
+			p      []byte
+			change ScheduledChange
+		)
+		err = rows.Scan(&id, &p)
+		if err == nil {
+			err = json.Unmarshal(p, &change)
+		}
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		go o.applyChange(id, change, targets[change.Route])
+	}
+	if err := rows.Err(); err != nil {
+		log.Warn(err)
+	}
+}
+
+// applyChange - submits change through target as the user that scheduled it,
+// waits for the load balancer work to finish and records the outcome.
+func (o *Common) applyChange(id string, change ScheduledChange, target *Common) {
+	////////////////////////////////////////////////////////////////////////////
+	log := o.Log.WithFields(logrus.Fields{"user": change.User, "handler": "schedule", "schedule": id})
+	oUser := &userenv.User{Username: change.User, Group: change.Groups, Token: change.Token, ApprovedBy: change.ApprovedBy}
+	r, err := func() (r DbRecord, err error) {
+		if change.Deadline != "" && time.Now().UTC().Format(scheduleTimeFormat) > change.Deadline {
+			return r, fmt.Errorf("maintenance window %s closed at %s before the change started", change.Window, change.Deadline)
+		}
+		if target == nil {
+			return r, fmt.Errorf("changes to %s cannot be scheduled", change.Route)
+		}
+		err = activeToken(change.Token)
+		if err != nil {
+			return r, err
+		}
+		////////////////////////////////////////////////////////////////////////
+		// Test - Record has not changed since the change was scheduled.
+		////////////////////////////////////////////////////////////////////////
+		current, err := target.fetchRecord(change.RecordID, oUser)
+		if err != nil {
+			return r, err
+		}
+		if ETag(current) != change.ETag {
+			return r, ErrPreconditionFailed
+		}
+		////////////////////////////////////////////////////////////////////////
+		if change.Action == "delete" {
+			return target.Delete(change.RecordID, oUser)
+		}
+		p, err := unstashKeys(change.Payload, change.RecordID, current)
+		if err != nil {
+			return r, err
+		}
+		return target.Modify(p, oUser)
+	}()
+	////////////////////////////////////////////////////////////////////////////
+	// The load balancer work runs in the background of modify and delete.
+	////////////////////////////////////////////////////////////////////////////
+	change.OperationID = r.OperationID
+	if err == nil && r.OperationID != "" {
+		var result interface{}
+		result, err = waitOperation(r.OperationID)
+		if result != nil {
+			r.Data = result
+		}
+	}
+	change.Status = OperationComplete
+	if err != nil {
+		change.Status = OperationFailed
+		change.LastError = err.Error()
+		log.Warn(err)
+	}
+	change.Result = redact(r)
+	change.Finished = time.Now().UTC().Format(time.RFC3339Nano)
+	////////////////////////////////////////////////////////////////////////////
+	data, err := json.Marshal(change)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`UPDATE public.schedule SET data=$2, last_modified=current_timestamp, last_error=$3 WHERE id=$1`, id, string(data), change.LastError)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+// waitOperation - polls the operation with id until it has finished and
+// returns its result.
+func waitOperation(id string) (r interface{}, err error) {
+	var op struct {
+		Status    string      `json:"status"`
+		Result    interface{} `json:"result"`
+		LastError string      `json:"last_error"`
+	}
+	for timeout := time.Now().Add(operationTimeout); time.Now().Before(timeout); time.Sleep(5 * time.Second) {
+		var p []byte
+		err = dao.GlobalDAO.Db.QueryRow(`SELECT data FROM public.operations WHERE id=$1`, id).Scan(&p)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(p, &op)
+		if err != nil {
+			return
+		}
+		switch op.Status {
+		case OperationComplete:
+			return op.Result, nil
+		case OperationFailed:
+			return op.Result, errors.New(op.LastError)
+		}
+	}
+	return nil, fmt.Errorf("operation %s did not finish within %v", id, operationTimeout)
+}
+
+// scheduleTime - returns the time a change requested for applyAt or window
+// is applied and, for windows that close, the time the window closes.
+func scheduleTime(applyAt string, window string, now time.Time) (start time.Time, deadline time.Time, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	switch {
+	case applyAt != "" && window != "":
+		err = errors.New("apply_at and window cannot be combined")
+		return
+	case applyAt != "":
+		start, err = time.Parse(time.RFC3339, applyAt)
+		if err != nil {
+			err = fmt.Errorf("apply_at must be an RFC 3339 time, e.g. 2020-06-01T02:00:00-07:00 - %v", err)
+			return
+		}
+		if !start.After(now) {
+			err = fmt.Errorf("apply_at %s is in the past", applyAt)
+		}
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, v := range config.GlobalConfig.Schedule.Windows {
+		if v.Name == window {
+			return nextWindow(v, now)
+		}
+	}
+	err = fmt.Errorf("no maintenance window named %s", window)
+	return
+}
+
+// nextWindow - returns the next opening of w that has not closed by now. A
+// window that is already open starts now.
+func nextWindow(w config.Window, now time.Time) (start time.Time, end time.Time, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	loc := time.UTC
+	if w.TimeZone != "" {
+		loc, err = time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return
+		}
+	}
+	opens, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		err = fmt.Errorf("maintenance window %s has an invalid start %q - %v", w.Name, w.Start, err)
+		return
+	}
+	days := make(map[string]bool)
+	for _, v := range w.Days {
+		days[strings.ToLower(v)] = true
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	////////////////////////////////////////////////////////////////////////////
+	local := now.In(loc)
+	for i := -1; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		start = time.Date(day.Year(), day.Month(), day.Day(), opens.Hour(), opens.Minute(), 0, 0, loc)
+		if len(days) > 0 && !days[strings.ToLower(start.Weekday().String()[:3])] {
+			continue
+		}
+		if duration == 0 {
+			if start.After(now) {
+				return start, time.Time{}, nil
+			}
+			continue
+		}
+		end = start.Add(duration)
+		if end.After(now) {
+			if start.Before(now) {
+				start = now
+			}
+			return
+		}
+	}
+	err = fmt.Errorf("maintenance window %s never opens", w.Name)
+	return start, time.Time{}, err
+}
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestNextWindow(t *testing.T) {
+	// Tuesday.
+	now := time.Date(2020, 6, 2, 10, 0, 0, 0, time.UTC)
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2020, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		window config.Window
+		start  time.Time
+		end    time.Time
+		ok     bool
+	}{
+		{"later today", config.Window{Start: "12:00", DurationMinutes: 60}, at(2, 12, 0), at(2, 13, 0), true},
+		{"open now starts now", config.Window{Start: "09:30", DurationMinutes: 60}, now, at(2, 10, 30), true},
+		{"closed today opens tomorrow", config.Window{Start: "02:00", DurationMinutes: 60}, at(3, 2, 0), at(3, 3, 0), true},
+		{"open from yesterday", config.Window{Start: "23:00", DurationMinutes: 720}, now, at(2, 11, 0), true},
+		{"open ended", config.Window{Start: "02:00"}, at(3, 2, 0), time.Time{}, true},
+		{"days", config.Window{Start: "02:00", DurationMinutes: 60, Days: []string{"Fri"}}, at(5, 2, 0), at(5, 3, 0), true},
+		{"time zone", config.Window{Start: "02:00", DurationMinutes: 60, TimeZone: "America/Los_Angeles"}, at(3, 9, 0), at(3, 10, 0), true},
+		{"bad start", config.Window{Start: "2am"}, time.Time{}, time.Time{}, false},
+		{"bad time zone", config.Window{Start: "02:00", TimeZone: "Mars/Olympus"}, time.Time{}, time.Time{}, false},
+		{"bad day", config.Window{Start: "02:00", Days: []string{"someday"}}, time.Time{}, time.Time{}, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := nextWindow(tt.window, now)
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected a window, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %v", start)
+			case !tt.ok:
+				return
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Fatalf("expected %v - %v, got %v - %v", tt.start, tt.end, start, end)
+			}
+		})
+	}
+}
+
+func TestScheduleTime(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{Schedule: config.Schedule{Windows: []config.Window{{Name: "nightly", Start: "02:00", DurationMinutes: 60}}}}
+	now := time.Date(2020, 6, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		applyAt string
+		window  string
+		start   time.Time
+		ok      bool
+	}{
+		{"apply at", "2020-06-02T12:00:00Z", "", time.Date(2020, 6, 2, 12, 0, 0, 0, time.UTC), true},
+		{"apply at with offset", "2020-06-02T05:00:00-07:00", "", time.Date(2020, 6, 2, 12, 0, 0, 0, time.UTC), true},
+		{"apply at in the past", "2020-06-01T12:00:00Z", "", time.Time{}, false},
+		{"apply at not rfc 3339", "2020-06-02 12:00", "", time.Time{}, false},
+		{"window", "", "nightly", time.Date(2020, 6, 3, 2, 0, 0, 0, time.UTC), true},
+		{"unknown window", "", "weekly", time.Time{}, false},
+		{"both", "2020-06-02T12:00:00Z", "nightly", time.Time{}, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, _, err := scheduleTime(tt.applyAt, tt.window, now)
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected a time, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %v", start)
+			case !tt.ok:
+				return
+			}
+			if !start.Equal(tt.start) {
+				t.Fatalf("expected %v, got %v", tt.start, start)
+			}
+		})
+	}
+}
+
+func TestScheduleFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string][]string
+		clause string
+		ok     bool
+	}{
+		{"status is the change's own", map[string][]string{"status": {"pending"}}, ` WHERE (data->>'status' = $1)`, true},
+		{"apply at date", map[string][]string{"apply_at__gt": {"2020-06-01"}}, ` WHERE ((data->>'apply_at')::timestamptz > $1)`, true},
+		{"apply at time", map[string][]string{"apply_at__lt": {"2020-06-01T02:00:00Z"}}, ` WHERE ((data->>'apply_at')::timestamptz < $1)`, true},
+		{"apply at not a time", map[string][]string{"apply_at__gt": {"tomorrow"}}, ``, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFilter()
+			f.Table = "schedule"
+			f.URLQueryParams = tt.params
+			r, _, err := f.BuildFilter()
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected filter to build, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %s", r)
+			case !tt.ok:
+				return
+			}
+			if r != tt.clause {
+				t.Fatalf("expected %s, got %s", tt.clause, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// ErrExists - returned when a record with the same name already exists.
+var ErrExists = errors.New("record already exists")
+
+// serviceAccountName - names service accounts may have.
+var serviceAccountName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,62}$`)
+
+// tokenActions - actions API tokens may be granted. Backup needs every
+// product code, which a token never holds.
+var tokenActions = []string{
+	userenv.ActionFetch,
+	userenv.ActionCreate,
+	userenv.ActionModify,
+	userenv.ActionDelete,
+	userenv.ActionMigrate,
+	userenv.ActionImport,
+}
+
+// ServiceAccount - non-human user, e.g. a CI pipeline, that API tokens are
+// issued to. Stored in the serviceaccounts table.
+type ServiceAccount struct {
+	// Name - unique name of the account.
+	Name string `json:"name"`
+	// Description - what the account is used for.
+	Description string `json:"description,omitempty"`
+	// Owners - users that may issue, list and revoke the account's tokens.
+	// The user creating the account is always an owner.
+	Owners []string `json:"owners"`
+	// Created - time the account was created.
+	Created string `json:"created,omitempty"`
+	// CreatedBy - user that created the account.
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// APIToken - long-lived token of a service account, scoped to product codes
+// and permissions. Stored in the apitokens table; only a hash of the secret is
+// kept.
+type APIToken struct {
+	// ID - id of the token. It is part of the token and is safe to log.
+	ID string `json:"id"`
+	// ServiceAccount - name of the account the token was issued to.
+	ServiceAccount string `json:"service_account"`
+	// Description - what the token is used for.
+	Description string `json:"description,omitempty"`
+	// ProductCodes - product codes the token may act on.
+	ProductCodes []int `json:"product_codes"`
+	// Permissions - actions the token may take on its product codes: fetch,
+	// create, modify, delete, migrate or import.
+	Permissions []string `json:"permissions"`
+	// ExpiresAt - RFC 3339 time the token stops working. Tokens without one
+	// work until revoked.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// Created - time the token was issued.
+	Created string `json:"created,omitempty"`
+	// CreatedBy - user that issued the token.
+	CreatedBy string `json:"created_by,omitempty"`
+	// Revoked - time the token was revoked.
+	Revoked string `json:"revoked,omitempty"`
+	// RevokedBy - user that revoked the token.
+	RevokedBy string `json:"revoked_by,omitempty"`
+	// LastUsed - time the token was last used.
+	LastUsed string `json:"last_used,omitempty"`
+}
+
+// IssuedToken - response of IssueToken. Token is only ever returned here.
+type IssuedToken struct {
+	APIToken
+	// Token - bearer token to authenticate with.
+	Token string `json:"token"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Service accounts.
+////////////////////////////////////////////////////////////////////////////////
+
+// CreateServiceAccount - creates the service account p.
+func CreateServiceAccount(p []byte, oUser *userenv.User) (r ServiceAccount, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.IsHuman()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(p, &r)
+	if err != nil {
+		return
+	}
+	if !serviceAccountName.MatchString(r.Name) {
+		return r, errors.New("name must be lowercase letters, digits, '.', '_' or '-' and at most 63 characters")
+	}
+	r.Owners = appendOwner(r.Owners, oUser.Username)
+	////////////////////////////////////////////////////////////////////////////
+	var created time.Time
+	err = dao.GlobalDAO.Db.QueryRow(`
+	INSERT INTO public.serviceaccounts (name, description, owners, created, created_by)
+	VALUES ($1, $2, $3, current_timestamp, $4)
+	ON CONFLICT (name) DO NOTHING
+	RETURNING created`, r.Name, r.Description, pq.Array(r.Owners), oUser.Username).Scan(&created)
+	if err == sql.ErrNoRows {
+		return r, fmt.Errorf("%w - service account %s", ErrExists, r.Name)
+	}
+	if err != nil {
+		return
+	}
+	r.Created = created.UTC().Format(time.RFC3339)
+	r.CreatedBy = oUser.Username
+	logrus.WithFields(logrus.Fields{"service_account": r.Name, "user": oUser.Username}).Info("service account created")
+	return
+}
+
+// FetchServiceAccounts - returns the service accounts oUser owns, or every
+// account for admins.
+func FetchServiceAccounts(oUser *userenv.User) (r []ServiceAccount, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r = []ServiceAccount{}
+	err = oUser.IsHuman()
+	if err != nil {
+		return
+	}
+	where, args := `WHERE $1 = ANY(owners)`, []interface{}{oUser.Username}
+	if oUser.IsAdmin() == nil {
+		where, args = ``, nil
+	}
+	rows, err := dao.GlobalDAO.Db.Query(`SELECT `+accountColumns+` FROM public.serviceaccounts `+where+` ORDER BY name`, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a, scanErr := scanServiceAccount(rows)
+		if scanErr != nil {
+			return r, scanErr
+		}
+		r = append(r, a)
+	}
+	err = rows.Err()
+	return
+}
+
+// DeleteServiceAccount - revokes every token of the service account name and
+// removes it. Revoked tokens are kept.
+func DeleteServiceAccount(name string, oUser *userenv.User) (r ServiceAccount, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r, err = ownedServiceAccount(name, oUser)
+	if err != nil {
+		return
+	}
+	tx, err := dao.GlobalDAO.Db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`UPDATE public.apitokens SET revoked=current_timestamp, revoked_by=$2 WHERE service_account=$1 AND revoked IS NULL`, name, oUser.Username)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(`DELETE FROM public.serviceaccounts WHERE name=$1`, name)
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	logrus.WithFields(logrus.Fields{"service_account": name, "user": oUser.Username}).Info("service account deleted")
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Tokens.
+////////////////////////////////////////////////////////////////////////////////
+
+// IssueToken - issues a token p to the service account name. The issuer must
+// hold each permission of the token for each of its product codes.
+func IssueToken(name string, p []byte, oUser *userenv.User) (r IssuedToken, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	_, err = ownedServiceAccount(name, oUser)
+	if err != nil {
+		return
+	}
+	var request APIToken
+	err = json.Unmarshal(p, &request)
+	if err != nil {
+		return
+	}
+	r.APIToken = APIToken{
+		ServiceAccount: name,
+		Description:    request.Description,
+		ProductCodes:   request.ProductCodes,
+		Permissions:    request.Permissions,
+		ExpiresAt:      request.ExpiresAt,
+	}
+	err = validateToken(&r.APIToken, oUser)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Generate - lbapi_<id>_<secret>.
+	////////////////////////////////////////////////////////////////////////////
+	r.ID, err = shared.NewUUID()
+	if err != nil {
+		return
+	}
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return
+	}
+	secret := hex.EncodeToString(b)
+	r.Token = userenv.TokenPrefix + r.ID + "_" + secret
+	////////////////////////////////////////////////////////////////////////////
+	var expires interface{}
+	if r.ExpiresAt != "" {
+		expires = r.ExpiresAt
+	}
+	var created time.Time
+	err = dao.GlobalDAO.Db.QueryRow(`
+	INSERT INTO public.apitokens (id, service_account, description, secret_hash, product_codes, permissions, expires, created, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, current_timestamp, $8)
+	RETURNING created`, r.ID, name, r.Description, hashSecret(secret), pq.Array(r.ProductCodes), pq.Array(r.Permissions), expires, oUser.Username).Scan(&created)
+	if err != nil {
+		return IssuedToken{}, err
+	}
+	r.Created = created.UTC().Format(time.RFC3339)
+	r.CreatedBy = oUser.Username
+	logrus.WithFields(logrus.Fields{"service_account": name, "token_id": r.ID, "user": oUser.Username}).Info("api token issued")
+	return
+}
+
+// FetchTokens - returns the tokens of the service account name, newest first.
+// Secrets are never returned.
+func FetchTokens(name string, oUser *userenv.User) (r []APIToken, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	r = []APIToken{}
+	_, err = ownedServiceAccount(name, oUser)
+	if err != nil {
+		return
+	}
+	rows, err := dao.GlobalDAO.Db.Query(`SELECT `+tokenColumns+` FROM public.apitokens WHERE service_account=$1 ORDER BY created DESC`, name)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t, scanErr := scanToken(rows)
+		if scanErr != nil {
+			return r, scanErr
+		}
+		r = append(r, t)
+	}
+	err = rows.Err()
+	return
+}
+
+// RevokeToken - revokes the token id of the service account name.
+func RevokeToken(name string, id string, oUser *userenv.User) (r APIToken, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	_, err = ownedServiceAccount(name, oUser)
+	if err != nil {
+		return
+	}
+	row := dao.GlobalDAO.Db.QueryRow(`
+	UPDATE public.apitokens SET revoked=current_timestamp, revoked_by=$3
+	WHERE id=$1 AND service_account=$2 AND revoked IS NULL
+	RETURNING `+tokenColumns, id, name, oUser.Username)
+	r, err = scanToken(row)
+	if err == sql.ErrNoRows {
+		return r, fmt.Errorf("%w - no active token %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return
+	}
+	logrus.WithFields(logrus.Fields{"service_account": name, "token_id": id, "user": oUser.Username}).Info("api token revoked")
+	return
+}
+
+// LookupToken - verifies token against its stored hash and returns its scope.
+// Revoked and expired tokens are rejected. Set as userenv.LookupToken.
+func LookupToken(token string) (r userenv.TokenScope, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	parts := strings.SplitN(strings.TrimPrefix(token, userenv.TokenPrefix), "_", 2)
+	if len(parts) != 2 {
+		return r, errors.New("malformed api token")
+	}
+	id, secret := parts[0], parts[1]
+	row := dao.GlobalDAO.Db.QueryRow(`
+	SELECT `+tokenColumns+`, secret_hash, COALESCE(expires <= current_timestamp, FALSE)
+	FROM public.apitokens WHERE id=$1`, id)
+	var (
+		t       APIToken
+		hash    string
+		expired bool
+	)
+	t, err = scanToken(row, &hash, &expired)
+	if err == sql.ErrNoRows {
+		return r, errors.New("unknown api token")
+	}
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Secret, revocation and expiry.
+	////////////////////////////////////////////////////////////////////////////
+	switch {
+	case subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(hash)) != 1:
+		return r, errors.New("unknown api token")
+	case t.Revoked != "":
+		return r, errors.New("api token was revoked")
+	case expired:
+		return r, errors.New("api token expired")
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`UPDATE public.apitokens SET last_used=current_timestamp WHERE id=$1`, id)
+	if err != nil {
+		return
+	}
+	r = userenv.TokenScope{TokenID: t.ID, ServiceAccount: t.ServiceAccount, Permissions: t.Permissions}
+	for _, v := range t.ProductCodes {
+		r.ProductCodes = append(r.ProductCodes, strconv.Itoa(v))
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers.
+////////////////////////////////////////////////////////////////////////////////
+
+// accountColumns - columns read by scanServiceAccount.
+const accountColumns = `name, description, owners, created, created_by`
+
+// tokenColumns - columns read by scanToken.
+const tokenColumns = `id, service_account, description, product_codes, permissions, expires, created, created_by, revoked, COALESCE(revoked_by, ''), last_used`
+
+// rowScanner - *sql.Row or *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanToken - reads the tokenColumns of row, followed by extra.
+func scanToken(row rowScanner, extra ...interface{}) (r APIToken, err error) {
+	var (
+		codes                          pq.Int64Array
+		expires, created, revoked, use sql.NullTime
+	)
+	dest := []interface{}{&r.ID, &r.ServiceAccount, &r.Description, &codes, pq.Array(&r.Permissions), &expires, &created, &r.CreatedBy, &revoked, &r.RevokedBy, &use}
+	err = row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return
+	}
+	for _, v := range codes {
+		r.ProductCodes = append(r.ProductCodes, int(v))
+	}
+	r.ExpiresAt = formatNullTime(expires)
+	r.Created = formatNullTime(created)
+	r.Revoked = formatNullTime(revoked)
+	r.LastUsed = formatNullTime(use)
+	return r, nil
+}
+
+// scanServiceAccount - reads a service account from row.
+func scanServiceAccount(row rowScanner) (r ServiceAccount, err error) {
+	var created sql.NullTime
+	err = row.Scan(&r.Name, &r.Description, pq.Array(&r.Owners), &created, &r.CreatedBy)
+	r.Created = formatNullTime(created)
+	return
+}
+
+// ownedServiceAccount - returns the service account name, unless oUser is
+// neither one of its owners nor an admin.
+func ownedServiceAccount(name string, oUser *userenv.User) (r ServiceAccount, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	err = oUser.IsHuman()
+	if err != nil {
+		return
+	}
+	row := dao.GlobalDAO.Db.QueryRow(`SELECT `+accountColumns+` FROM public.serviceaccounts WHERE name=$1`, name)
+	r, err = scanServiceAccount(row)
+	if err == sql.ErrNoRows {
+		return r, fmt.Errorf("%w - service account %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return
+	}
+	for _, v := range r.Owners {
+		if v == oUser.Username {
+			return r, nil
+		}
+	}
+	if oUser.IsAdmin() == nil {
+		return r, nil
+	}
+	return r, fmt.Errorf("%w. you do not own service account %s", userenv.ErrNotAuthorized, name)
+}
+
+// activeToken - returns an error when the API token of scope has been revoked,
+// has expired or no longer exists. Changes that replay a token later, once
+// approved or at their scheduled time, are checked with it first.
+func activeToken(scope *userenv.TokenScope) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if scope == nil {
+		return nil
+	}
+	var revoked, expired bool
+	row := dao.GlobalDAO.Db.QueryRow(`SELECT revoked IS NOT NULL, COALESCE(expires <= current_timestamp, FALSE) FROM public.apitokens WHERE id=$1`, scope.TokenID)
+	err = row.Scan(&revoked, &expired)
+	switch {
+	case err == sql.ErrNoRows:
+		return fmt.Errorf("%w. api token %s no longer exists", userenv.ErrNotAuthorized, scope.TokenID)
+	case err != nil:
+		return err
+	case revoked:
+		return fmt.Errorf("%w. api token %s was revoked", userenv.ErrNotAuthorized, scope.TokenID)
+	case expired:
+		return fmt.Errorf("%w. api token %s expired", userenv.ErrNotAuthorized, scope.TokenID)
+	}
+	return nil
+}
+
+// ownsServiceAccount - returns true when user is one of the owners of the
+// service account name.
+func ownsServiceAccount(name string, user string) (r bool, err error) {
+	err = dao.GlobalDAO.Db.QueryRow(`SELECT EXISTS (SELECT 1 FROM public.serviceaccounts WHERE name=$1 AND $2 = ANY(owners))`, name, user).Scan(&r)
+	return
+}
+
+// validateToken - checks the scope and expiry of t before it is issued.
+func validateToken(t *APIToken, oUser *userenv.User) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if len(t.ProductCodes) == 0 {
+		return errors.New("token needs at least one product code")
+	}
+	if len(t.Permissions) == 0 {
+		return errors.New("token needs at least one permission")
+	}
+	if t.ExpiresAt != "" {
+		expires, err := time.Parse(time.RFC3339, t.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("expires_at must be an RFC 3339 time - %v", err)
+		}
+		if !expires.After(time.Now()) {
+			return errors.New("expires_at must be in the future")
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Issuer holds the scope it grants.
+	////////////////////////////////////////////////////////////////////////////
+	for k, v := range t.Permissions {
+		t.Permissions[k] = strings.ToLower(v)
+		known := false
+		for _, action := range tokenActions {
+			known = known || t.Permissions[k] == action
+		}
+		if !known {
+			return fmt.Errorf("unknown permission %s - use one of %s", v, strings.Join(tokenActions, ", "))
+		}
+		for _, code := range t.ProductCodes {
+			err = oUser.HasRight(t.Permissions[k], strconv.Itoa(code))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// appendOwner - returns owners with user, unless it is already one.
+func appendOwner(owners []string, user string) []string {
+	for _, v := range owners {
+		if v == user {
+			return owners
+		}
+	}
+	return append(owners, user)
+}
+
+// hashSecret - hash of the secret of a token, as stored.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatNullTime - t as an RFC 3339 time, or empty when null.
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+func TestValidateToken(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{}
+	operator := &userenv.User{Group: []string{"prd1-operator"}}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name  string
+		token APIToken
+		ok    bool
+	}{
+		{"valid", APIToken{ProductCodes: []int{1}, Permissions: []string{"Modify"}, ExpiresAt: future}, true},
+		{"no expiry", APIToken{ProductCodes: []int{1}, Permissions: []string{"fetch"}}, true},
+		{"no product codes", APIToken{Permissions: []string{"fetch"}}, false},
+		{"no permissions", APIToken{ProductCodes: []int{1}}, false},
+		{"expired", APIToken{ProductCodes: []int{1}, Permissions: []string{"fetch"}, ExpiresAt: past}, false},
+		{"bad expiry", APIToken{ProductCodes: []int{1}, Permissions: []string{"fetch"}, ExpiresAt: "tomorrow"}, false},
+		{"unknown permission", APIToken{ProductCodes: []int{1}, Permissions: []string{"approve"}}, false},
+		{"backup is not grantable", APIToken{ProductCodes: []int{1}, Permissions: []string{"backup"}}, false},
+		{"code the issuer cannot modify", APIToken{ProductCodes: []int{1, 2}, Permissions: []string{"modify"}}, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateToken(&tt.token, operator)
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected ok %v, got %v", tt.ok, err)
+			}
+			for _, v := range tt.token.Permissions {
+				if tt.ok && v != userenv.ActionFetch && v != userenv.ActionModify {
+					t.Fatalf("expected permissions to be lower case, got %s", v)
+				}
+			}
+		})
+	}
+}
+
+func TestHashSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"secret", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := hashSecret(tt.secret); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestAppendOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		owners []string
+		user   string
+		want   []string
+	}{
+		{"first owner", nil, "jdoe", []string{"jdoe"}},
+		{"new owner", []string{"asmith"}, "jdoe", []string{"asmith", "jdoe"}},
+		{"existing owner", []string{"jdoe", "asmith"}, "jdoe", []string{"jdoe", "asmith"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := appendOwner(tt.owners, tt.user); !reflect.DeepEqual(r, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestFormatNullTime(t *testing.T) {
+	tests := []struct {
+		name string
+		time sql.NullTime
+		want string
+	}{
+		{"null", sql.NullTime{}, ""},
+		{"utc", sql.NullTime{Time: time.Date(2020, 6, 1, 2, 0, 0, 0, time.FixedZone("PDT", -7*3600)), Valid: true}, "2020-06-01T09:00:00Z"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := formatNullTime(tt.time); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+// eventChannel - NOTIFY channel events are shared on, so that every instance
+// using the database streams the changes made by the others.
+const eventChannel = "lbapi_events"
+
+// streamBuffer - events buffered per subscriber. Events are dropped for a
+// subscriber that falls this far behind.
+const streamBuffer = 64
+
+var stream = struct {
+	sync.Mutex
+	listening   bool
+	subscribers map[chan webhook.Event]bool
+}{subscribers: make(map[chan webhook.Event]bool)}
+
+// Subscribe - returns a channel receiving every event raised by any instance
+// sharing the database. Call cancel once the client has gone away.
+func Subscribe() (r <-chan webhook.Event, cancel func(), err error) {
+	////////////////////////////////////////////////////////////////////////////
+	stream.Lock()
+	defer stream.Unlock()
+	if !stream.listening {
+		err = listen()
+		if err != nil {
+			return
+		}
+		stream.listening = true
+	}
+	////////////////////////////////////////////////////////////////////////////
+	ch := make(chan webhook.Event, streamBuffer)
+	stream.subscribers[ch] = true
+	cancel = func() {
+		stream.Lock()
+		delete(stream.subscribers, ch)
+		stream.Unlock()
+	}
+	return ch, cancel, nil
+}
+
+// listen - starts the goroutine fanning out the events received on
+// eventChannel.
+func listen() (err error) {
+	l, err := dao.GlobalDAO.Listen(eventChannel)
+	if err != nil {
+		return
+	}
+	log := logrus.New().WithFields(logrus.Fields{"handler": "events"})
+	go func() {
+		for n := range l.Notify {
+			////////////////////////////////////////////////////////////////////
+			// nil is sent after a reconnect. Events raised while the
+			// connection was down are lost.
+			////////////////////////////////////////////////////////////////////
+			if n == nil {
+				continue
+			}
+			var e webhook.Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				log.Warn(err)
+				continue
+			}
+			broadcast(e)
+		}
+	}()
+	return
+}
+
+// broadcast - sends e to the subscribers of this instance without blocking.
+func broadcast(e webhook.Event) {
+	stream.Lock()
+	defer stream.Unlock()
+	for ch := range stream.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// notify - shares e with the subscribers of every instance.
+func notify(e webhook.Event) {
+	p, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, err = dao.GlobalDAO.Db.Exec(`SELECT pg_notify($1, $2)`, eventChannel, string(p))
+	if err != nil {
+		logrus.New().WithFields(logrus.Fields{"handler": "events"}).Warn(err)
+	}
+}
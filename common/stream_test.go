@@ -0,0 +1,43 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+func TestBroadcast(t *testing.T) {
+	ready := make(chan webhook.Event, streamBuffer)
+	full := make(chan webhook.Event)
+	stream.Lock()
+	stream.subscribers[ready] = true
+	stream.subscribers[full] = true
+	stream.Unlock()
+	defer func() {
+		stream.Lock()
+		delete(stream.subscribers, ready)
+		delete(stream.subscribers, full)
+		stream.Unlock()
+	}()
+	////////////////////////////////////////////////////////////////////////////
+	// A subscriber that is not reading must not hold up the others.
+	////////////////////////////////////////////////////////////////////////////
+	broadcast(webhook.Event{ID: "event-1"})
+	select {
+	case e := <-ready:
+		if e.ID != "event-1" {
+			t.Fatalf("expected event-1, got %s", e.ID)
+		}
+	default:
+		t.Fatal("expected the event to be delivered")
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Events are dropped once a subscriber falls streamBuffer behind.
+	////////////////////////////////////////////////////////////////////////////
+	for i := 0; i < streamBuffer+10; i++ {
+		broadcast(webhook.Event{ID: "event"})
+	}
+	if len(ready) != streamBuffer {
+		t.Fatalf("expected %d buffered events, got %d", streamBuffer, len(ready))
+	}
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+// validate - checks the required fields with the route validator and the
+// value of every field with validateFields. Every invalid field is returned
+// together as a *shared.ValidationError.
+func (o *Common) validate(d *DbRecord) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	fields, err := o.fieldErrors(d, "data")
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	validated, err := o.Database.Validate(d)
+	var validationErr *shared.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		fields = mergeFieldErrors(validationErr.Fields, fields)
+	case err != nil:
+		return err
+	case !validated && len(fields) == 0:
+		return fmt.Errorf("payload not validated - %+v", d.Data)
+	}
+	return shared.NewValidationError(fields)
+}
+
+// validateFields - checks the value of every field without enforcing the
+// fields that are assigned during create, such as ip and load_balancer_ip.
+// Runs before any change is made.
+func (o *Common) validateFields(d *DbRecord) (err error) {
+	fields, err := o.fieldErrors(d, "data")
+	if err != nil {
+		return
+	}
+	return shared.NewValidationError(fields)
+}
+
+// validateBulkFields - validateFields for every record of a bulk payload so
+// that one bad record rejects the request before any record is processed.
+// Paths are prefixed with the index of the record, e.g. [2].data.name.
+func (o *Common) validateBulkFields(dbRecords []DbRecord) (err error) {
+	var fields []shared.FieldError
+	for k := range dbRecords {
+		r, err := o.fieldErrors(&dbRecords[k], shared.JSONPath(shared.JSONPath("", k), "data"))
+		var validationErr *shared.ValidationError
+		if errors.As(err, &validationErr) {
+			r = validationErr.Fields
+		} else if err != nil {
+			return err
+		}
+		fields = append(fields, r...)
+	}
+	return shared.NewValidationError(fields)
+}
+
+// fieldErrors - returns the invalid fields of virtual server records, with
+// paths under path. Values are checked against the platform of the assigned
+// load balancer, or against every platform when none is assigned yet. Imports
+// skip this check since the load balancer is the source of truth.
+func (o *Common) fieldErrors(d *DbRecord, path string) (r []shared.FieldError, err error) {
+	if o.Database.Table != "virtualservers" {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var data virtualserver.Data
+	err = shared.MarshalValidate(path, d.Data, &data)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	mfr := GlobalSources.Clusters[d.LoadBalancerIP].Mfr
+	if mfr == "" {
+		mfr = d.Platform
+	}
+	return virtualserver.Validate(&data, mfr, path), nil
+}
+
+// mergeFieldErrors - appends the fields of b that are not already in a.
+func mergeFieldErrors(a []shared.FieldError, b []shared.FieldError) (r []shared.FieldError) {
+	r = a
+	seen := make(map[string]bool)
+	for _, v := range a {
+		seen[v.Path+v.Reason] = true
+	}
+	for _, v := range b {
+		if !seen[v.Path+v.Reason] {
+			r = append(r, v)
+		}
+	}
+	return
+}
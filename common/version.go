@@ -0,0 +1,218 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ticketmaster/lbapi/secret"
+	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+// Version - document of a record accepted by a create, modify or migrate
+// request, taken from the audit log.
+type Version struct {
+	// Version - number of the version. 1 is the oldest.
+	Version int `json:"version"`
+	// AuditID - id of the audit record the version was taken from.
+	AuditID string `json:"audit_id"`
+	// Action - create, modify or migrate.
+	Action string `json:"action"`
+	// User - user that submitted the version.
+	User string `json:"user"`
+	// LastModified - time the version was submitted.
+	LastModified string `json:"last_modified"`
+	// Data - resource configuration. Private keys are redacted.
+	Data interface{} `json:"data"`
+}
+
+// VersionRecord - response of the versions route.
+type VersionRecord struct {
+	// ID - id of the record.
+	ID string `json:"id"`
+	// Versions - every version of the record, oldest first.
+	Versions []Version `json:"versions"`
+	// LastError - error returned by the request.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Versions - returns the version history of the record with id.
+func (o *Common) Versions(id string, oUser *userenv.User) (r VersionRecord, err error) {
+	r.ID = id
+	_, err = o.fetchRecord(id, oUser)
+	if err != nil {
+		return
+	}
+	r.Versions, err = o.versions(id)
+	return
+}
+
+// Rollback - resubmits version of the record with id through Modify.
+// Certificate private keys redacted from the version are restored from secure
+// storage. A key that is not stored may be left out only when the certificate
+// is unchanged on the current record, since the load balancer still holds it.
+func (o *Common) Rollback(id string, version int, oUser *userenv.User) (r DbRecord, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	// Get Record By Id. This operation queries the system db.
+	////////////////////////////////////////////////////////////////////////////
+	r, err = o.fetchRecord(id, oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Find version.
+	////////////////////////////////////////////////////////////////////////////
+	versions, err := o.versions(id)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	if version < 1 || version > len(versions) {
+		err = fmt.Errorf("%w - %s has no version %d", ErrNotFound, id, version)
+		r.LastError = err.Error()
+		return r, err
+	}
+	var data virtualserver.Data
+	err = shared.MarshalInterface(versions[version-1].Data, &data)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Restore private keys.
+	////////////////////////////////////////////////////////////////////////////
+	var current virtualserver.Data
+	shared.MarshalInterface(r.Data, &current)
+	err = rehydrate(&data, &current)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Submit the version as a full modify request. The record stays on the
+	// load balancer it currently lives on.
+	////////////////////////////////////////////////////////////////////////////
+	clientDbRecord := DbRecord{
+		ID:             r.ID,
+		LoadBalancerIP: r.LoadBalancerIP,
+		Platform:       r.Platform,
+		Source:         r.Source,
+		Data:           data,
+	}
+	payload, err := json.Marshal(clientDbRecord)
+	if err != nil {
+		r.LastError = err.Error()
+		return r, err
+	}
+	return o.Modify(payload, oUser)
+}
+
+// fetchRecord - returns the stored record with id.
+func (o *Common) fetchRecord(id string, oUser *userenv.User) (r DbRecord, err error) {
+	collection, err := o.FetchByID(id, oUser)
+	if err != nil {
+		return
+	}
+	if len(collection.DbRecords) == 0 {
+		return r, fmt.Errorf("%w - no records match %s", ErrNotFound, id)
+	}
+	return collection.DbRecords[0], nil
+}
+
+// versions - reads the successful create, modify and migrate requests of the
+// record with id from the audit log.
+func (o *Common) versions(id string) (r []Version, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	rows, err := o.Database.Client.Db.Query(`SELECT id, data, last_modified FROM public.audit
+		WHERE data->>'route'=$1 AND data->>'record_id'=$2 AND data->>'action' IN ('create', 'modify', 'migrate')
+		AND data->'after' IS NOT NULL AND coalesce(data->>'last_error', '')=''
+		ORDER BY last_modified, id`, o.Route, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	////////////////////////////////////////////////////////////////////////////
+	r = []Version{}
+	for rows.Next() {
+		var (
+			auditID      string
+			p            []byte
+			lastModified time.Time
+			entry        AuditEntry
+		)
+		err = rows.Scan(&auditID, &p, &lastModified)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(p, &entry)
+		if err != nil {
+			return
+		}
+		r = append(r, Version{
+			Version:      len(r) + 1,
+			AuditID:      auditID,
+			Action:       entry.Action,
+			User:         entry.User,
+			LastModified: lastModified.UTC().Format(time.RFC3339Nano),
+			Data:         entry.After,
+		})
+	}
+	err = rows.Err()
+	return
+}
+
+// keepSecrets - saves the certificate private keys of d to secure storage.
+func (o *Common) keepSecrets(d *DbRecord, oUser *userenv.User) {
+	var data virtualserver.Data
+	if shared.MarshalInterface(d.Data, &data) != nil {
+		return
+	}
+	for _, v := range data.Certificates {
+		if v.Key.PrivateKey == "" || v.Key.PrivateKey == redacted {
+			continue
+		}
+		var user string
+		if oUser != nil {
+			user = oUser.Username
+		}
+		err := secret.Store(v.Certificate, v.Key, user)
+		if err != nil && o.Log != nil {
+			o.Log.Warn(err)
+		}
+	}
+}
+
+// rehydrate - replaces the redacted private keys of data with the ones in
+// secure storage. Keys of certificates that match current are dropped when
+// they are not stored, leaving the load balancer's key in place.
+func rehydrate(data *virtualserver.Data, current *virtualserver.Data) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	installed := make(map[string]bool)
+	for _, v := range current.Certificates {
+		installed[v.Name+"\n"+v.Certificate] = true
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for k, v := range data.Certificates {
+		if v.Key.PassPhrase == redacted {
+			data.Certificates[k].Key.PassPhrase = ""
+		}
+		if v.Key.PrivateKey != redacted {
+			continue
+		}
+		key, lookupErr := secret.Lookup(v.Certificate)
+		switch {
+		case lookupErr == nil:
+			data.Certificates[k].Key.PrivateKey = key.PrivateKey
+			data.Certificates[k].Key.PassPhrase = key.PassPhrase
+		case installed[v.Name+"\n"+v.Certificate]:
+			data.Certificates[k].Key.PrivateKey = ""
+			data.Certificates[k].Key.PassPhrase = ""
+		default:
+			return fmt.Errorf("%w - %s - %v", ErrKeyNotStored, v.Name, lookupErr)
+		}
+	}
+	return
+}
@@ -0,0 +1,42 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/certificate"
+	"github.com/ticketmaster/lbapi/virtualserver"
+)
+
+func TestRehydrate(t *testing.T) {
+	cert := func(name string, pem string, key string, pass string) certificate.Data {
+		return certificate.Data{Name: name, Certificate: pem, Key: certificate.Key{PrivateKey: key, PassPhrase: pass}}
+	}
+	current := virtualserver.Data{Certificates: []certificate.Data{cert("installed", "pem-a", "", "")}}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name string
+		cert certificate.Data
+		want certificate.Key
+		err  error
+	}{
+		{"plain key is kept", cert("new", "pem-b", "key", "pass"), certificate.Key{PrivateKey: "key", PassPhrase: "pass"}, nil},
+		{"redacted passphrase is dropped", cert("new", "pem-b", "key", redacted), certificate.Key{PrivateKey: "key"}, nil},
+		{"installed certificate keeps the load balancer key", cert("installed", "pem-a", redacted, redacted), certificate.Key{}, nil},
+		{"missing key fails", cert("new", "pem-b", redacted, ""), certificate.Key{}, ErrKeyNotStored},
+		{"renamed certificate fails", cert("renamed", "pem-a", redacted, ""), certificate.Key{}, ErrKeyNotStored},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := virtualserver.Data{Certificates: []certificate.Data{tt.cert}}
+			err := rehydrate(&data, &current)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if tt.err == nil && data.Certificates[0].Key != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, data.Certificates[0].Key)
+			}
+		})
+	}
+}
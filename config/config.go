@@ -50,11 +50,18 @@ func (c *Setting) Read() {
 		// Lbm
 		////////////////////////////////////////////////////////////////////////
 		c.Lbm.KeyFile = os.Getenv("LBM_KEYFILE")
+		c.Lbm.Environment = os.Getenv("LBM_ENVIRONMENT")
 		c.Lbm.PemFile = os.Getenv("LBM_PEMFILE")
 		enableTLS := os.Getenv("LBMRUNTLS")
 		if strings.ToLower(enableTLS) == "true" {
 			c.Lbm.RunTLS = true
 		}
+		c.Lbm.RecycleRetentionDays, _ = strconv.Atoi(os.Getenv("LBM_RECYCLE_RETENTION_DAYS"))
+		c.Lbm.StatusRetentionDays, _ = strconv.Atoi(os.Getenv("LBM_STATUS_RETENTION_DAYS"))
+		c.Lbm.MigrateRetentionDays, _ = strconv.Atoi(os.Getenv("LBM_MIGRATE_RETENTION_DAYS"))
+		c.Lbm.PurgeIntervalHours, _ = strconv.Atoi(os.Getenv("LBM_PURGE_INTERVAL_HOURS"))
+		c.Lbm.ArchiveDir = os.Getenv("LBM_ARCHIVE_DIR")
+		c.Lbm.SessionSecret = os.Getenv("LBM_SESSION_SECRET")
 		////////////////////////////////////////////////////////////////////////
 		// Backup
 		////////////////////////////////////////////////////////////////////////
@@ -69,6 +76,57 @@ func (c *Setting) Read() {
 		if strings.ToLower(enableBackup) == "true" {
 			c.Backup.Enable = true
 		}
+		////////////////////////////////////////////////////////////////////////
+		// Webhook
+		////////////////////////////////////////////////////////////////////////
+		if urls := os.Getenv("WEBHOOK_URLS"); urls != "" {
+			c.Webhook.URLs = strings.Split(urls, ",")
+		}
+		c.Webhook.Secret = os.Getenv("WEBHOOK_SECRET")
+		c.Webhook.MaxAttempts, _ = strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+		enableWebhook := os.Getenv("WEBHOOK_ENABLE")
+		if strings.ToLower(enableWebhook) == "true" {
+			c.Webhook.Enable = true
+		}
+		////////////////////////////////////////////////////////////////////////
+		// Secrets
+		////////////////////////////////////////////////////////////////////////
+		c.Secrets.Key = os.Getenv("SECRETS_KEY")
+		////////////////////////////////////////////////////////////////////////
+		// Schedule
+		////////////////////////////////////////////////////////////////////////
+		c.Schedule.IntervalSeconds, _ = strconv.Atoi(os.Getenv("SCHEDULE_INTERVAL_SECONDS"))
+		////////////////////////////////////////////////////////////////////////
+		// Approval
+		////////////////////////////////////////////////////////////////////////
+		for _, v := range strings.Split(os.Getenv("APPROVAL_PRODUCT_CODES"), ",") {
+			if code, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				c.Approval.ProductCodes = append(c.Approval.ProductCodes, code)
+			}
+		}
+		if environments := os.Getenv("APPROVAL_ENVIRONMENTS"); environments != "" {
+			c.Approval.Environments = strings.Split(environments, ",")
+		}
+		////////////////////////////////////////////////////////////////////////
+		// Token
+		////////////////////////////////////////////////////////////////////////
+		c.Token.PublicKey = os.Getenv("TOKEN_PUBLIC_KEY")
+		c.Token.JWKS = os.Getenv("TOKEN_JWKS")
+		c.Token.CacheMinutes, _ = strconv.Atoi(os.Getenv("TOKEN_CACHE_MINUTES"))
+		c.Token.Issuer = os.Getenv("TOKEN_ISSUER")
+		if audience := os.Getenv("TOKEN_AUDIENCE"); audience != "" {
+			c.Token.Audience = strings.Split(audience, ",")
+		}
+		c.Token.LeewaySeconds, _ = strconv.Atoi(os.Getenv("TOKEN_LEEWAY_SECONDS"))
+		////////////////////////////////////////////////////////////////////////
+		// Roles
+		////////////////////////////////////////////////////////////////////////
+		for _, v := range strings.Split(os.Getenv("ROLES_MAPPINGS"), ",") {
+			if i := strings.LastIndex(v, "="); i > 0 {
+				c.Roles.Mappings = append(c.Roles.Mappings, RoleMapping{Group: strings.TrimSpace(v[:i]), Role: strings.TrimSpace(v[i+1:])})
+			}
+		}
+		c.Roles.DefaultRole = os.Getenv("ROLES_DEFAULT_ROLE")
 	}
 }
 
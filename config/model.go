@@ -10,6 +10,12 @@ type Setting struct {
 	Backup     Backup
 	NetAPI     NetAPI
 	Prometheus Prometheus
+	Webhook    Webhook
+	Secrets    Secrets
+	Schedule   Schedule
+	Approval   Approval
+	Token      Token
+	Roles      Roles
 }
 
 // Avi stores avi settings.
@@ -53,6 +59,18 @@ type Lbm struct {
 	KeyFile            string
 	PemFile            string
 	RunTLS             bool
+	// Retention of the recycle, status and migrate tables in days. Zero
+	// keeps records forever.
+	RecycleRetentionDays int
+	StatusRetentionDays  int
+	MigrateRetentionDays int
+	// PurgeIntervalHours - how often the purge job runs. Zero disables it.
+	PurgeIntervalHours int
+	// ArchiveDir - directory purged records are archived to.
+	ArchiveDir string
+	// SessionSecret - key the login session cookie is signed with. A random
+	// key is used when empty, so sessions end when the service restarts.
+	SessionSecret string
 }
 
 // Git backup settings.
@@ -78,3 +96,90 @@ type Prometheus struct {
 	Enable bool
 	URI    string
 }
+
+// Webhook stores outbound webhook settings.
+type Webhook struct {
+	Enable      bool
+	URLs        []string
+	Secret      string
+	MaxAttempts int
+}
+
+// Secrets stores the key protecting certificate private keys at rest.
+type Secrets struct {
+	Key string
+}
+
+// Schedule stores the scheduler settings and the named maintenance windows
+// changes can be scheduled into.
+type Schedule struct {
+	// IntervalSeconds - how often due changes are looked for. Defaults to 60.
+	IntervalSeconds int
+	Windows         []Window
+}
+
+// Approval stores the product codes and environments whose changes need the
+// approval of a second user.
+type Approval struct {
+	ProductCodes []int
+	// Environments - values of Lbm.Environment, e.g. "prod", in which every
+	// change needs approval.
+	Environments []string
+}
+
+// Token stores the keys and claims bearer tokens are verified against.
+type Token struct {
+	// PublicKey - PEM file of the RSA or ECDSA key tokens are signed with.
+	PublicKey string
+	// JWKS - file or http(s) url of a JSON Web Key Set. Keys are matched on
+	// the kid header of the token.
+	JWKS string
+	// CacheMinutes - how long keys read from JWKS are kept. Defaults to 60.
+	CacheMinutes int
+	// Issuer - required iss claim. Empty accepts any issuer.
+	Issuer string
+	// Audience - aud claims accepted. Empty accepts any audience.
+	Audience []string
+	// LeewaySeconds - clock skew allowed when checking exp and nbf.
+	LeewaySeconds int
+}
+
+// Roles stores the role model. Without Mappings the built-in model is used:
+// prd<code>-operator and prd<code>-limitedaccess are operators of the code,
+// Lbm.AdminGroup is admin and every user is a viewer.
+type Roles struct {
+	// Permissions - actions each role allows, e.g. viewer = ["fetch"]. Roles
+	// missing from it keep their built-in actions.
+	Permissions map[string][]string
+	// Mappings - groups granting each role.
+	Mappings []RoleMapping
+	// DefaultRole - role every user holds for every product code. Empty
+	// grants nothing.
+	DefaultRole string
+}
+
+// RoleMapping - grants Role to users with a group or role claim matching
+// Group.
+type RoleMapping struct {
+	// Group - case insensitive regular expression. {code} matches a product
+	// code and limits the role to it. Without it the role holds for every
+	// product code.
+	Group string
+	Role  string
+}
+
+// Window - maintenance window opening at the same time on the given days.
+type Window struct {
+	Name string
+	// Start - time the window opens, e.g. "02:00".
+	Start string
+	// DurationMinutes - changes not started by the end of the window fail.
+	// Zero leaves the window open ended.
+	DurationMinutes int
+	// Days - days the window opens on, e.g. ["tue", "thu"]. Empty means
+	// every day.
+	Days []string
+	// TimeZone - IANA zone of Start, e.g. "America/Los_Angeles". Defaults
+	// to UTC.
+	TimeZone string
+}
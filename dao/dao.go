@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ticketmaster/lbapi/config"
@@ -49,9 +51,7 @@ func (o *Client) Connect() *DAO {
 	// (in this case, `lib/pq`) used to register itself in `database/sql`
 	// The next argument specifies the parameters to be used in the env.DBO.
 	// Details about this string can be seen at https://godoc.org/githubcom/lib/pq.
-	db, err := sql.Open("postgres", fmt.Sprintf(
-		"user=%s password=%s dbname=%s host=%s port=%s sslmode=%s",
-		o.UserName, o.Password, o.Database, o.Host, strconv.Itoa(o.Port), o.SSLMode))
+	db, err := sql.Open("postgres", o.DataSourceName())
 	if err != nil {
 		err = errors.Wrapf(err,
 			"Couldn't open env.DBO to postgre database (%s)",
@@ -59,9 +59,30 @@ func (o *Client) Connect() *DAO {
 		log.Panic(err)
 	}
 	resp.Db = db
+	resp.Client = *o
 	return &resp
 }
 
+// DataSourceName returns the lib/pq connection string of the client.
+func (o *Client) DataSourceName() string {
+	return fmt.Sprintf(
+		"user=%s password=%s dbname=%s host=%s port=%s sslmode=%s",
+		o.UserName, o.Password, o.Database, o.Host, strconv.Itoa(o.Port), o.SSLMode)
+}
+
+// Listen opens a dedicated connection that receives every NOTIFY sent on
+// channel. The listener reconnects on its own; a nil notification is
+// delivered after each reconnect.
+func (o *DAO) Listen(channel string) (r *pq.Listener, err error) {
+	r = pq.NewListener(o.Client.DataSourceName(), 10*time.Second, time.Minute, nil)
+	err = r.Listen(channel)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return
+}
+
 // Close terminates a database connection.
 func (o *DAO) Close() (err error) {
 	if o.Db == nil {
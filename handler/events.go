@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+// heartbeat - interval of the comments keeping idle streams open through
+// proxies.
+const heartbeat = 30 * time.Second
+
+// Events - serves the Server-Sent Events stream of record changes at /events.
+func Events(route *gin.RouterGroup) {
+	handle(route, "events", "GET", "/events", "Events", streamEvents)
+}
+
+// streamEvents - writes every event matching the product_code, route and
+// record_id query params until the client disconnects.
+func streamEvents(c *gin.Context) {
+	var (
+		events <-chan webhook.Event
+		cancel func()
+	)
+	////////////////////////////////////////////////////////////////////////////
+	match, err := eventFilter(c)
+	if err == nil {
+		events, cancel, err = common.Subscribe()
+	}
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+		if err := json.NewEncoder(c.Writer).Encode(common.DbRecord{LastError: err.Error()}); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+	defer cancel()
+	////////////////////////////////////////////////////////////////////////////
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case e := <-events:
+			if match(e) {
+				c.Render(-1, sse.Event{Id: e.ID, Event: e.Type, Data: e})
+			}
+			return true
+		}
+	})
+}
+
+// eventFilter - returns the matcher built from the query params. Each param
+// may be repeated or comma separated. Events of product codes the user may
+// not fetch are never matched.
+func eventFilter(c *gin.Context) (r func(webhook.Event) bool, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	values := func(key string) map[string]bool {
+		m := make(map[string]bool)
+		for _, v := range c.QueryArray(key) {
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					m[s] = true
+				}
+			}
+		}
+		return m
+	}
+	productCodes := values("product_code")
+	routes := values("route")
+	ids := values("record_id")
+	allowed := make(map[string]bool)
+	codes, all := userenv.New(c).Codes(userenv.ActionFetch)
+	for _, v := range codes {
+		allowed[v] = true
+	}
+	for k := range productCodes {
+		if _, err = strconv.Atoi(k); err != nil {
+			return nil, fmt.Errorf("product_code must be a number - %s", k)
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	return func(e webhook.Event) bool {
+		if !all && !allowed[strconv.Itoa(e.ProductCode)] {
+			return false
+		}
+		if len(productCodes) > 0 && !productCodes[strconv.Itoa(e.ProductCode)] {
+			return false
+		}
+		if len(routes) > 0 && !routes[e.Route] {
+			return false
+		}
+		if len(ids) > 0 && !ids[e.RecordID] {
+			return false
+		}
+		return true
+	}, nil
+}
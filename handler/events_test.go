@@ -0,0 +1,70 @@
+package handler
+
+import (
+	b64 "encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/userenv"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+func TestEventFilter(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	gin.SetMode(gin.TestMode)
+	vs := webhook.Event{Route: "virtualserver", RecordID: "a", ProductCode: 1234}
+	lb := webhook.Event{Route: "loadbalancer", RecordID: "b", ProductCode: 5678}
+	scoped := config.Roles{Mappings: []config.RoleMapping{{Group: "prd{code}-operator", Role: userenv.RoleOperator}}}
+	tests := []struct {
+		name  string
+		roles config.Roles
+		query string
+		vs    bool
+		lb    bool
+		ok    bool
+	}{
+		{"no filter", config.Roles{}, "", true, true, true},
+		{"product code", config.Roles{}, "?product_code=1234", true, false, true},
+		{"comma separated", config.Roles{}, "?product_code=1234,5678", true, true, true},
+		{"repeated", config.Roles{}, "?product_code=1234&product_code=5678", true, true, true},
+		{"route", config.Roles{}, "?route=loadbalancer", false, true, true},
+		{"record id", config.Roles{}, "?record_id=a", true, false, true},
+		{"params are anded", config.Roles{}, "?route=virtualserver&record_id=b", false, false, true},
+		{"bad product code", config.Roles{}, "?product_code=prd1234", false, false, false},
+		{"scoped to the user's codes", scoped, "", true, false, true},
+		{"scope cannot be widened", scoped, "?product_code=5678", false, false, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Roles: tt.roles}
+			var (
+				match func(webhook.Event) bool
+				err   error
+			)
+			router := gin.New()
+			router.Use(sessions.Sessions("lbapi", cookie.NewStore([]byte("test"))))
+			router.GET("/api/v1/events", func(c *gin.Context) {
+				sessions.Default(c).Set("roles", b64.StdEncoding.EncodeToString([]byte("prd1234-operator")))
+				match, err = eventFilter(c)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/events"+tt.query, nil))
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected filter to build, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatal("expected an error")
+			case !tt.ok:
+				return
+			}
+			if match(vs) != tt.vs || match(lb) != tt.lb {
+				t.Fatalf("expected %v/%v, got %v/%v", tt.vs, tt.lb, match(vs), match(lb))
+			}
+		})
+	}
+}
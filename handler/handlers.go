@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"net"
+	"strconv"
 
+	"github.com/avinetworks/sdk/go/session"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/shared"
 	"github.com/ticketmaster/lbapi/userenv"
 )
 
@@ -19,6 +26,26 @@ func (h Handler) Create(c *gin.Context) {
 		c.Error(err)
 	}
 	////////////////////////////////////////////////////////////////////////////
+	// Replay the response of a request retried with the same Idempotency-Key.
+	////////////////////////////////////////////////////////////////////////////
+	key := c.GetHeader("Idempotency-Key")
+	idempotent, ok := h.Definition.(Idempotent)
+	if key != "" && ok {
+		stored, err := idempotent.ReserveIdempotencyKey(key, p, oUser)
+		if err != nil {
+			c.Status(errorStatus(err))
+			c.Error(err)
+			if err := json.NewEncoder(c.Writer).Encode(common.DbRecord{LastError: err.Error()}); err != nil {
+				c.Error(err)
+			}
+			return
+		}
+		if stored != nil {
+			replay(c, stored)
+			return
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
 	var r interface{}
 	if len(q["bulk"]) > 0 && q["bulk"][0] == "yes" {
 		handler, ok := h.Definition.(CreateBulk)
@@ -29,7 +56,8 @@ func (h Handler) Create(c *gin.Context) {
 		r, err = handler.CreateBulk(p, oUser)
 		if err != nil {
 			c.Error(err)
-			c.Status(400)
+			c.Status(errorStatus(err))
+			r = errorBody(r, err)
 		}
 	} else {
 		handler, ok := h.Definition.(Create)
@@ -40,9 +68,17 @@ func (h Handler) Create(c *gin.Context) {
 		r, err = handler.Create(p, oUser)
 		if err != nil {
 			c.Error(err)
-			c.Status(400)
+			c.Status(errorStatus(err))
 		}
 		////////////////////////////////////////////////////////////////////////////
+		setOperation(c, r)
+		r = errorBody(r, err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if key != "" && ok {
+		if err := idempotent.SaveIdempotencyKey(key, c.Writer.Status(), r, oUser); err != nil {
+			c.Error(err)
+		}
 	}
 	////////////////////////////////////////////////////////////////////////////
 	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
@@ -62,7 +98,7 @@ func (h Handler) ImportAll(c *gin.Context) {
 	////////////////////////////////////////////////////////////////////////////
 	r, err := handler.ImportAll(oUser)
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
 	////////////////////////////////////////////////////////////////////////////
@@ -77,6 +113,10 @@ func (h Handler) Delete(c *gin.Context) {
 	oUser := userenv.New(c)
 	////////////////////////////////////////////////////////////////////////////
 	filter := c.Param("id")
+	if scheduled(c) {
+		h.schedule(c, "delete", filter, nil, oUser)
+		return
+	}
 	////////////////////////////////////////////////////////////////////////////
 	handler, ok := h.Definition.(Delete)
 	if !ok {
@@ -85,7 +125,34 @@ func (h Handler) Delete(c *gin.Context) {
 	////////////////////////////////////////////////////////////////////////////
 	r, err := handler.Delete(filter, oUser)
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
+
+// DeleteBulk ...
+func (h Handler) DeleteBulk(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	p, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(DeleteBulk)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a bulk Delete method"))
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.DeleteBulk(p, c.Query("atomic") == "true", oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
 	////////////////////////////////////////////////////////////////////////////
@@ -108,7 +175,7 @@ func (h Handler) Fetch(c *gin.Context) {
 	}
 	r, err := handler.Fetch(p, 0, oUser)
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
 	toEncoder = r
@@ -132,7 +199,7 @@ func (h Handler) FetchVs(c *gin.Context) {
 	}
 	r, err := handler.FetchVirtualServices(p, 0, oUser)
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
 	toEncoder = r
@@ -155,7 +222,7 @@ func (h Handler) Backup(c *gin.Context) {
 	err := handler.Backup(oUser)
 	msg := "backup complete"
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
 		c.Error(err)
 		msg = err.Error()
 	}
@@ -179,9 +246,12 @@ func (h Handler) FetchByID(c *gin.Context) {
 	////////////////////////////////////////////////////////////////////////////
 	r, err := handler.FetchByID(filter, oUser)
 	if err != nil {
-		c.Status(400)
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
+	if len(r.DbRecords) == 1 {
+		c.Header("ETag", common.ETag(r.DbRecords[0]))
+	}
 	////////////////////////////////////////////////////////////////////////////
 	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
 		c.Error(err)
@@ -228,6 +298,7 @@ func (h Handler) Migrate(c *gin.Context) {
 		c.Status(200)
 		c.Error(err)
 	}
+	setOperation(c, r)
 	////////////////////////////////////////////////////////////////////////////
 	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
 		c.Error(err)
@@ -268,6 +339,21 @@ func (h Handler) Modify(c *gin.Context) {
 		c.Error(err)
 	}
 	////////////////////////////////////////////////////////////////////////////
+	// Dry run - returns the planned changes without applying them.
+	////////////////////////////////////////////////////////////////////////////
+	if c.Query("dry_run") == "true" {
+		h.plan(c, p, oUser)
+		return
+	}
+	if scheduled(c) {
+		h.schedule(c, "modify", "", p, oUser)
+		return
+	}
+	if c.Query("bulk") == "yes" {
+		h.modifyBulk(c, p, oUser)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
 	handler, ok := h.Definition.(Modify)
 	if !ok {
 		c.Error(errors.New("the handler definition does not contain a Put method"))
@@ -275,15 +361,446 @@ func (h Handler) Modify(c *gin.Context) {
 	////////////////////////////////////////////////////////////////////////////
 	r, err := handler.Modify(p, oUser)
 	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// modifyBulk - updates every record of the payload and returns the outcome of
+// each.
+func (h Handler) modifyBulk(c *gin.Context, p []byte, oUser *userenv.User) {
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(ModifyBulk)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a bulk Put method"))
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.ModifyBulk(p, c.Query("atomic") == "true", oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// DisableBinding ...
+func (h Handler) DisableBinding(c *gin.Context) {
+	h.bindingState(c, false)
+}
+
+// EnableBinding ...
+func (h Handler) EnableBinding(c *gin.Context) {
+	h.bindingState(c, true)
+}
+
+// bindingState - enables or disables a single pool member.
+func (h Handler) bindingState(c *gin.Context, enabled bool) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	state, err := memberState(c, enabled)
+	if err != nil {
+		c.Error(err)
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(BindingState)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a ModifyBindingState method"))
 		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.ModifyBindingState(c.Param("id"), c.Param("pool"), c.Param("ip"), state, oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
 		c.Error(err)
 	}
+	setOperation(c, r)
 	////////////////////////////////////////////////////////////////////////////
 	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
 		c.Error(err)
 	}
 }
 
+// DisableMaintenance ...
+func (h Handler) DisableMaintenance(c *gin.Context) {
+	h.maintenance(c, false)
+}
+
+// EnableMaintenance ...
+func (h Handler) EnableMaintenance(c *gin.Context) {
+	h.maintenance(c, true)
+}
+
+// maintenance - enables or disables a backend server on every virtual server.
+func (h Handler) maintenance(c *gin.Context, enabled bool) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	state, err := memberState(c, enabled)
+	if err != nil {
+		c.Error(err)
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Maintenance)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Maintenance method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Maintenance(c.Param("ip"), state, oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
+
+// memberState - builds the requested pool member state. Disable accepts the
+// optional graceful and delay (seconds) query params.
+func memberState(c *gin.Context, enabled bool) (r pool.MemberBinding, err error) {
+	r.Enabled = enabled
+	if enabled {
+		return
+	}
+	r.GracefulDisable = c.Query("graceful") == "true"
+	if delay := c.Query("delay"); delay != "" {
+		r.DisableDelay, err = strconv.Atoi(delay)
+	}
+	return
+}
+
+// Patch ...
+func (h Handler) Patch(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	id := c.Param("id")
+	////////////////////////////////////////////////////////////////////////////
+	p, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Patch)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Patch method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Patch(id, p, oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// Versions ...
+func (h Handler) Versions(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Versions)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Versions method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Versions(c.Param("id"), oUser)
+	if err != nil {
+		r.LastError = err.Error()
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
+
+// Rollback ...
+func (h Handler) Rollback(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	version, err := strconv.Atoi(c.Query("version"))
+	if err != nil {
+		c.Error(err)
+		c.Status(400)
+		json.NewEncoder(c.Writer).Encode(common.DbRecord{LastError: "version must be a number"})
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Rollback)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Rollback method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Rollback(c.Param("id"), version, oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// Restore ...
+func (h Handler) Restore(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Restore)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Restore method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Restore(c.Param("id"), c.Query("load_balancer_ip"), oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// Cancel ...
+func (h Handler) Cancel(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Cancel)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Cancel method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Cancel(c.Param("id"), oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
+
+// Approve ...
+func (h Handler) Approve(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Approve)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain an Approve method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Approve(c.Param("id"), oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	setOperation(c, r)
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// Reject ...
+func (h Handler) Reject(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	oUser := userenv.New(c)
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Reject)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Reject method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Reject(c.Param("id"), c.Query("reason"), oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
+
+// scheduled - returns true when the request asks for the change to be applied
+// later.
+func scheduled(c *gin.Context) bool {
+	return c.Query("apply_at") != "" || c.Query("window") != ""
+}
+
+// schedule - persists a modify or delete to be applied at the time given by
+// the apply_at or window query params.
+func (h Handler) schedule(c *gin.Context, action string, id string, p []byte, oUser *userenv.User) {
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Schedule)
+	if !ok {
+		err := errors.New("changes to this route cannot be scheduled")
+		c.Status(400)
+		c.Error(err)
+		if err := json.NewEncoder(c.Writer).Encode(common.DbRecord{LastError: err.Error()}); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Schedule(action, id, p, c.Query("apply_at"), c.Query("window"), oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	} else if r.ApprovalID != "" {
+		setOperation(c, r)
+	} else {
+		c.Header("Location", "/api/v1/schedule/"+r.ID)
+		c.Status(202)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// plan ...
+func (h Handler) plan(c *gin.Context, p []byte, oUser *userenv.User) {
+	////////////////////////////////////////////////////////////////////////////
+	handler, ok := h.Definition.(Plan)
+	if !ok {
+		c.Error(errors.New("the handler definition does not contain a Plan method"))
+		c.Status(400)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r, err := handler.Plan(p, oUser)
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if err := json.NewEncoder(c.Writer).Encode(errorBody(r, err)); err != nil {
+		c.Error(err)
+	}
+}
+
+// errorStatus - maps errors returned by the definition to a status code.
+// Errors of an unknown type are treated as client errors.
+func errorStatus(err error) int {
+	var (
+		validationErr *shared.ValidationError
+		syntaxErr     *json.SyntaxError
+		typeErr       *json.UnmarshalTypeError
+		pqErr         *pq.Error
+		aviErr        session.AviError
+		netErr        net.Error
+	)
+	switch {
+	case errors.As(err, &validationErr), errors.Is(err, common.ErrBulkAborted):
+		return 422
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return 400
+	case errors.Is(err, userenv.ErrNotAuthorized):
+		return 403
+	case errors.Is(err, common.ErrNotFound):
+		return 404
+	case errors.Is(err, common.ErrPreconditionFailed):
+		return 412
+	case errors.Is(err, common.ErrIdempotencyInProgress), errors.Is(err, common.ErrNotPending), errors.Is(err, common.ErrExists):
+		return 409
+	case errors.Is(err, common.ErrIdempotencyMismatch), errors.Is(err, common.ErrKeyNotStored):
+		return 422
+	case errors.As(err, &pqErr):
+		return 500
+	case errors.As(err, &aviErr), errors.As(err, &netErr):
+		return 502
+	}
+	return 400
+}
+
+// errorBody - replaces the response with the invalid fields when the payload
+// failed validation.
+func errorBody(r interface{}, err error) interface{} {
+	var validationErr *shared.ValidationError
+	if errors.As(err, &validationErr) {
+		return common.ErrorRecord{LastError: err.Error(), Errors: validationErr.Fields}
+	}
+	return r
+}
+
+// replay - writes the response stored for an Idempotency-Key.
+func replay(c *gin.Context, stored *common.IdempotentResponse) {
+	var r common.DbRecord
+	if err := json.Unmarshal(stored.Body, &r); err == nil {
+		setOperation(c, r)
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Status(stored.StatusCode)
+	if _, err := c.Writer.Write(append(stored.Body, '\n')); err != nil {
+		c.Error(err)
+	}
+}
+
+// setOperation - points the client at the operation tracking the request, or
+// at the change request holding it until it is approved.
+func setOperation(c *gin.Context, r interface{}) {
+	var id string
+	switch v := r.(type) {
+	case common.DbRecord:
+		id = v.OperationID
+		if id == "" && v.ApprovalID != "" {
+			c.Header("Location", "/api/v1/approvals/"+v.ApprovalID)
+			c.Status(202)
+			return
+		}
+	case common.MaintenanceRecord:
+		id = v.OperationID
+	}
+	if id == "" {
+		return
+	}
+	c.Header("Location", "/api/v1/operations/"+id)
+}
+
 // New - package constructor.
 func New(definition interface{}, route *gin.RouterGroup) (*Handler, error) {
 	handler := Handler{Definition: definition}
@@ -295,40 +812,72 @@ func New(definition interface{}, route *gin.RouterGroup) (*Handler, error) {
 	////////////////////////////////////////////////////////////////////////////
 	routeString := rh.GetRoute()
 	if _, ok := definition.(Fetch); ok {
-		route.GET("/"+routeString, handler.Fetch)
+		handle(route, routeString, "GET", "/"+routeString, "Fetch", handler.Fetch)
 	}
 	if _, ok := definition.(FetchByID); ok {
-		route.GET("/"+routeString+"/:id", handler.FetchByID)
+		handle(route, routeString, "GET", "/"+routeString+"/:id", "FetchByID", handler.FetchByID)
 	}
 	if _, ok := definition.(Modify); ok {
-		route.PUT("/"+routeString, handler.Modify)
+		handle(route, routeString, "PUT", "/"+routeString, "Modify", handler.Modify)
 	}
 	if _, ok := definition.(Create); ok {
-		route.POST("/"+routeString, handler.Create)
+		handle(route, routeString, "POST", "/"+routeString, "Create", handler.Create)
 	}
 	if _, ok := definition.(Delete); ok {
-		route.DELETE("/"+routeString+"/:id", handler.Delete)
+		handle(route, routeString, "DELETE", "/"+routeString+"/:id", "Delete", handler.Delete)
+	}
+	if _, ok := definition.(DeleteBulk); ok {
+		handle(route, routeString, "DELETE", "/"+routeString, "DeleteBulk", handler.DeleteBulk)
+	}
+	if _, ok := definition.(Restore); ok {
+		handle(route, routeString, "POST", "/"+routeString+"/:id/restore", "Restore", handler.Restore)
+	}
+	if _, ok := definition.(Cancel); ok {
+		handle(route, routeString, "POST", "/"+routeString+"/:id/cancel", "Cancel", handler.Cancel)
+	}
+	if _, ok := definition.(Approve); ok {
+		handle(route, routeString, "POST", "/"+routeString+"/:id/approve", "Approve", handler.Approve)
+	}
+	if _, ok := definition.(Reject); ok {
+		handle(route, routeString, "POST", "/"+routeString+"/:id/reject", "Reject", handler.Reject)
 	}
 	if routeString != "recycle" {
 		if _, ok := definition.(ImportAll); ok {
-			route.POST("/source/"+routeString, handler.ImportAll)
+			handle(route, routeString, "POST", "/source/"+routeString, "ImportAll", handler.ImportAll)
 		}
 	}
 	if routeString == "virtualserver" {
+		if _, ok := definition.(Patch); ok {
+			handle(route, routeString, "PATCH", "/"+routeString+"/:id", "Patch", handler.Patch)
+		}
+		if _, ok := definition.(Versions); ok {
+			handle(route, routeString, "GET", "/"+routeString+"/:id/versions", "Versions", handler.Versions)
+		}
+		if _, ok := definition.(Rollback); ok {
+			handle(route, routeString, "POST", "/"+routeString+"/:id/rollback", "Rollback", handler.Rollback)
+		}
+		if _, ok := definition.(BindingState); ok {
+			handle(route, routeString, "POST", "/"+routeString+"/:id/pools/:pool/bindings/:ip/disable", "DisableBinding", handler.DisableBinding)
+			handle(route, routeString, "POST", "/"+routeString+"/:id/pools/:pool/bindings/:ip/enable", "EnableBinding", handler.EnableBinding)
+		}
+		if _, ok := definition.(Maintenance); ok {
+			handle(route, routeString, "POST", "/maintenance/"+routeString+"/:ip/disable", "DisableMaintenance", handler.DisableMaintenance)
+			handle(route, routeString, "POST", "/maintenance/"+routeString+"/:ip/enable", "EnableMaintenance", handler.EnableMaintenance)
+		}
 		if _, ok := definition.(Backup); ok {
-			route.GET("/simple/"+routeString, handler.FetchVs)
+			handle(route, routeString, "GET", "/simple/"+routeString, "FetchVs", handler.FetchVs)
 		}
 		if _, ok := definition.(Backup); ok {
-			route.POST("/backup/"+routeString, handler.Backup)
+			handle(route, routeString, "POST", "/backup/"+routeString, "Backup", handler.Backup)
 		}
 		if _, ok := definition.(StageMigration); ok {
-			route.POST("/migrate/"+routeString+"/:id", handler.StageMigration)
+			handle(route, routeString, "POST", "/migrate/"+routeString+"/:id", "StageMigration", handler.StageMigration)
 		}
 		if _, ok := definition.(Migrate); ok {
-			route.PUT("/migrate/"+routeString+"/:id", handler.Migrate)
+			handle(route, routeString, "PUT", "/migrate/"+routeString+"/:id", "Migrate", handler.Migrate)
 		}
 		if _, ok := definition.(FetchStaged); ok {
-			route.GET("/migrate/"+routeString+"/:id", handler.FetchStaged)
+			handle(route, routeString, "GET", "/migrate/"+routeString+"/:id", "FetchStaged", handler.FetchStaged)
 		}
 	}
 	return &handler, nil
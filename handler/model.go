@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/pool"
 	"github.com/ticketmaster/lbapi/userenv"
 )
 
@@ -15,6 +16,12 @@ type CreateBulk interface {
 	CreateBulk([]byte, *userenv.User) (common.DbRecordCollection, error)
 }
 
+// Idempotent ...
+type Idempotent interface {
+	ReserveIdempotencyKey(string, []byte, *userenv.User) (*common.IdempotentResponse, error)
+	SaveIdempotencyKey(string, int, interface{}, *userenv.User) error
+}
+
 // ImportAll ...
 type ImportAll interface {
 	ImportAll(*userenv.User) (common.DbRecordCollection, error)
@@ -29,6 +36,11 @@ type Delete interface {
 	Delete(string, *userenv.User) (common.DbRecord, error)
 }
 
+// DeleteBulk ...
+type DeleteBulk interface {
+	DeleteBulk([]byte, bool, *userenv.User) (common.BulkRecord, error)
+}
+
 // Fetch ...
 type Fetch interface {
 	Fetch(map[string][]string, int, *userenv.User) (common.DbRecordCollection, error)
@@ -74,6 +86,66 @@ type Modify interface {
 	Modify([]byte, *userenv.User) (r common.DbRecord, err error)
 }
 
+// ModifyBulk ...
+type ModifyBulk interface {
+	ModifyBulk([]byte, bool, *userenv.User) (common.BulkRecord, error)
+}
+
+// BindingState ...
+type BindingState interface {
+	ModifyBindingState(string, string, string, pool.MemberBinding, *userenv.User) (common.DbRecord, error)
+}
+
+// Maintenance ...
+type Maintenance interface {
+	Maintenance(string, pool.MemberBinding, *userenv.User) (common.MaintenanceRecord, error)
+}
+
+// Patch ...
+type Patch interface {
+	Patch(string, []byte, *userenv.User) (common.DbRecord, error)
+}
+
+// Versions ...
+type Versions interface {
+	Versions(string, *userenv.User) (common.VersionRecord, error)
+}
+
+// Rollback ...
+type Rollback interface {
+	Rollback(string, int, *userenv.User) (common.DbRecord, error)
+}
+
+// Restore ...
+type Restore interface {
+	Restore(string, string, *userenv.User) (common.DbRecord, error)
+}
+
+// Schedule ...
+type Schedule interface {
+	Schedule(string, string, []byte, string, string, *userenv.User) (common.DbRecord, error)
+}
+
+// Cancel ...
+type Cancel interface {
+	Cancel(string, *userenv.User) (common.DbRecord, error)
+}
+
+// Approve ...
+type Approve interface {
+	Approve(string, *userenv.User) (common.DbRecord, error)
+}
+
+// Reject ...
+type Reject interface {
+	Reject(string, string, *userenv.User) (common.DbRecord, error)
+}
+
+// Plan ...
+type Plan interface {
+	Plan([]byte, *userenv.User) (common.PlanRecord, error)
+}
+
 // Backup ...
 type Backup interface {
 	Backup(*userenv.User) (err error)
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/openapi"
+)
+
+var (
+	// routeTable - every route registered by New, in registration order.
+	routeTable []openapi.Route
+	routeMu    sync.Mutex
+)
+
+// handle - registers f on route, guarded by the permission of action, and
+// records it in the route table.
+func handle(route *gin.RouterGroup, resource string, method string, path string, action string, f gin.HandlerFunc) {
+	route.Handle(method, path, authorize(action, f))
+	routeMu.Lock()
+	defer routeMu.Unlock()
+	routeTable = append(routeTable, openapi.Route{
+		Method:   method,
+		Path:     route.BasePath() + path,
+		Resource: resource,
+		Action:   action,
+	})
+}
+
+// OpenAPI - serves the OpenAPI document describing every route registered by
+// New at /openapi.json. Register it after all definitions.
+func OpenAPI(route *gin.RouterGroup) {
+	handle(route, "openapi", "GET", "/openapi.json", "OpenAPI", serveOpenAPI)
+}
+
+// serveOpenAPI ...
+func serveOpenAPI(c *gin.Context) {
+	////////////////////////////////////////////////////////////////////////////
+	routeMu.Lock()
+	r := openapi.New(routeTable)
+	routeMu.Unlock()
+	////////////////////////////////////////////////////////////////////////////
+	c.Header("Content-Type", "application/json")
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
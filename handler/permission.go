@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// permissions - permission each handler action needs for at least one product
+// code. The product code of the record is checked again when it is known.
+// Approve, reject and cancel check the action of the change they release.
+// Purge checks for the admin role and OpenAPI is served to anyone.
+var permissions = map[string]string{
+	"Fetch":              userenv.ActionFetch,
+	"FetchByID":          userenv.ActionFetch,
+	"FetchVs":            userenv.ActionFetch,
+	"FetchStaged":        userenv.ActionFetch,
+	"Versions":           userenv.ActionFetch,
+	"Events":             userenv.ActionFetch,
+	"Cancel":             userenv.ActionFetch,
+	"Approve":            userenv.ActionFetch,
+	"Reject":             userenv.ActionFetch,
+	"Create":             userenv.ActionCreate,
+	"Restore":            userenv.ActionCreate,
+	"Modify":             userenv.ActionModify,
+	"Patch":              userenv.ActionModify,
+	"Rollback":           userenv.ActionModify,
+	"EnableBinding":      userenv.ActionModify,
+	"DisableBinding":     userenv.ActionModify,
+	"EnableMaintenance":  userenv.ActionModify,
+	"DisableMaintenance": userenv.ActionModify,
+	"Delete":             userenv.ActionDelete,
+	"DeleteBulk":         userenv.ActionDelete,
+	"StageMigration":     userenv.ActionMigrate,
+	"Migrate":            userenv.ActionMigrate,
+	"ImportAll":          userenv.ActionImport,
+	"Backup":             userenv.ActionBackup,
+}
+
+// authorize - returns f guarded by the permission of action. Requests from
+// users without it are rejected with 403.
+func authorize(action string, f gin.HandlerFunc) gin.HandlerFunc {
+	permission, ok := permissions[action]
+	if !ok {
+		return f
+	}
+	return func(c *gin.Context) {
+		if err := userenv.New(c).HasPermission(permission); err != nil {
+			c.Status(errorStatus(err))
+			c.Error(err)
+			if err := json.NewEncoder(c.Writer).Encode(common.DbRecord{LastError: err.Error()}); err != nil {
+				c.Error(err)
+			}
+			return
+		}
+		f(c)
+	}
+}
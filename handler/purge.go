@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Purge - serves the retention purge at /purge. GET previews what would be
+// purged and POST archives and removes it.
+func Purge(route *gin.RouterGroup) {
+	handle(route, "purge", "GET", "/purge", "PreviewPurge", purge(true))
+	handle(route, "purge", "POST", "/purge", "Purge", purge(false))
+}
+
+// purge - returns the handler running common.Purge.
+func purge(dryRun bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := common.Purge(dryRun, userenv.New(c))
+		if err != nil {
+			c.Status(errorStatus(err))
+			c.Error(err)
+			r.LastError = err.Error()
+		}
+		if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+			c.Error(err)
+		}
+	}
+}
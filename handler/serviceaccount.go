@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// ServiceAccounts - serves the service accounts and their API tokens at
+// /serviceaccounts. Only owners of an account and admins may see or change it
+// and its tokens.
+func ServiceAccounts(route *gin.RouterGroup) {
+	handle(route, "serviceaccounts", "GET", "/serviceaccounts", "FetchServiceAccounts", fetchServiceAccounts)
+	handle(route, "serviceaccounts", "POST", "/serviceaccounts", "CreateServiceAccount", createServiceAccount)
+	handle(route, "serviceaccounts", "DELETE", "/serviceaccounts/:name", "DeleteServiceAccount", deleteServiceAccount)
+	handle(route, "serviceaccounts", "GET", "/serviceaccounts/:name/tokens", "FetchTokens", fetchTokens)
+	handle(route, "serviceaccounts", "POST", "/serviceaccounts/:name/tokens", "IssueToken", issueToken)
+	handle(route, "serviceaccounts", "DELETE", "/serviceaccounts/:name/tokens/:token", "RevokeToken", revokeToken)
+}
+
+// fetchServiceAccounts ...
+func fetchServiceAccounts(c *gin.Context) {
+	r, err := common.FetchServiceAccounts(userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// createServiceAccount ...
+func createServiceAccount(c *gin.Context) {
+	p, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		encodeAccount(c, nil, err)
+		return
+	}
+	r, err := common.CreateServiceAccount(p, userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// deleteServiceAccount ...
+func deleteServiceAccount(c *gin.Context) {
+	r, err := common.DeleteServiceAccount(c.Param("name"), userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// fetchTokens ...
+func fetchTokens(c *gin.Context) {
+	r, err := common.FetchTokens(c.Param("name"), userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// issueToken ...
+func issueToken(c *gin.Context) {
+	p, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		encodeAccount(c, nil, err)
+		return
+	}
+	r, err := common.IssueToken(c.Param("name"), p, userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// revokeToken ...
+func revokeToken(c *gin.Context) {
+	r, err := common.RevokeToken(c.Param("name"), c.Param("token"), userenv.New(c))
+	encodeAccount(c, r, err)
+}
+
+// encodeAccount - writes r, or err in last_error.
+func encodeAccount(c *gin.Context, r interface{}, err error) {
+	if err != nil {
+		c.Status(errorStatus(err))
+		c.Error(err)
+		r = common.DbRecord{LastError: err.Error()}
+	}
+	if err := json.NewEncoder(c.Writer).Encode(r); err != nil {
+		c.Error(err)
+	}
+}
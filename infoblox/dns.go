@@ -2,10 +2,12 @@ package infoblox
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/ticketmaster/infoblox-go-sdk/model"
+	"github.com/ticketmaster/lbapi/config"
 )
 
 func (o *Infoblox) setIpv4Addrs(addr string) []model.Ipv4Addr {
@@ -60,8 +62,9 @@ func (o *Infoblox) Create(ip string, productCode int, data []string) (r []string
 	return r, nil
 }
 
-// Modify - updates the A record.
-func (o *Infoblox) Modify(ip string, productCode int, data []string) (r []string, err error) {
+// Diff - compares requested dns names against the records bound to the ip.
+// The primary lb record is never reported as removed.
+func (o *Infoblox) Diff(ip string, productCode int, data []string) (added []string, removed []string, err error) {
 	////////////////////////////////////////////////////////////////////////////
 	d := make(map[string]string)
 	s := make(map[string]string)
@@ -69,7 +72,7 @@ func (o *Infoblox) Modify(ip string, productCode int, data []string) (r []string
 	var source []string
 	sresp, err := o.Client.RecordHostClient.FetchByIPAddress(ip)
 	if err != nil {
-		o.Log.Warn(err)
+		return
 	}
 	for _, v := range sresp.Result {
 		source = append(source, v.Name)
@@ -86,37 +89,64 @@ func (o *Infoblox) Modify(ip string, productCode int, data []string) (r []string
 	////////////////////////////////////////////////////////////////////////////
 	for k, v := range d {
 		if s[k] == "" {
-			req := o.setRecordHostCreateRequest(v, ip)
-			_, err = o.Client.RecordHostClient.Create(*req)
-			if err != nil {
-				o.Log.Warn(err)
-				continue
-			}
+			added = append(added, v)
 		}
 	}
 	////////////////////////////////////////////////////////////////////////////
 	// Deleted.
 	////////////////////////////////////////////////////////////////////////////
+	lbRecord := o.setName(ip, strconv.Itoa(productCode))
 	for k, v := range s {
-		lbRecord := o.setName(ip, strconv.Itoa(productCode))
 		if k == *lbRecord {
 			continue
 		}
 		if d[k] == "" {
-			resp, err := o.Client.RecordHostClient.FetchByName(v)
-			if err != nil {
-				o.Log.Warn(err)
-				continue
-			}
-			if len(resp.Result) == 0 {
-				continue
-			}
-			o.Log.Warnf("deleting %s %+v", resp.Result[0].Name, resp.Result[0].Ipv4Addrs)
-			_, err = o.Client.RecordHostClient.Delete(resp.Result[0].Ref)
-			if err != nil {
-				o.Log.Warn(err)
-				continue
-			}
+			removed = append(removed, v)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+// Modify - updates the A record.
+func (o *Infoblox) Modify(ip string, productCode int, data []string) (r []string, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	// Existing records could not be retrieved, so only additions are applied.
+	////////////////////////////////////////////////////////////////////////////
+	added, removed, err := o.Diff(ip, productCode, data)
+	if err != nil {
+		o.Log.Warn(err)
+		added = data
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Added.
+	////////////////////////////////////////////////////////////////////////////
+	for _, v := range added {
+		req := o.setRecordHostCreateRequest(v, ip)
+		_, err = o.Client.RecordHostClient.Create(*req)
+		if err != nil {
+			o.Log.Warn(err)
+			continue
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Deleted.
+	////////////////////////////////////////////////////////////////////////////
+	for _, v := range removed {
+		resp, err := o.Client.RecordHostClient.FetchByName(v)
+		if err != nil {
+			o.Log.Warn(err)
+			continue
+		}
+		if len(resp.Result) == 0 {
+			continue
+		}
+		o.Log.Warnf("deleting %s %+v", resp.Result[0].Name, resp.Result[0].Ipv4Addrs)
+		_, err = o.Client.RecordHostClient.Delete(resp.Result[0].Ref)
+		if err != nil {
+			o.Log.Warn(err)
+			continue
 		}
 	}
 	////////////////////////////////////////////////////////////////////////////
@@ -153,3 +183,39 @@ func (o *Infoblox) Delete(data []string) (err error) {
 	}
 	return
 }
+
+// PrimaryName - returns the lb record created for ip.
+func (o *Infoblox) PrimaryName(ip string, productCode int) string {
+	return *o.setName(ip, strconv.Itoa(productCode))
+}
+
+// Available - returns true when ip may be reserved again for a vip owning
+// names: no host record other than its primary record or names points at it
+// and, when NetAPI is enabled, nothing answers on it.
+func (o *Infoblox) Available(ip string, productCode int, names []string) (b bool, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	owned := make(map[string]bool)
+	owned[o.PrimaryName(ip, productCode)] = true
+	for _, v := range names {
+		owned[v] = true
+	}
+	////////////////////////////////////////////////////////////////////////////
+	resp, err := o.Client.RecordHostClient.FetchByIPAddress(ip)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range resp.Result {
+		if !owned[v.Name] {
+			return false, nil
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if !config.GlobalConfig.NetAPI.Enable {
+		return true, nil
+	}
+	inUse, err := o.IPInUse(ip)
+	if err != nil {
+		return false, err
+	}
+	return !inUse, nil
+}
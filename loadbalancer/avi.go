@@ -238,26 +238,10 @@ func (o *Avi) FetchCollections() (err error) {
 	o.VsVips.System = make(map[string]VsVip)
 	////////////////////////////////////////////////////////////////////////////
 	o.ServiceTypes.Source = make(map[string]Service)
-	o.ServiceTypes.System = make(map[string]Service)
-	o.ServiceTypes.System["https"] = Service{Name: "System-Secure-HTTP", UUID: "", APIName: "https", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["syslog"] = Service{Name: "System-Syslog", UUID: "", APIName: "syslog", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["https-no-secure-cookies"] = Service{Name: "System-Secure-HTTP-No-Secure-Cookies", UUID: "", APIName: "https-no-secure-cookies", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["http"] = Service{Name: "System-HTTP", UUID: "", APIName: "http", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["http-multiplex-disabled"] = Service{Name: "System-HTTP-Multiplex-Disabled", UUID: "", APIName: "http-multiplex-disabled", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["ssl-l4-app"] = Service{Name: "System-SSL-Application", UUID: "", APIName: "ssl-l4-app", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["ssl-vdi"] = Service{Name: "System-Secure-HTTP-VDI", UUID: "", APIName: "ssl-vdi", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["l4-app"] = Service{Name: "System-L4-Application", UUID: "", APIName: "l4-app", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["l4-app-udp"] = Service{Name: "System-L4-Application-UDP", UUID: "", APIName: "l4-app-udp", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["ssl-bridge"] = Service{Name: "System-SSL-Bridge", UUID: "", APIName: "ssl-bridge", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
-	o.ServiceTypes.System["dns"] = Service{Name: "System-DNS", UUID: "", APIName: "dns", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	o.ServiceTypes.System = aviServiceTypes()
 	////////////////////////////////////////////////////////////////////////////
 	o.NetworkProfiles.Source = make(map[string]NetworkProfile)
-	o.NetworkProfiles.System = make(map[string]NetworkProfile)
-	o.NetworkProfiles.System["tcp"] = NetworkProfile{Name: "System-TCP-Proxy", UUID: "", APIName: "tcp"}
-	o.NetworkProfiles.System["udp"] = NetworkProfile{Name: "System-UDP-Fast-Path", UUID: "", APIName: "udp"}
-	o.NetworkProfiles.System["udp-fast-path-vdi"] = NetworkProfile{Name: "System-UDP-Fast-Path-VDI", UUID: "", APIName: "udp-fast-path-vdi"}
-	o.NetworkProfiles.System["udp-per-pkt"] = NetworkProfile{Name: "System-UDP-Per-Pkt", UUID: "", APIName: "udp-per-pkt"}
-	o.NetworkProfiles.System["udp-no-snat"] = NetworkProfile{Name: "System-UDP-No-SNAT", UUID: "", APIName: "udp-no-snat"}
+	o.NetworkProfiles.System = aviNetworkProfiles()
 	////////////////////////////////////////////////////////////////////////////
 	// VsVip
 	////////////////////////////////////////////////////////////////////////////
@@ -32,9 +32,10 @@ type Data struct {
 	Model string `json:"model,omitempty"`
 	// Serial - serial number of appliance. Netscaler only.
 	Serial string `json:"serial,omitempty"`
+	// ProductCode - product code associated with record.
+	ProductCode int `json:"product_code,omitempty"`
 	// DeviceID - unique identifier for licensing. Netscaler only.
-	ProductCode int    `json:"product_code,omitempty"`
-	DeviceID    string `json:"device_id,omitempty"`
+	DeviceID string `json:"device_id,omitempty"`
 	// Status - system status.
 	Status string `json:"status,omitempty"`
 	// IPAddresses - list of all the ip addresses mapped to the unit.
@@ -78,7 +79,7 @@ type IPAddress struct {
 	IP string `json:"ip,omitempty"`
 	// DNS -reverse dns lookup of ip.
 	DNS []string `json:"dns,omitempty"`
-	// Type ipv4 or ipv6.
+	// Type - address type as reported by Netscaler, e.g. SNIP, VIP or NSIP.
 	Type string `json:"type,omitempty"`
 	// Enabled - state of interface.
 	Enabled bool `json:"enabled"`
@@ -167,14 +167,7 @@ func (o *Netscaler) FetchCollections() (err error) {
 	////////////////////////////////////////////////////////////////////////////
 	// Supported protocols for frontend.
 	////////////////////////////////////////////////////////////////////////////
-	system := make(map[string]Service)
-	system["https"] = Service{Name: "SSL", UUID: "SSL", APIName: "https"}
-	system["http"] = Service{Name: "HTTP", UUID: "HTTP", APIName: "http"}
-	system["l4-app-tcp"] = Service{Name: "TCP", UUID: "TCP", APIName: "l4-app-tcp"}
-	system["l4-app"] = Service{Name: "TCP", UUID: "TCP", APIName: "l4-app"}
-	system["dns"] = Service{Name: "DNS_TCP", UUID: "DNS_TCP", APIName: "dns"}
-	system["l4-app-udp"] = Service{Name: "UDP", UUID: "UDP", APIName: "l4-app-udp"}
-	system["ssl-bridge"] = Service{Name: "SSL_BRIDGE", UUID: "SSL_BRIDGE", APIName: "ssl-bridge"}
+	system := netscalerServiceTypes()
 	source := make(map[string]Service)
 	source["SSL"] = Service{Name: "SSL", APIName: "https", UUID: "SSL"}
 	source["HTTP"] = Service{Name: "HTTP", APIName: "http", UUID: "HTTP"}
@@ -37,7 +37,7 @@ func (o *Netscaler) etlFetchHanode(in []model.Hanode, data *Data) {
 		hapartner.IP = val.Ipaddress
 		role := "active"
 		if val.State == "secondary" {
-			role = "standy"
+			role = "standby"
 		}
 		hapartner.Role = role
 		hapartner.Status = val.Hastatus
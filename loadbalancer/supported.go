@@ -0,0 +1,88 @@
+package loadbalancer
+
+import "sort"
+
+// aviServiceTypes - service types supported by Avi indexed by api name.
+// UUIDs are filled in from the application profiles on the controller.
+func aviServiceTypes() map[string]Service {
+	r := make(map[string]Service)
+	r["https"] = Service{Name: "System-Secure-HTTP", UUID: "", APIName: "https", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["syslog"] = Service{Name: "System-Syslog", UUID: "", APIName: "syslog", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["https-no-secure-cookies"] = Service{Name: "System-Secure-HTTP-No-Secure-Cookies", UUID: "", APIName: "https-no-secure-cookies", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["http"] = Service{Name: "System-HTTP", UUID: "", APIName: "http", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["http-multiplex-disabled"] = Service{Name: "System-HTTP-Multiplex-Disabled", UUID: "", APIName: "http-multiplex-disabled", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["ssl-l4-app"] = Service{Name: "System-SSL-Application", UUID: "", APIName: "ssl-l4-app", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["ssl-vdi"] = Service{Name: "System-Secure-HTTP-VDI", UUID: "", APIName: "ssl-vdi", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["l4-app"] = Service{Name: "System-L4-Application", UUID: "", APIName: "l4-app", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["l4-app-udp"] = Service{Name: "System-L4-Application-UDP", UUID: "", APIName: "l4-app-udp", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["ssl-bridge"] = Service{Name: "System-SSL-Bridge", UUID: "", APIName: "ssl-bridge", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	r["dns"] = Service{Name: "System-DNS", UUID: "", APIName: "dns", SupportedNetworkProfiles: make(map[string]NetworkProfile)}
+	return r
+}
+
+// aviNetworkProfiles - network profiles supported by Avi indexed by api name.
+func aviNetworkProfiles() map[string]NetworkProfile {
+	r := make(map[string]NetworkProfile)
+	r["tcp"] = NetworkProfile{Name: "System-TCP-Proxy", UUID: "", APIName: "tcp"}
+	r["udp"] = NetworkProfile{Name: "System-UDP-Fast-Path", UUID: "", APIName: "udp"}
+	r["udp-fast-path-vdi"] = NetworkProfile{Name: "System-UDP-Fast-Path-VDI", UUID: "", APIName: "udp-fast-path-vdi"}
+	r["udp-per-pkt"] = NetworkProfile{Name: "System-UDP-Per-Pkt", UUID: "", APIName: "udp-per-pkt"}
+	r["udp-no-snat"] = NetworkProfile{Name: "System-UDP-No-SNAT", UUID: "", APIName: "udp-no-snat"}
+	return r
+}
+
+// netscalerServiceTypes - frontend protocols supported by Netscaler indexed
+// by api name.
+func netscalerServiceTypes() map[string]Service {
+	r := make(map[string]Service)
+	r["https"] = Service{Name: "SSL", UUID: "SSL", APIName: "https"}
+	r["http"] = Service{Name: "HTTP", UUID: "HTTP", APIName: "http"}
+	r["l4-app-tcp"] = Service{Name: "TCP", UUID: "TCP", APIName: "l4-app-tcp"}
+	r["l4-app"] = Service{Name: "TCP", UUID: "TCP", APIName: "l4-app"}
+	r["dns"] = Service{Name: "DNS_TCP", UUID: "DNS_TCP", APIName: "dns"}
+	r["l4-app-udp"] = Service{Name: "UDP", UUID: "UDP", APIName: "l4-app-udp"}
+	r["ssl-bridge"] = Service{Name: "SSL_BRIDGE", UUID: "SSL_BRIDGE", APIName: "ssl-bridge"}
+	return r
+}
+
+// SupportedServiceTypes - service types supported by mfr. The types of every
+// platform are returned when mfr is empty.
+func SupportedServiceTypes(mfr string) []string {
+	keys := make(map[string]bool)
+	if mfr == "" || mfr == "avi networks" {
+		for k := range aviServiceTypes() {
+			keys[k] = true
+		}
+	}
+	if mfr == "" || mfr == "netscaler" {
+		for k := range netscalerServiceTypes() {
+			keys[k] = true
+		}
+	}
+	return sortedKeys(keys)
+}
+
+// SupportedNetworkProfiles - l4 profiles supported by mfr. Netscaler derives
+// the protocol from the service type, so only tcp and udp apply. The profiles
+// of every platform are returned when mfr is empty.
+func SupportedNetworkProfiles(mfr string) []string {
+	keys := make(map[string]bool)
+	if mfr == "" || mfr == "avi networks" {
+		for k := range aviNetworkProfiles() {
+			keys[k] = true
+		}
+	}
+	if mfr == "" || mfr == "netscaler" {
+		keys["tcp"] = true
+		keys["udp"] = true
+	}
+	return sortedKeys(keys)
+}
+
+func sortedKeys(m map[string]bool) (r []string) {
+	for k := range m {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"crypto/rand"
 	"crypto/tls"
 	"flag"
 	"net/http"
 
 	"github.com/gin-contrib/cors"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
@@ -16,6 +19,7 @@ import (
 	"github.com/ticketmaster/lbapi/golog"
 	"github.com/ticketmaster/lbapi/handler"
 	"github.com/ticketmaster/lbapi/routeconfig"
+	"github.com/ticketmaster/lbapi/userenv"
 )
 
 func main() {
@@ -30,6 +34,7 @@ func main() {
 		log.Fatal(err)
 	}
 	log.Println("Load Balancer Sources enumerated.")
+	common.StartPurge()
 	////////////////////////////////////////////////////////////////////////////
 	options := filter.NewAuthenticationOptions()
 	options.ConfigPath = "etc"
@@ -46,10 +51,23 @@ func main() {
 	corsConfig.AllowHeaders = []string{"Authorization", "Content-Type", "Cache-Control", "Pragma"}
 	router.Use(cors.New(corsConfig))
 	////////////////////////////////////////////////////////////////////////////
-	err = filter.UseAuthentication(router, options)
+	// UseAuthentication is given a scratch router so it only loads its
+	// settings. Its middleware is registered here so that API tokens of
+	// service accounts skip the session and JWT login, with a session store
+	// signed by Lbm.SessionSecret.
+	////////////////////////////////////////////////////////////////////////////
+	err = filter.UseAuthentication(gin.New(), options)
 	if err != nil {
 		log.Fatal(err)
 	}
+	store, err := sessionStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	userenv.LookupToken = common.LookupToken
+	router.Use(sessions.Sessions("lbapi-session", store))
+	router.Use(userenv.Authenticate(filter.Authentication()))
+	router.POST("/login", filter.Login)
 	////////////////////////////////////////////////////////////////////////////
 	router.Use(golog.Logger(log))
 	router.HEAD("/", func(c *gin.Context) {})
@@ -77,6 +95,31 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	op := routeconfig.NewOperation()
+	_, err = handler.New(op, v1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	a := routeconfig.NewAudit()
+	_, err = handler.New(a, v1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sc := routeconfig.NewSchedule()
+	_, err = handler.New(sc, v1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sc.Start()
+	ap := routeconfig.NewApproval()
+	_, err = handler.New(ap, v1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handler.Events(v1)
+	handler.Purge(v1)
+	handler.ServiceAccounts(v1)
+	handler.OpenAPI(v1)
 	////////////////////////////////////////////////////////////////////////////
 	if config.GlobalConfig.Lbm.RunTLS {
 		server := http.Server{
@@ -104,3 +147,18 @@ func main() {
 		log.Fatal(server.ListenAndServe())
 	}
 }
+
+// sessionStore - returns the cookie store of the login sessions, signed with
+// Lbm.SessionSecret or, when it is not set, a random key.
+func sessionStore() (r cookie.Store, err error) {
+	secret := []byte(config.GlobalConfig.Lbm.SessionSecret)
+	if len(secret) == 0 {
+		logrus.Warn("Lbm.SessionSecret is not set - login sessions end when the service restarts")
+		secret = make([]byte, 32)
+		_, err = rand.Read(secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cookie.NewStore(secret), nil
+}
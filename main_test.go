@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/config"
+)
+
+// sessionRouter - router that writes the roles query param to the session
+// and returns the roles held in it.
+func sessionRouter(t *testing.T) *gin.Engine {
+	store, err := sessionStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.Use(sessions.Sessions("lbapi", store))
+	router.GET("/", func(c *gin.Context) {
+		s := sessions.Default(c)
+		if roles := c.Query("roles"); roles != "" {
+			s.Set("roles", roles)
+			s.Save()
+		}
+		roles, _ := s.Get("roles").(string)
+		c.String(http.StatusOK, roles)
+	})
+	return router
+}
+
+func TestSessionStore(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		secret string
+		kept   bool
+	}{
+		{"configured secret survives a restart", "0123456789abcdef0123456789abcdef", true},
+		{"generated secret does not", "", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Lbm: config.Lbm{SessionSecret: tt.secret}}
+			w := httptest.NewRecorder()
+			sessionRouter(t).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?roles=admin", nil))
+			////////////////////////////////////////////////////////////////////
+			// A second store stands in for the restarted service.
+			////////////////////////////////////////////////////////////////////
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, v := range w.Result().Cookies() {
+				req.AddCookie(v)
+			}
+			w = httptest.NewRecorder()
+			sessionRouter(t).ServeHTTP(w, req)
+			if kept := w.Body.String() == "admin"; kept != tt.kept {
+				t.Fatalf("expected session kept %v, got %q", tt.kept, w.Body.String())
+			}
+		})
+	}
+}
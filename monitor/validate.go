@@ -0,0 +1,27 @@
+package monitor
+
+import "github.com/ticketmaster/lbapi/shared"
+
+// aviTypes - types matched by the Avi etlCreate switch.
+var aviTypes = []string{"http", "http-ecv", "https", "https-ecv", "tcp", "tcp-ecv", "udp", "udp-ecv", "ping", "external"}
+
+// netscalerTypes - types matched by the Netscaler etlCreate switch.
+var netscalerTypes = []string{"http", "http-ecv", "https", "https-ecv", "tcp", "tcp-ecv", "udp", "udp-ecv"}
+
+// SupportedTypes - monitor types supported by mfr. Avi types are returned
+// when mfr is empty since they are a superset of the Netscaler types.
+func SupportedTypes(mfr string) []string {
+	if mfr == "netscaler" {
+		return netscalerTypes
+	}
+	return aviTypes
+}
+
+// Validate - checks the values of data against the options supported by
+// mfr. Paths are reported relative to path.
+func Validate(data *Data, mfr string, path string) (r []shared.FieldError) {
+	r = append(r, shared.CheckRequired(shared.JSONPath(path, "type"), data.Type != "")...)
+	r = append(r, shared.CheckEnum(shared.JSONPath(path, "type"), data.Type, SupportedTypes(mfr))...)
+	r = append(r, shared.CheckRange(shared.JSONPath(path, "monitor_port"), data.MonitorPort, 0, 65535)...)
+	return
+}
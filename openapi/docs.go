@@ -0,0 +1,371 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package openapi
+
+var docs = map[string]doc{
+	"certificate.AviCertificate":                        {Description: "Struct for manipulating Avi Certificates."},
+	"certificate.AviCertificate.Certificate":            {Description: "resource configuration\"."},
+	"certificate.AviCertificate.Key":                    {Description: "Required on creation. PEM formatted private key."},
+	"certificate.AviCertificate.KeyPassphrase":          {Description: "secret for decrypting key."},
+	"certificate.AviCertificate.Name":                   {Description: "friendly name of the resource. Typically inherited from parent."},
+	"certificate.AviCertificate.TenantRef":              {Description: "Avi URL for tenant."},
+	"certificate.Data":                                  {Description: "resource configuration."},
+	"certificate.Data.Certificate":                      {Description: "PEM formated certificate. Replace line breaks with \"\\n\"."},
+	"certificate.Data.Key":                              {Description: "resource configuration."},
+	"certificate.Data.Name":                             {Description: "friendly name of the resource. Typically inherited from parent."},
+	"certificate.Data.SourceCommonName":                 {Description: "common name of certificate.", ReadOnly: true},
+	"certificate.Data.SourceDistinguishedName":          {Description: "distinguished name of certificate.", ReadOnly: true},
+	"certificate.Data.SourceExpiry":                     {Description: "date the cert expires.", ReadOnly: true},
+	"certificate.Data.SourceSelfSigned":                 {Description: "true if certificate is self-signed.", ReadOnly: true},
+	"certificate.Data.SourceSerialNumber":               {Description: "serial number of the certificate.", ReadOnly: true},
+	"certificate.Data.SourceSignatureAlgorithm":         {Description: "signature algorithm used to sign certificate.", ReadOnly: true},
+	"certificate.Data.SourceUUID":                       {Description: "record id of the resource.", ReadOnly: true},
+	"certificate.Key":                                   {Description: "resource configuration."},
+	"certificate.Key.PassPhrase":                        {Description: "secret for decrypting key."},
+	"certificate.Key.PrivateKey":                        {Description: "Required on creation. PEM formatted private key. Replace line breaks with \"\\n\"."},
+	"certificate.Key.SourceAlgorithm":                   {Description: "algorithm used to encrypt certificate.", ReadOnly: true},
+	"certificate.Key.SourceECCurve":                     {Description: "eccurve used to encrypt certificate. Applicable to ECC certs.", ReadOnly: true},
+	"certificate.Key.SourceRSASize":                     {Description: "size of RSA key. Applicable to RSA certs.", ReadOnly: true},
+	"certificate.PublicKey":                             {Description: "resource configuration."},
+	"certificate.PublicKey.Certificate":                 {Description: "PEM formated certificate."},
+	"common.APIToken":                                   {Description: "long-lived token of a service account, scoped to product codes and permissions. Stored in the apitokens table; only a hash of the secret is kept."},
+	"common.APIToken.Created":                           {Description: "time the token was issued."},
+	"common.APIToken.CreatedBy":                         {Description: "user that issued the token."},
+	"common.APIToken.Description":                       {Description: "what the token is used for."},
+	"common.APIToken.ExpiresAt":                         {Description: "RFC 3339 time the token stops working. Tokens without one work until revoked."},
+	"common.APIToken.ID":                                {Description: "id of the token. It is part of the token and is safe to log."},
+	"common.APIToken.LastUsed":                          {Description: "time the token was last used."},
+	"common.APIToken.Permissions":                       {Description: "actions the token may take on its product codes: fetch, create, modify, delete, migrate or import."},
+	"common.APIToken.ProductCodes":                      {Description: "product codes the token may act on."},
+	"common.APIToken.Revoked":                           {Description: "time the token was revoked."},
+	"common.APIToken.RevokedBy":                         {Description: "user that revoked the token."},
+	"common.APIToken.ServiceAccount":                    {Description: "name of the account the token was issued to."},
+	"common.AuditChange":                                {Description: "single field of an audit diff."},
+	"common.AuditChange.From":                           {Description: "value before the request."},
+	"common.AuditChange.Op":                             {Description: "add, remove or replace.", Enum: []string{"add", "remove", "replace"}},
+	"common.AuditChange.Path":                           {Description: "path of the field, e.g. pools[0].bindings[1].server.ip."},
+	"common.AuditChange.To":                             {Description: "value after the request."},
+	"common.AuditEntry":                                 {Description: "data of an audit record. Audit records are written once for every create, modify, delete, migrate, import and backup request and are never updated."},
+	"common.AuditEntry.Action":                          {Description: "create, modify, delete, migrate, import, backup, purge, request_approval, approve or reject."},
+	"common.AuditEntry.After":                           {Description: "data of the record returned by the request. Private keys are redacted."},
+	"common.AuditEntry.Before":                          {Description: "data of the record before the request. Private keys are redacted."},
+	"common.AuditEntry.Count":                           {Description: "number of records imported, backed up or purged."},
+	"common.AuditEntry.Diff":                            {Description: "fields that differ between before and after."},
+	"common.AuditEntry.LastError":                       {Description: "error returned by the request."},
+	"common.AuditEntry.ProductCode":                     {Description: "product code of the record."},
+	"common.AuditEntry.RecordID":                        {Description: "id of the record. Empty for import and backup."},
+	"common.AuditEntry.Route":                           {Description: "route the request was made against."},
+	"common.AuditEntry.SourceIP":                        {Description: "address the request came from."},
+	"common.AuditEntry.User":                            {Description: "user that made the request."},
+	"common.BindingChange":                              {Description: "pool member enabled or disabled by a binding change."},
+	"common.BindingChange.IP":                           {Description: "ip of the pool member."},
+	"common.BindingChange.Pool":                         {Description: "name of the pool."},
+	"common.BindingChange.State":                        {Description: "binding state to apply."},
+	"common.BulkRecord":                                 {Description: "response of the bulk routes."},
+	"common.BulkRecord.LastError":                       {Description: "reason the request was aborted."},
+	"common.BulkRecord.Results":                         {Description: "outcome of every item, in request order."},
+	"common.BulkResult":                                 {Description: "outcome of one item of a bulk request."},
+	"common.BulkResult.ApprovalID":                      {Description: "change request holding the item until a second user approves it."},
+	"common.BulkResult.Errors":                          {Description: "invalid fields of the item."},
+	"common.BulkResult.ID":                              {Description: "id of the record."},
+	"common.BulkResult.LastError":                       {Description: "error returned for the item."},
+	"common.BulkResult.OperationID":                     {Description: "operation tracking the item when it completes in the background."},
+	"common.BulkResult.Status":                          {Description: "running, complete, fail, skipped or pending.", Enum: []string{"running", "complete", "fail", "skipped", "pending"}},
+	"common.ChangeRequest":                              {Description: "create, modify, delete, migrate or binding change of a record with a protected product code, waiting for the approval of a second user. Change requests are stored in the approvals table."},
+	"common.ChangeRequest.Action":                       {Description: "create, modify, delete, migrate or binding.", Enum: []string{"create", "modify", "delete", "migrate", "binding"}},
+	"common.ChangeRequest.ApplyAt":                      {Description: "time the change is scheduled for once approved."},
+	"common.ChangeRequest.Binding":                      {Description: "pool member to enable or disable for a binding change."},
+	"common.ChangeRequest.ETag":                         {Description: "entity tag of the record when the change was requested. The change fails if the record has been modified since."},
+	"common.ChangeRequest.Groups":                       {Description: "groups of the user when the change was requested."},
+	"common.ChangeRequest.LastError":                    {Description: "error that failed the release of the change."},
+	"common.ChangeRequest.OperationID":                  {Description: "operation tracking the load balancer work once approved."},
+	"common.ChangeRequest.Payload":                      {Description: "create or modify request. Private keys are redacted and restored from secure storage when the change is released."},
+	"common.ChangeRequest.ProductCodes":                 {Description: "protected product codes the change touches. The approver needs rights to each."},
+	"common.ChangeRequest.Reason":                       {Description: "reason given for a rejection."},
+	"common.ChangeRequest.RecordID":                     {Description: "id of the record being changed. Empty for create."},
+	"common.ChangeRequest.Result":                       {Description: "record returned when the change was released. Private keys are redacted."},
+	"common.ChangeRequest.Reviewed":                     {Description: "time the change was approved or rejected."},
+	"common.ChangeRequest.ReviewedBy":                   {Description: "user that approved or rejected the change."},
+	"common.ChangeRequest.Route":                        {Description: "route the change is applied through."},
+	"common.ChangeRequest.Status":                       {Description: "pending, approved, rejected or fail.", Enum: []string{"pending", "approved", "rejected", "fail"}},
+	"common.ChangeRequest.Submitted":                    {Description: "time the change was requested."},
+	"common.ChangeRequest.Token":                        {Description: "scope of the API token the change was requested with."},
+	"common.ChangeRequest.User":                         {Description: "user that requested the change. The change is applied with the user's rights."},
+	"common.ChangeRequest.Window":                       {Description: "maintenance window the change is scheduled into once approved."},
+	"common.Cluster":                                    {Description: "resource configuration."},
+	"common.Data":                                       {Description: "resource configuration."},
+	"common.DbRecord":                                   {Description: "fields associated with the default response."},
+	"common.DbRecord.ApprovalID":                        {Description: "change request waiting for approval instead of the request being applied.", ReadOnly: true},
+	"common.DbRecord.Data":                              {Description: "resource configuration. The schema depends on the route."},
+	"common.DbRecord.ID":                                {Description: "id of the record. Leave empty on create."},
+	"common.DbRecord.LastError":                         {Description: "error returned by the last request.", ReadOnly: true},
+	"common.DbRecord.LastModified":                      {Description: "time the record was last written.", ReadOnly: true},
+	"common.DbRecord.LastModifiedBy":                    {Description: "user that last wrote the record.", ReadOnly: true},
+	"common.DbRecord.LoadBalancer":                      {Description: "cluster the record lives on.", ReadOnly: true},
+	"common.DbRecord.LoadBalancerIP":                    {Description: "cluster ip of the target load balancer."},
+	"common.DbRecord.Md5Hash":                           {Description: "hash of the stored record. Returned as the ETag.", ReadOnly: true},
+	"common.DbRecord.OperationID":                       {Description: "operation tracking the request.", ReadOnly: true},
+	"common.DbRecord.Platform":                          {Description: "avi networks or netscaler."},
+	"common.DbRecord.SQLMessage":                        {Description: "sql summary.", ReadOnly: true},
+	"common.DbRecord.Source":                            {Description: "load balancer the record was read from.", ReadOnly: true},
+	"common.DbRecord.Status":                            {Description: "status of the record.", ReadOnly: true},
+	"common.DbRecord.StatusID":                          {Description: "status code of the record.", ReadOnly: true},
+	"common.DbRecordCollection":                         {Description: "default response from the API."},
+	"common.DbRecordResponse":                           {Description: "response from database."},
+	"common.DbResponseRecord":                           {Description: "database response."},
+	"common.ErrorRecord":                                {Description: "response returned when a payload fails validation."},
+	"common.ErrorRecord.Errors":                         {Description: "invalid fields of the payload."},
+	"common.ErrorRecord.LastError":                      {Description: "summary of every invalid field."},
+	"common.IssuedToken":                                {Description: "response of IssueToken. Token is only ever returned here."},
+	"common.IssuedToken.Token":                          {Description: "bearer token to authenticate with."},
+	"common.LBData":                                     {Description: "resource configuration."},
+	"common.LBRecordCollection":                         {Description: "resource collection."},
+	"common.MaintenanceRecord":                          {Description: "fleet wide binding state change response."},
+	"common.MaintenanceTarget":                          {Description: "virtual server changed by a maintenance request."},
+	"common.Operation":                                  {Description: "tracks the progress of an asynchronous create, modify, delete or migrate request. Operations are stored in the operations table and updated as each step starts and finishes."},
+	"common.Operation.Action":                           {Description: "create, modify, delete, migrate, binding enable, binding disable, maintenance enable or maintenance disable."},
+	"common.Operation.Finished":                         {Description: "time the operation finished."},
+	"common.Operation.ID":                               {Description: "operation id."},
+	"common.Operation.LastError":                        {Description: "error that failed the operation."},
+	"common.Operation.LoadBalancerIP":                   {Description: "cluster ip of the target load balancer."},
+	"common.Operation.ProductCode":                      {Description: "product code associated with the record."},
+	"common.Operation.RecordID":                         {Description: "id of the record being changed."},
+	"common.Operation.Result":                           {Description: "resulting record once the operation has finished."},
+	"common.Operation.Route":                            {Description: "route the request was made against."},
+	"common.Operation.Started":                          {Description: "time the operation was submitted."},
+	"common.Operation.Status":                           {Description: "running, complete or fail.", Enum: []string{"running", "complete", "fail"}},
+	"common.Operation.Steps":                            {Description: "steps in the order they were started."},
+	"common.Operation.User":                             {Description: "user that submitted the request."},
+	"common.OperationStep":                              {Description: "single step of an operation."},
+	"common.OperationStep.Error":                        {Description: "error returned by the step."},
+	"common.OperationStep.Finished":                     {Description: "time the step finished."},
+	"common.OperationStep.Name":                         {Description: "ip allocation, dns, pool create, monitor create, vs create, db write etc."},
+	"common.OperationStep.Started":                      {Description: "time the step started."},
+	"common.OperationStep.Status":                       {Description: "running, complete or fail.", Enum: []string{"running", "complete", "fail"}},
+	"common.PlanRecord":                                 {Description: "dry run response. Describes the changes a modify would apply."},
+	"common.PurgeRecord":                                {Description: "response of the purge route."},
+	"common.PurgeRecord.DryRun":                         {Description: "true when nothing was removed."},
+	"common.PurgeRecord.LastError":                      {Description: "error returned by the request."},
+	"common.PurgeRecord.Tables":                         {Description: "outcome for each table with a retention window."},
+	"common.PurgeTable":                                 {Description: "outcome of a purge for one table."},
+	"common.PurgeTable.Archive":                         {Description: "file the purged records were written to."},
+	"common.PurgeTable.Before":                          {Description: "cut off of the retention window."},
+	"common.PurgeTable.Count":                           {Description: "number of records purged, or that would be purged."},
+	"common.PurgeTable.LastError":                       {Description: "error that stopped the purge of this table."},
+	"common.PurgeTable.RetentionDays":                   {Description: "records last modified before this many days ago are purged."},
+	"common.PurgeTable.Table":                           {Description: "recycle, status or migrate.", Enum: []string{"recycle", "status", "migrate"}},
+	"common.SQLMessage":                                 {Description: "sql summary response."},
+	"common.ScheduledChange":                            {Description: "modify or delete persisted to be applied later. Scheduled changes are stored in the schedule table."},
+	"common.ScheduledChange.Action":                     {Description: "modify or delete.", Enum: []string{"modify", "delete"}},
+	"common.ScheduledChange.ApplyAt":                    {Description: "time the change is applied."},
+	"common.ScheduledChange.ApprovedBy":                 {Description: "user that approved the change when the record's product code is protected."},
+	"common.ScheduledChange.CancelledBy":                {Description: "user that cancelled the change."},
+	"common.ScheduledChange.Deadline":                   {Description: "end of the window. A change not started by then fails."},
+	"common.ScheduledChange.ETag":                       {Description: "entity tag of the record when the change was scheduled. The change fails if the record has been modified since."},
+	"common.ScheduledChange.Finished":                   {Description: "time the change finished."},
+	"common.ScheduledChange.Groups":                     {Description: "groups of the user when the change was scheduled."},
+	"common.ScheduledChange.LastError":                  {Description: "error that failed the change."},
+	"common.ScheduledChange.OperationID":                {Description: "operation tracking the load balancer work."},
+	"common.ScheduledChange.Payload":                    {Description: "modify request. Private keys are redacted and restored from secure storage when the change is applied."},
+	"common.ScheduledChange.ProductCode":                {Description: "product code of the record."},
+	"common.ScheduledChange.RecordID":                   {Description: "id of the record being changed."},
+	"common.ScheduledChange.Result":                     {Description: "record returned once the change was applied. Private keys are redacted."},
+	"common.ScheduledChange.Route":                      {Description: "route the change is applied through."},
+	"common.ScheduledChange.Status":                     {Description: "pending, running, complete, fail or cancelled.", Enum: []string{"pending", "running", "complete", "fail", "cancelled"}},
+	"common.ScheduledChange.Submitted":                  {Description: "time the change was scheduled."},
+	"common.ScheduledChange.Token":                      {Description: "scope of the API token the change was scheduled with."},
+	"common.ScheduledChange.User":                       {Description: "user that scheduled the change. The change is applied with the user's rights."},
+	"common.ScheduledChange.Window":                     {Description: "maintenance window the change was scheduled into."},
+	"common.ServiceAccount":                             {Description: "non-human user, e.g. a CI pipeline, that API tokens are issued to. Stored in the serviceaccounts table."},
+	"common.ServiceAccount.Created":                     {Description: "time the account was created."},
+	"common.ServiceAccount.CreatedBy":                   {Description: "user that created the account."},
+	"common.ServiceAccount.Description":                 {Description: "what the account is used for."},
+	"common.ServiceAccount.Name":                        {Description: "unique name of the account."},
+	"common.ServiceAccount.Owners":                      {Description: "users that may issue, list and revoke the account's tokens. The user creating the account is always an owner."},
+	"common.Version":                                    {Description: "document of a record accepted by a create, modify or migrate request, taken from the audit log."},
+	"common.Version.Action":                             {Description: "create, modify or migrate.", Enum: []string{"create", "modify", "migrate"}},
+	"common.Version.AuditID":                            {Description: "id of the audit record the version was taken from."},
+	"common.Version.Data":                               {Description: "resource configuration. Private keys are redacted."},
+	"common.Version.LastModified":                       {Description: "time the version was submitted."},
+	"common.Version.User":                               {Description: "user that submitted the version."},
+	"common.Version.Version":                            {Description: "number of the version. 1 is the oldest."},
+	"common.VersionRecord":                              {Description: "response of the versions route."},
+	"common.VersionRecord.ID":                           {Description: "id of the record."},
+	"common.VersionRecord.LastError":                    {Description: "error returned by the request."},
+	"common.VersionRecord.Versions":                     {Description: "every version of the record, oldest first."},
+	"common.VsDbRecord":                                 {Description: "fields associated with the default response."},
+	"common.VsDbRecordCollection":                       {Description: "default response from the API."},
+	"common.VsDbResponseRecord":                         {Description: "database response."},
+	"loadbalancer.Data":                                 {Description: "resource configuration."},
+	"loadbalancer.Data.ClusterDNS":                      {Description: "reverse DNS lookup of cluster ip."},
+	"loadbalancer.Data.ClusterIP":                       {Description: "ip used to manage cluster wide resources. For Netscaler, this would be the first MIP or SNIP configured for management. For Avi, this is the control cluster ip."},
+	"loadbalancer.Data.ClusterUUID":                     {Description: "unique id of cluster. Avi only."},
+	"loadbalancer.Data.DNS":                             {Description: "reverse DNS lookup of server ip."},
+	"loadbalancer.Data.DeviceID":                        {Description: "unique identifier for licensing. Netscaler only."},
+	"loadbalancer.Data.Firmware":                        {Description: "firmware running on load balancer. Firmware - firmware running on load balancer."},
+	"loadbalancer.Data.HAMembers":                       {Description: "all members of the HA cluster. For Avi, this only relates to the control plane."},
+	"loadbalancer.Data.IPAddresses":                     {Description: "list of all the ip addresses mapped to the unit. Netscaler only."},
+	"loadbalancer.Data.Interfaces":                      {Description: "list of all the interfaces configured on the unit. Netscaler only."},
+	"loadbalancer.Data.Mfr":                             {Description: "manufacturer. Either avi or netscaler."},
+	"loadbalancer.Data.Model":                           {Description: "model number of appliance. Netscaler only."},
+	"loadbalancer.Data.ProductCode":                     {Description: "product code associated with record."},
+	"loadbalancer.Data.Routes":                          {Description: "list of all the network routes in CIDR format."},
+	"loadbalancer.Data.Serial":                          {Description: "serial number of appliance. Netscaler only."},
+	"loadbalancer.Data.Status":                          {Description: "system status."},
+	"loadbalancer.Data.VRFContexts":                     {Description: "list of all the vrfcontexts configured on the unit. Avi only."},
+	"loadbalancer.HAMember":                             {Description: "ha member configuration."},
+	"loadbalancer.HAMember.IP":                          {Description: "IPV4 of ha member."},
+	"loadbalancer.HAMember.Role":                        {Description: "either active or standby.", Enum: []string{"active", "standby"}},
+	"loadbalancer.HAMember.Status":                      {Description: "status as it pertains to the cluster."},
+	"loadbalancer.IPAddress":                            {Description: "ip address configuration."},
+	"loadbalancer.IPAddress.CIDR":                       {Description: "network cidr."},
+	"loadbalancer.IPAddress.DNS":                        {Description: "reverse dns lookup of ip."},
+	"loadbalancer.IPAddress.Enabled":                    {Description: "state of interface."},
+	"loadbalancer.IPAddress.IP":                         {Description: "IPV4/IPV6 of object."},
+	"loadbalancer.IPAddress.Netmask":                    {Description: "netmask for ip."},
+	"loadbalancer.IPAddress.Type":                       {Description: "address type as reported by Netscaler, e.g. SNIP, VIP or NSIP."},
+	"loadbalancer.Interface":                            {Description: "interface configuration."},
+	"loadbalancer.Interface.Enabled":                    {Description: "state of interface."},
+	"loadbalancer.Interface.ID":                         {Description: "id of interface."},
+	"loadbalancer.Interface.Lacpmode":                   {Description: "lacp mode of interface."},
+	"loadbalancer.Interface.MAC":                        {Description: "mac address of interface."},
+	"loadbalancer.NetworkProfile":                       {Description: "part of collection."},
+	"loadbalancer.NetworkProfile.APIName":               {Description: "name of resource in API."},
+	"loadbalancer.NetworkProfile.Name":                  {Description: "name of resource on the load balancer."},
+	"loadbalancer.NetworkProfile.UUID":                  {Description: "uuid of resource on load balancer."},
+	"loadbalancer.Route":                                {Description: "route configuration."},
+	"loadbalancer.Runtime":                              {Description: "runtime configuration."},
+	"loadbalancer.SSLProfile":                           {Description: "part of collection."},
+	"loadbalancer.SSLProfile.Name":                      {Description: "name of resource on the load balancer."},
+	"loadbalancer.SSLProfile.UUID":                      {Description: "uuid of resource on the load balancer."},
+	"loadbalancer.Service":                              {Description: "part of collection."},
+	"loadbalancer.VrfContext":                           {Description: "part of collection."},
+	"loadbalancer.VrfContext.CloudRef":                  {Description: "cloud ref of object."},
+	"loadbalancer.VrfContext.Name":                      {Description: "name of resource on the load balancer."},
+	"loadbalancer.VrfContext.Routes":                    {Description: "list of all routes associated to VrfContext."},
+	"loadbalancer.VrfContext.TenantRef":                 {Description: "UUID of tenant."},
+	"loadbalancer.VrfContext.UUID":                      {Description: "UUID of object."},
+	"loadbalancer.VsVip":                                {Description: "part of collection."},
+	"loadbalancer.VsVip.IP":                             {Description: "IP address."},
+	"loadbalancer.VsVip.UUID":                           {Description: "uuid of resource on load balancer."},
+	"monitor.Data":                                      {Description: "resource configuration."},
+	"monitor.Data.Description":                          {Description: "provides additional details for the resource."},
+	"monitor.Data.FailedCount":                          {Description: "number of times the test must fail before the resource is marked down."},
+	"monitor.Data.MaintenanceResponse":                  {Description: "is an Avi only field. This will mark the resource as down due to maintenance if response data match."},
+	"monitor.Data.MaintenanceResponseCodes":             {Description: "is an Avi only field. This will mark the resource as down due to maintenance if response codes match."},
+	"monitor.Data.MonitorPort":                          {Description: "defaults to same port of pool."},
+	"monitor.Data.Name":                                 {Description: "friendly name of the resource. Typically inherited from parent."},
+	"monitor.Data.ReceiveTimeout":                       {Description: "number of seconds before the health check times out."},
+	"monitor.Data.Request":                              {Description: "data the monitor will send."},
+	"monitor.Data.Response":                             {Description: "expected response."},
+	"monitor.Data.ResponseCodes":                        {Description: "expected response codes."},
+	"monitor.Data.SendInterval":                         {Description: "number of seconds between health checks."},
+	"monitor.Data.SourceUUID":                           {Description: "record id of the resource.", ReadOnly: true},
+	"monitor.Data.SuccessfulCount":                      {Description: "number of times a positive response must be received to mark the resource healthy."},
+	"monitor.Data.Type":                                 {Description: "options include: http, http-ecv, https, https-ecv, tcp, tcp-ecv, ping, udp, and external.", Enum: []string{"http", "http-ecv", "https", "https-ecv", "tcp", "tcp-ecv", "ping", "udp", "external"}},
+	"persistence.Data":                                  {Description: "resource configuration."},
+	"persistence.Data.Description":                      {Description: "provides additional details for the resource."},
+	"persistence.Data.Name":                             {Description: "friendly name of the resource. Typically inherited from parent."},
+	"persistence.Data.ObjName":                          {Description: "name of persistence setting resource. This is either the name of a cookie, or header."},
+	"persistence.Data.SourceUUID":                       {Description: "record id of the resource.", ReadOnly: true},
+	"persistence.Data.Timeout":                          {Description: "number of seconds before the session times out. 0 means no timeout."},
+	"persistence.Data.Type":                             {Description: "types include client-ip, http-cookie, custom-http-header, app-cookie and tls.", Enum: []string{"client-ip", "http-cookie", "custom-http-header", "app-cookie", "tls"}},
+	"pool.Data":                                         {Description: "resource configuration."},
+	"pool.Data.Bindings":                                {Description: "list of all backend servers."},
+	"pool.Data.Certificate":                             {Description: "certificate object. Only supported by Avi. This will allow key authentication between the VIP and backend servers."},
+	"pool.Data.DefaultPort":                             {Description: "this port will be mapped to all bindings."},
+	"pool.Data.DisableDelay":                            {Description: "time in seconds before pool is disabled."},
+	"pool.Data.Enabled":                                 {Description: "controls the state of the pool."},
+	"pool.Data.GracefulDisable":                         {Description: "waits for all sessions to complete before disabling the resource."},
+	"pool.Data.GracefulDisableTimeout":                  {Description: "only supported by Avi."},
+	"pool.Data.HealthMonitors":                          {Description: "list of all health monitors."},
+	"pool.Data.IsNsrService":                            {Description: "creates a service instead of a servicegroup. Only supported by Netscaler."},
+	"pool.Data.MaxClientConnections":                    {Description: "maximum number of client connections."},
+	"pool.Data.Name":                                    {Description: "friendly name of the resource. Typically inherited from parent."},
+	"pool.Data.Persistence":                             {Description: "persistence object."},
+	"pool.Data.Priority":                                {Description: "used only in pool group configurations. Only supported by Avi. Equal priority results in Round Robin. Pools with higher priority take take precedence, resulting in Active/Standby."},
+	"pool.Data.SSLEnabled":                              {Description: "enables ssl between pool members and the vip."},
+	"pool.Data.SourceLoadBalancingMethod":               {Description: "lb method inherited from virtual service.", ReadOnly: true},
+	"pool.Data.SourceServiceType":                       {Description: "service type inherited from virtual service.", ReadOnly: true},
+	"pool.Data.SourceStatus":                            {Description: "status of resource.", ReadOnly: true},
+	"pool.Data.SourceUUID":                              {Description: "uuid of resource.", ReadOnly: true},
+	"pool.Data.SourceVrfRef":                            {Description: "vrf reference from Avi.", ReadOnly: true},
+	"pool.Data.Weight":                                  {Description: "used only in pool group configurations. Only supported by Avi."},
+	"pool.MemberBinding":                                {Description: "backend server configuration."},
+	"pool.MemberBinding.DisableDelay":                   {Description: "time in seconds before pool is disabled"},
+	"pool.MemberBinding.Enabled":                        {Description: "controls whether or not the backend is enabled."},
+	"pool.MemberBinding.GracefulDisable":                {Description: "waits for all sessions to complete before disabling the resource."},
+	"pool.MemberBinding.Port":                           {Description: "service port on backend."},
+	"pool.MemberBinding.Server":                         {Description: "server object."},
+	"pool.Server":                                       {Description: "server configuration."},
+	"pool.Server.IP":                                    {Description: "IPV4 of server."},
+	"pool.Server.SourceDNS":                             {Description: "reverse DNS result of IP.", ReadOnly: true},
+	"pool.Server.SourceUUID":                            {Description: "uuid.", ReadOnly: true},
+	"shared.FieldError":                                 {Description: "invalid field in a payload."},
+	"shared.FieldError.Allowed":                         {Description: "accepted values when the field is limited to a set."},
+	"shared.FieldError.Path":                            {Description: "json path of the field, e.g. data.pools[0].bindings[1].port."},
+	"shared.FieldError.Reason":                          {Description: "why the value was rejected."},
+	"userenv.TokenScope":                                {Description: "service account and scope of a verified API token."},
+	"userenv.TokenScope.Permissions":                    {Description: "actions the token may take on its product codes."},
+	"userenv.TokenScope.ProductCodes":                   {Description: "product codes the token may act on."},
+	"userenv.TokenScope.ServiceAccount":                 {Description: "name of the service account the token was issued to."},
+	"userenv.TokenScope.TokenID":                        {Description: "id of the token."},
+	"virtualserver.BindingsPlan":                        {Description: "backend server changes."},
+	"virtualserver.CertificatesPlan":                    {Description: "certificate changes. Private keys are never returned."},
+	"virtualserver.DNSPlan":                             {Description: "dns changes."},
+	"virtualserver.Data":                                {Description: "resource configuration."},
+	"virtualserver.Data.Certificates":                   {Description: "certificate configuration. Only applies to Avi."},
+	"virtualserver.Data.DNS":                            {Description: "dns names associated with vip."},
+	"virtualserver.Data.Enabled":                        {Description: "enables the vip."},
+	"virtualserver.Data.IP":                             {Description: "ipv4 address."},
+	"virtualserver.Data.LoadBalancingMethod":            {Description: "roundrobin or leastconnection.", Enum: []string{"roundrobin", "leastconnection"}},
+	"virtualserver.Data.Name":                           {Description: "friendly name of the resource. System will preprend prdXXXX to the name if prd code isn't part of the name already."},
+	"virtualserver.Data.Pools":                          {Description: "pool configurations."},
+	"virtualserver.Data.Ports":                          {Description: "port configuration."},
+	"virtualserver.Data.ProductCode":                    {Description: "product code associated with record."},
+	"virtualserver.Data.ServiceType":                    {Description: "http, https, https-no-secure-cookies, http-multiplex-disabled, ssl-l4-app, l4-app, l4-app-udp and ssl-bridge.", Enum: []string{"http", "https", "https-no-secure-cookies", "http-multiplex-disabled", "ssl-l4-app", "l4-app", "l4-app-udp", "ssl-bridge"}},
+	"virtualserver.Data.SourceApplicationPolicy":        {Description: "application policy uuid on Avi.", ReadOnly: true},
+	"virtualserver.Data.SourceLast30":                   {Description: "last 30 day uptime for Netscaler.", ReadOnly: true},
+	"virtualserver.Data.SourceNetworkSecurityPolicyRef": {Description: "network security policy uuid on Avi.", ReadOnly: true},
+	"virtualserver.Data.SourceNsrBackupVip":             {Description: "name of backup on Netscaler.", ReadOnly: true},
+	"virtualserver.Data.SourcePoolGroupUUID":            {Description: "uuid of pool group. Only supported by Avi."},
+	"virtualserver.Data.SourceStatus":                   {Description: "status of resource. Only applies to Netscaler.", ReadOnly: true},
+	"virtualserver.Data.SourceUUID":                     {Description: "uuid of resource on load balancer.", ReadOnly: true},
+	"virtualserver.Data.SourceVrfRef":                   {Description: "virtual routing context uuid on Avi.", ReadOnly: true},
+	"virtualserver.FieldChange":                         {Description: "single field that differs between the request and the lb."},
+	"virtualserver.FieldChange.Field":                   {Description: "json name of the field."},
+	"virtualserver.FieldChange.From":                    {Description: "value on the load balancer."},
+	"virtualserver.FieldChange.To":                      {Description: "requested value."},
+	"virtualserver.MonitorsPlan":                        {Description: "health monitor changes."},
+	"virtualserver.Plan":                                {Description: "changes a modify request would apply to the load balancer. Plans are produced by dry runs and are never applied."},
+	"virtualserver.Plan.Certificates":                   {Description: "certificate changes. Only applies to Avi."},
+	"virtualserver.Plan.Changes":                        {Description: "top level fields that differ from the load balancer."},
+	"virtualserver.Plan.DNS":                            {Description: "dns changes. Only populated when infoblox is enabled."},
+	"virtualserver.Plan.Pools":                          {Description: "pool changes."},
+	"virtualserver.PoolPlan":                            {Description: "changes to an existing pool."},
+	"virtualserver.PoolPlan.Bindings":                   {Description: "backend server changes."},
+	"virtualserver.PoolPlan.Changes":                    {Description: "pool fields that differ from the load balancer."},
+	"virtualserver.PoolPlan.HealthMonitors":             {Description: "health monitor changes."},
+	"virtualserver.PoolPlan.Name":                       {Description: "name of the pool on the load balancer."},
+	"virtualserver.PoolPlan.SourceUUID":                 {Description: "uuid of the pool on the load balancer.", ReadOnly: true},
+	"virtualserver.PoolsPlan":                           {Description: "pool changes."},
+	"virtualserver.PoolsPlan.Added":                     {Description: "pools that will be created."},
+	"virtualserver.PoolsPlan.Removed":                   {Description: "pools that will be unbound and marked for deletion."},
+	"virtualserver.PoolsPlan.Updated":                   {Description: "pools that will be modified."},
+	"virtualserver.Port.L4Profile":                      {Description: "tcp, udp, udp-fast-path-vdi, or udp-per-pkt. Note that udp is only supported by ServiceType(s) with udp in their name.", Enum: []string{"tcp", "udp", "udp-fast-path-vdi", "udp-per-pkt"}},
+	"virtualserver.Port.Port":                           {Description: "service port."},
+	"virtualserver.Port.SSLEnabled":                     {Description: "enables ssl on the port."},
+	"virtualserver.PromHealthStatus":                    {Description: "Returned data from prometheus for health status."},
+	"webhook.Event":                                     {Description: "payload POSTed to every configured endpoint."},
+	"webhook.Event.Count":                               {Description: "number of records backed up or imported."},
+	"webhook.Event.ID":                                  {Description: "id of the event. Retries of a delivery share the id."},
+	"webhook.Event.LastError":                           {Description: "error recorded with the transition."},
+	"webhook.Event.NewStatus":                           {Description: "status after the transition."},
+	"webhook.Event.OldStatus":                           {Description: "status before the transition."},
+	"webhook.Event.ProductCode":                         {Description: "product code of the record."},
+	"webhook.Event.RecordID":                            {Description: "id of the record. Empty for backup and import events."},
+	"webhook.Event.Route":                               {Description: "route the record belongs to."},
+	"webhook.Event.Time":                                {Description: "time the event was raised."},
+	"webhook.Event.Type":                                {Description: "e.g. virtualserver.status_changed, virtualserver.recycled, virtualserver.backup_completed or virtualserver.import_completed."},
+	"webhook.Event.User":                                {Description: "user that made the request."},
+}
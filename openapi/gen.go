@@ -0,0 +1,208 @@
+//go:build ignore
+// +build ignore
+
+// gen.go - reads the doc comments of the model structs and writes docs.go.
+// Run with go generate from the openapi directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// packages - model packages described by the document.
+var packages = []string{
+	"certificate",
+	"common",
+	"loadbalancer",
+	"migrate",
+	"monitor",
+	"persistence",
+	"pool",
+	"shared",
+	"userenv",
+	"virtualserver",
+	"webhook",
+}
+
+type doc struct {
+	Description string
+	Enum        []string
+	ReadOnly    bool
+}
+
+var (
+	prefixRegex = regexp.MustCompile(`^([A-Z][A-Za-z0-9]*)\s*(\[([^\]]*)\])?\s*(-\s*)?`)
+	tokenRegex  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	listRegex   = regexp.MustCompile(`,\s*|\s+or\s+|\s+and\s+`)
+)
+
+func main() {
+	docs := make(map[string]doc)
+	for _, pkg := range packages {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, "../"+pkg, func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, p := range pkgs {
+			for _, f := range p.Files {
+				for _, decl := range f.Decls {
+					gd, ok := decl.(*ast.GenDecl)
+					if !ok || gd.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range gd.Specs {
+						ts := spec.(*ast.TypeSpec)
+						st, ok := ts.Type.(*ast.StructType)
+						if !ok || !ts.Name.IsExported() || !tagged(st) {
+							continue
+						}
+						key := pkg + "." + ts.Name.Name
+						cg := ts.Doc
+						if cg == nil {
+							cg = gd.Doc
+						}
+						if d := parse(ts.Name.Name, cg); d.Description != "" {
+							docs[key] = d
+						}
+						fields(docs, key, st)
+					}
+				}
+			}
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var keys []string
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go. DO NOT EDIT.\n\npackage openapi\n\n")
+	buf.WriteString("var docs = map[string]doc{\n")
+	for _, k := range keys {
+		d := docs[k]
+		fmt.Fprintf(&buf, "%q: {Description: %q", k, d.Description)
+		if len(d.Enum) > 0 {
+			fmt.Fprintf(&buf, ", Enum: %#v", d.Enum)
+		}
+		if d.ReadOnly {
+			buf.WriteString(", ReadOnly: true")
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile("docs.go", src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// fields - records the docs of every field in st. Anonymous structs are
+// walked with the field name appended to the key.
+func fields(docs map[string]doc, key string, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			if d := parse(name.Name, field.Doc); d.Description != "" || d.ReadOnly {
+				docs[key+"."+name.Name] = d
+			}
+			if nested := anonymous(field.Type); nested != nil {
+				fields(docs, key+"."+name.Name, nested)
+			}
+		}
+	}
+}
+
+// tagged - returns true when st has json tags. Structs without tags are
+// helpers and never serialized.
+func tagged(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if field.Tag != nil && strings.Contains(field.Tag.Value, "json:") {
+			return true
+		}
+	}
+	return false
+}
+
+// anonymous - returns the struct type behind slices and pointers.
+func anonymous(expr ast.Expr) *ast.StructType {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t
+	case *ast.ArrayType:
+		return anonymous(t.Elt)
+	case *ast.StarExpr:
+		return anonymous(t.X)
+	}
+	return nil
+}
+
+// parse - strips the "Name [marker] -" prefix from a comment and extracts
+// the allowed values when the first sentence is a list such as
+// "http, https or tcp.".
+func parse(name string, cg *ast.CommentGroup) (r doc) {
+	if cg == nil {
+		return
+	}
+	text := strings.Join(strings.Fields(cg.Text()), " ")
+	if m := prefixRegex.FindStringSubmatch(text); m != nil && (m[1] == name || m[2] != "" || m[4] != "") {
+		text = text[len(m[0]):]
+		switch m[3] {
+		case "", "optional":
+		case "system":
+			r.ReadOnly = true
+		default:
+			text = strings.ToUpper(m[3][:1]) + m[3][1:] + ". " + text
+		}
+	}
+	if text == "..." || strings.HasPrefix(text, "//") {
+		return
+	}
+	r.Description = text
+	r.Enum = enum(text)
+	return
+}
+
+// enum - returns the values listed in the first sentence of text, or nil
+// when the sentence is not a list of lower case tokens.
+func enum(text string) []string {
+	sentence := strings.SplitN(text, ". ", 2)[0]
+	sentence = strings.TrimSuffix(strings.TrimSpace(sentence), ".")
+	if i := strings.Index(sentence, "include"); i >= 0 {
+		sentence = strings.TrimPrefix(sentence[i+len("include"):], ":")
+	}
+	sentence = strings.TrimPrefix(strings.TrimSpace(sentence), "either ")
+	var r []string
+	for _, v := range listRegex.Split(sentence, -1) {
+		v = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(v), "and "))
+		v = strings.TrimSpace(strings.TrimPrefix(v, "or "))
+		if !tokenRegex.MatchString(v) {
+			return nil
+		}
+		r = append(r, v)
+	}
+	if len(r) < 2 {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,101 @@
+package openapi
+
+// Route - route registered by the handler package.
+type Route struct {
+	// Method - http method.
+	Method string
+	// Path - gin path including the group prefix, e.g. /api/v1/virtualserver/:id.
+	Path string
+	// Resource - route string of the definition, e.g. virtualserver.
+	Resource string
+	// Action - handler method serving the route, e.g. FetchByID.
+	Action string
+}
+
+// Document - OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []map[string][]string            `json:"security,omitempty"`
+}
+
+// Info - document metadata.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components - reusable schemas and security schemes.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme - authentication method accepted by the api.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Operation - single method on a path.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter - path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody - request payload.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response - response payload.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType - payload schema for a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema - subset of the OpenAPI schema object used by the models.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// doc - description of a model type or field taken from its comment.
+type doc struct {
+	Description string
+	Enum        []string
+	ReadOnly    bool
+}
@@ -0,0 +1,405 @@
+// Package openapi builds the OpenAPI 3 document served at
+// /api/v1/openapi.json from the route table and the model structs. Field
+// descriptions, enums and read only markers come from the struct comments
+// via docs.go; run go generate after changing a model comment.
+package openapi
+
+//go:generate go run gen.go
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/loadbalancer"
+	"github.com/ticketmaster/lbapi/migrate"
+	"github.com/ticketmaster/lbapi/virtualserver"
+	"github.com/ticketmaster/lbapi/webhook"
+)
+
+// resources - data model stored by each route.
+var resources = map[string]interface{}{
+	"loadbalancer":  loadbalancer.Data{},
+	"virtualserver": virtualserver.Data{},
+	"recycle":       virtualserver.Data{},
+	"status":        virtualserver.Data{},
+	"operations":    common.Operation{},
+	"audit":         common.AuditEntry{},
+	"schedule":      common.ScheduledChange{},
+	"approvals":     common.ChangeRequest{},
+}
+
+// New - builds the document describing routes.
+func New(routes []Route) (r *Document) {
+	r = &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "lbapi",
+			Description: "Load balancer management api for Avi Networks and Netscaler.",
+			Version:     "v1",
+		},
+		Paths: make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]*SecurityScheme{
+				"basic":  {Type: "http", Scheme: "basic"},
+				"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT or lbapi_ API token"},
+			},
+		},
+		Security: []map[string][]string{{"basic": {}}, {"bearer": {}}},
+	}
+	s := schemas(r.Components.Schemas)
+	for _, route := range routes {
+		p, params := pathParams(route.Path)
+		if r.Paths[p] == nil {
+			r.Paths[p] = make(map[string]*Operation)
+		}
+		op := s.operation(route)
+		op.Parameters = append(params, op.Parameters...)
+		r.Paths[p][strings.ToLower(route.Method)] = op
+	}
+	return r
+}
+
+// pathParams - converts gin :params to OpenAPI {params}.
+func pathParams(ginPath string) (r string, params []*Parameter) {
+	segments := strings.Split(ginPath, "/")
+	for k, v := range segments {
+		if !strings.HasPrefix(v, ":") {
+			continue
+		}
+		name := v[1:]
+		segments[k] = "{" + name + "}"
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          "path",
+			Description: pathDescriptions[name],
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+var pathDescriptions = map[string]string{
+	"id":    "record id. Legacy md5 ids are accepted as an alias.",
+	"pool":  "pool name or uuid.",
+	"ip":    "ip address of the backend server.",
+	"name":  "name of the service account.",
+	"token": "id of the api token.",
+}
+
+// record - registers the record and collection schemas of resource and
+// returns references to them.
+func (o schemas) record(resource string) (record *Schema, collection *Schema) {
+	name := strings.Title(resource)
+	if _, ok := o[name+"Record"]; !ok {
+		r := o.object(reflect.TypeOf(common.DbRecord{}), "common.DbRecord")
+		r.Properties["data"] = o.ref(resources[resource])
+		o[name+"Record"] = r
+		c := o.object(reflect.TypeOf(common.DbRecordCollection{}), "common.DbRecordCollection")
+		c.Properties["db_records"] = &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/" + name + "Record"}}
+		o[name+"Collection"] = c
+	}
+	return &Schema{Ref: "#/components/schemas/" + name + "Record"}, &Schema{Ref: "#/components/schemas/" + name + "Collection"}
+}
+
+// operation - describes the request and responses of route.
+func (o schemas) operation(route Route) (r *Operation) {
+	var record, collection *Schema
+	if _, ok := resources[route.Resource]; ok {
+		record, collection = o.record(route.Resource)
+	}
+	r = &Operation{
+		OperationID: strings.ToLower(route.Action[:1]) + route.Action[1:] + strings.Title(route.Resource),
+		Tags:        []string{route.Resource},
+		Responses:   make(map[string]*Response),
+	}
+	var body *Schema
+	ok := record
+	switch route.Action {
+	case "Fetch":
+		r.Summary = fmt.Sprintf("List %s records.", route.Resource)
+		r.Description = "Any field documented under Filters in the README may be passed as a query param."
+		r.Parameters = []*Parameter{
+			query("limit", "maximum number of records to return.", &Schema{Type: "integer"}),
+			query("offset", "number of records to skip. Prefer cursor.", &Schema{Type: "integer"}),
+			query("cursor", "token from sql_message._cursor of the previous page.", &Schema{Type: "string"}),
+			query("fields", "comma separated data paths to return, e.g. name,pools.bindings.server.ip.", &Schema{Type: "string"}),
+			query("orderCol", "field to order by.", &Schema{Type: "string"}),
+			query("orderDirection", "sort direction.", &Schema{Type: "string", Enum: []string{"asc", "desc"}}),
+		}
+		ok = collection
+	case "FetchByID":
+		r.Summary = fmt.Sprintf("Get a %s record.", route.Resource)
+		ok = collection
+	case "Create":
+		r.Summary = fmt.Sprintf("Create a %s record.", route.Resource)
+		r.Parameters = []*Parameter{
+			{Name: "Idempotency-Key", In: "header", Description: "unique key of the request. A retry with the same key and payload returns the original response instead of creating the resource again.", Schema: &Schema{Type: "string"}},
+			query("bulk", "set to yes to submit an array of records.", &Schema{Type: "string", Enum: []string{"yes"}}),
+		}
+		body = &Schema{OneOf: []*Schema{record, {Type: "array", Items: record}}}
+		ok = &Schema{OneOf: []*Schema{record, collection}}
+	case "Modify":
+		r.Summary = fmt.Sprintf("Replace a %s record.", route.Resource)
+		r.Parameters = []*Parameter{
+			ifMatch(),
+			query("dry_run", "set to true to return the planned changes without applying them.", &Schema{Type: "string", Enum: []string{"true"}}),
+			query("bulk", "set to yes to submit an array of records. The outcome of each is returned in results.", &Schema{Type: "string", Enum: []string{"yes"}}),
+			atomic(),
+		}
+		body = &Schema{OneOf: []*Schema{record, {Type: "array", Items: record}}}
+		ok = &Schema{OneOf: []*Schema{record, o.ref(common.PlanRecord{}), o.ref(common.BulkRecord{})}}
+		if route.Resource == "virtualserver" {
+			r.Parameters = append(r.Parameters, scheduleParams()...)
+		}
+	case "Patch":
+		r.Summary = fmt.Sprintf("Merge patch a %s record.", route.Resource)
+		r.Parameters = []*Parameter{ifMatch()}
+		r.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+			"application/merge-patch+json": {Schema: o.ref(resources[route.Resource])},
+		}}
+	case "Versions":
+		r.Summary = fmt.Sprintf("List the versions of a %s record.", route.Resource)
+		r.Description = "Every document accepted by a create, modify or migrate request, oldest first. Private keys are redacted."
+		ok = o.ref(common.VersionRecord{})
+	case "Rollback":
+		r.Summary = fmt.Sprintf("Roll a %s record back to an earlier version.", route.Resource)
+		r.Description = "Resubmits the version through modify. Redacted private keys are restored from secure storage."
+		r.Parameters = []*Parameter{
+			ifMatch(),
+			{Name: "version", In: "query", Description: "version to roll back to.", Required: true, Schema: &Schema{Type: "integer"}},
+		}
+	case "Restore":
+		r.Summary = "Recreate a deleted virtual server."
+		r.Description = "Creates the virtual server again through the virtualserver create pipeline and removes the recycle record once the load balancer change succeeds. The ip is kept if it is still free in infoblox, otherwise a new one is assigned."
+		r.Parameters = []*Parameter{
+			query("load_balancer_ip", "load balancer to restore to. Defaults to the one the record was deleted from.", &Schema{Type: "string"}),
+		}
+		record, _ = o.record("virtualserver")
+		ok = record
+	case "Delete":
+		r.Summary = fmt.Sprintf("Delete a %s record.", route.Resource)
+		r.Parameters = []*Parameter{ifMatch()}
+		if route.Resource == "virtualserver" {
+			r.Parameters = append(r.Parameters, scheduleParams()...)
+		}
+	case "Cancel":
+		r.Summary = "Cancel a scheduled change."
+		r.Description = "Only pending changes can be cancelled."
+	case "Approve":
+		r.Summary = "Approve a change request and apply it."
+		r.Description = "The change is applied with the rights of the user that requested it. The approver must be another user with rights to every product code of the change. The record returned by the change is returned."
+		record, _ = o.record("virtualserver")
+		ok = record
+	case "Reject":
+		r.Summary = "Reject a change request."
+		r.Parameters = []*Parameter{
+			query("reason", "reason for the rejection.", &Schema{Type: "string"}),
+		}
+	case "DeleteBulk":
+		r.Summary = fmt.Sprintf("Delete several %s records.", route.Resource)
+		r.Description = "The body is a json array of ids. The outcome of each is returned in results."
+		r.Parameters = []*Parameter{atomic()}
+		body = &Schema{Type: "array", Items: &Schema{Type: "string"}}
+		ok = o.ref(common.BulkRecord{})
+	case "ImportAll":
+		r.Summary = fmt.Sprintf("Import every %s from the load balancers.", route.Resource)
+		ok = collection
+	case "EnableBinding", "DisableBinding":
+		r.Summary = "Enable or disable the members of a pool pointing at a backend server."
+		r.Parameters = append([]*Parameter{ifMatch()}, stateParams(route.Action)...)
+	case "EnableMaintenance", "DisableMaintenance":
+		r.Summary = "Enable or disable a backend server on every virtual server that binds it."
+		r.Parameters = stateParams(route.Action)
+		ok = o.ref(common.MaintenanceRecord{})
+	case "FetchVs":
+		r.Summary = "List virtual servers in the simple format."
+		ok = o.ref(common.VsDbRecordCollection{})
+	case "Backup":
+		r.Summary = "Back up the virtual server records."
+		ok = &Schema{Type: "string"}
+	case "StageMigration":
+		r.Summary = "Stage a migration and run the readiness checks."
+		body = o.ref(common.MigrateRequest{})
+		ok = o.migrateRecord()
+	case "FetchStaged":
+		r.Summary = "Get a staged migration."
+		ok = o.migrateRecord()
+	case "Migrate":
+		r.Summary = "Migrate a staged virtual server."
+	case "Events":
+		r.Summary = "Stream record changes as Server-Sent Events."
+		r.Description = "Each event is named after its type (e.g. virtualserver.status_changed) and carries the event as json data. Params may be repeated or comma separated."
+		r.Parameters = []*Parameter{
+			query("product_code", "only stream events of these product codes.", &Schema{Type: "string"}),
+			query("route", "only stream events of these routes, e.g. virtualserver.", &Schema{Type: "string"}),
+			query("record_id", "only stream events of these records.", &Schema{Type: "string"}),
+		}
+	case "PreviewPurge":
+		r.Summary = "Count the records past their retention window."
+		r.Description = "Nothing is removed. Requires membership of the admin group."
+		ok = o.ref(common.PurgeRecord{})
+	case "Purge":
+		r.Summary = "Purge the records past their retention window."
+		r.Description = "Records of the recycle, status and migrate tables older than their retention window are written to a gzipped json file in the archive directory, then deleted. Requires membership of the admin group."
+		ok = o.ref(common.PurgeRecord{})
+	case "FetchServiceAccounts":
+		r.Summary = "List service accounts."
+		r.Description = "Only the accounts the user owns are returned. Admins see every account."
+		ok = &Schema{Type: "array", Items: o.ref(common.ServiceAccount{})}
+	case "CreateServiceAccount":
+		r.Summary = "Create a service account."
+		r.Description = "The user creating the account is always one of its owners. Service accounts cannot manage service accounts."
+		body = o.ref(common.ServiceAccount{})
+		ok = o.ref(common.ServiceAccount{})
+	case "DeleteServiceAccount":
+		r.Summary = "Delete a service account and revoke its tokens."
+		ok = o.ref(common.ServiceAccount{})
+	case "FetchTokens":
+		r.Summary = "List the API tokens of a service account."
+		r.Description = "Revoked tokens are included. Secrets are never returned."
+		ok = &Schema{Type: "array", Items: o.ref(common.APIToken{})}
+	case "IssueToken":
+		r.Summary = "Issue an API token to a service account."
+		r.Description = "The issuer must hold every permission of the token for every product code of it. The token is only returned in this response; send it as a bearer token."
+		body = o.ref(common.APIToken{})
+		ok = o.ref(common.IssuedToken{})
+	case "RevokeToken":
+		r.Summary = "Revoke an API token."
+		ok = o.ref(common.APIToken{})
+	case "OpenAPI":
+		r.Summary = "Get this document."
+		ok = &Schema{Type: "object"}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if body != nil {
+		r.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+			"application/json": {Schema: body},
+		}}
+	}
+	r.Responses["200"] = &Response{
+		Description: "success.",
+		Content:     map[string]*MediaType{"application/json": {Schema: ok}},
+	}
+	if route.Action == "Events" {
+		r.Responses["200"].Content = map[string]*MediaType{"text/event-stream": {Schema: o.ref(webhook.Event{})}}
+	}
+	r.Responses["400"] = &Response{
+		Description: "request failed. The error is returned in last_error. Missing records return 404, missing rights 403 and load balancer or database failures 502 or 500.",
+		Content:     map[string]*MediaType{"application/json": {Schema: ok}},
+	}
+	for _, v := range r.Parameters {
+		if v.Name == "If-Match" {
+			r.Responses["412"] = &Response{Description: "the record has changed since it was fetched."}
+		}
+	}
+	if route.Action == "Create" {
+		r.Responses["409"] = &Response{Description: "the original request with this Idempotency-Key is still in progress."}
+	}
+	if route.Action == "Cancel" {
+		r.Responses["409"] = &Response{Description: "the change has already started, finished or been cancelled."}
+	}
+	if route.Action == "CreateServiceAccount" {
+		r.Responses["409"] = &Response{Description: "a service account with this name already exists."}
+	}
+	if route.Action == "Approve" || route.Action == "Reject" {
+		r.Responses["409"] = &Response{Description: "the change request has already been approved or rejected."}
+	}
+	for _, v := range r.Parameters {
+		if v.Name == "apply_at" {
+			schedule, _ := o.record("schedule")
+			r.Responses["202"] = &Response{
+				Description: "the change was scheduled. Location points at the scheduled change.",
+				Content:     map[string]*MediaType{"application/json": {Schema: schedule}},
+			}
+		}
+	}
+	if protected(route) {
+		description := "the record has a protected product code and the change is waiting for approval. Location points at the change request and its id is returned in _approval_id."
+		if v, scheduled := r.Responses["202"]; scheduled {
+			v.Description += " Or " + description
+		} else {
+			r.Responses["202"] = &Response{
+				Description: description,
+				Content:     map[string]*MediaType{"application/json": {Schema: ok}},
+			}
+		}
+	}
+	switch route.Action {
+	case "Create", "Modify", "Patch":
+		r.Responses["422"] = &Response{
+			Description: "the payload failed validation. Every invalid field is listed in errors.",
+			Content:     map[string]*MediaType{"application/json": {Schema: o.ref(common.ErrorRecord{})}},
+		}
+	case "DeleteBulk":
+		r.Responses["422"] = &Response{
+			Description: "an item of an atomic request failed. No item was processed.",
+			Content:     map[string]*MediaType{"application/json": {Schema: ok}},
+		}
+	case "Rollback", "Restore":
+		r.Responses["422"] = &Response{
+			Description: "the record failed validation, or a certificate's private key is not in secure storage.",
+			Content:     map[string]*MediaType{"application/json": {Schema: o.ref(common.ErrorRecord{})}},
+		}
+	}
+	return r
+}
+
+// migrateRecord - registers the record returned by the staging routes and
+// returns a reference to it.
+func (o schemas) migrateRecord() *Schema {
+	if _, ok := o["MigrateRecord"]; !ok {
+		r := o.object(reflect.TypeOf(common.DbRecord{}), "common.DbRecord")
+		r.Properties["data"] = o.ref(migrate.Response{})
+		o["MigrateRecord"] = r
+	}
+	return &Schema{Ref: "#/components/schemas/MigrateRecord"}
+}
+
+// query - returns an optional query parameter.
+func query(name string, description string, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// ifMatch - returns the optional If-Match header.
+func ifMatch() *Parameter {
+	return &Parameter{Name: "If-Match", In: "header", Description: "ETag of the record. The request fails with 412 if the record has changed.", Schema: &Schema{Type: "string"}}
+}
+
+// atomic - returns the param aborting a bulk request when any item fails.
+func atomic() *Parameter {
+	return query("atomic", "set to true to check every item before any change is made. The request is aborted with 422 if one fails.", &Schema{Type: "string", Enum: []string{"true"}})
+}
+
+// protected - returns true when route may hold a change for approval.
+func protected(route Route) bool {
+	switch route.Action {
+	case "Create", "Modify", "Patch", "Rollback", "Delete", "Migrate", "EnableBinding", "DisableBinding":
+		return route.Resource == "virtualserver"
+	case "Restore":
+		return true
+	}
+	return false
+}
+
+// scheduleParams - returns the params scheduling a change for later.
+func scheduleParams() []*Parameter {
+	return []*Parameter{
+		query("apply_at", "RFC 3339 time to apply the change at instead of now.", &Schema{Type: "string", Format: "date-time"}),
+		query("window", "name of the maintenance window to apply the change in instead of now.", &Schema{Type: "string"}),
+	}
+}
+
+// stateParams - returns the params accepted when disabling a member.
+func stateParams(action string) []*Parameter {
+	if !strings.HasPrefix(action, "Disable") {
+		return nil
+	}
+	return []*Parameter{
+		query("graceful", "set to true to wait for sessions to complete.", &Schema{Type: "string", Enum: []string{"true", "false"}}),
+		query("delay", "seconds to wait before disabling.", &Schema{Type: "integer"}),
+	}
+}
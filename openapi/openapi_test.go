@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		want   string
+		params []string
+	}{
+		{"no params", "/api/v1/virtualserver", "/api/v1/virtualserver", nil},
+		{"id", "/api/v1/virtualserver/:id", "/api/v1/virtualserver/{id}", []string{"id"}},
+		{"nested", "/api/v1/virtualserver/:id/pools/:pool/bindings/:ip/disable", "/api/v1/virtualserver/{id}/pools/{pool}/bindings/{ip}/disable", []string{"id", "pool", "ip"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, params := pathParams(tt.path)
+			if r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+			if len(params) != len(tt.params) {
+				t.Fatalf("expected params %v, got %d", tt.params, len(params))
+			}
+			for k, v := range params {
+				if v.Name != tt.params[k] || v.In != "path" || !v.Required {
+					t.Fatalf("expected required path param %s, got %+v", tt.params[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  Route
+		id     string
+		params []string
+		body   bool
+		status string
+	}{
+		{"fetch", Route{"GET", "/api/v1/virtualserver", "virtualserver", "Fetch"}, "fetchVirtualserver", []string{"limit", "offset", "cursor", "fields", "orderCol", "orderDirection"}, false, ""},
+		{"create", Route{"POST", "/api/v1/virtualserver", "virtualserver", "Create"}, "createVirtualserver", []string{"bulk"}, true, ""},
+		{"modify", Route{"PUT", "/api/v1/virtualserver", "virtualserver", "Modify"}, "modifyVirtualserver", []string{"If-Match", "dry_run"}, true, "412"},
+		{"patch", Route{"PATCH", "/api/v1/virtualserver/:id", "virtualserver", "Patch"}, "patchVirtualserver", []string{"If-Match"}, true, "412"},
+		{"delete", Route{"DELETE", "/api/v1/virtualserver/:id", "virtualserver", "Delete"}, "deleteVirtualserver", []string{"If-Match"}, false, "412"},
+		{"disable binding", Route{"POST", "/api/v1/virtualserver/:id/pools/:pool/bindings/:ip/disable", "virtualserver", "DisableBinding"}, "disableBindingVirtualserver", []string{"If-Match", "graceful", "delay"}, false, "412"},
+		{"enable binding", Route{"POST", "/api/v1/virtualserver/:id/pools/:pool/bindings/:ip/enable", "virtualserver", "EnableBinding"}, "enableBindingVirtualserver", []string{"If-Match"}, false, "412"},
+		{"enable maintenance", Route{"POST", "/api/v1/maintenance/virtualserver/:ip/enable", "virtualserver", "EnableMaintenance"}, "enableMaintenanceVirtualserver", nil, false, ""},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := make(schemas).operation(tt.route)
+			if r.OperationID != tt.id {
+				t.Fatalf("expected operation id %s, got %s", tt.id, r.OperationID)
+			}
+			params := make(map[string]bool)
+			for _, v := range r.Parameters {
+				params[v.Name] = true
+			}
+			for _, v := range tt.params {
+				if !params[v] {
+					t.Fatalf("expected param %s, got %+v", v, r.Parameters)
+				}
+			}
+			if (r.RequestBody != nil) != tt.body {
+				t.Fatalf("expected request body %v, got %+v", tt.body, r.RequestBody)
+			}
+			if tt.status != "" && r.Responses[tt.status] == nil {
+				t.Fatalf("expected a %s response", tt.status)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type record struct {
+		Name    string            `json:"name,omitempty"`
+		Count   int64             `json:"count"`
+		Ratio   float64           `json:"ratio"`
+		Enabled bool              `json:"enabled"`
+		Tags    []string          `json:"tags"`
+		Raw     []byte            `json:"raw"`
+		Labels  map[string]string `json:"labels"`
+		Inner   *inner            `json:"inner"`
+		Any     interface{}       `json:"any"`
+		Skipped string            `json:"-"`
+		Plain   string
+		private string
+	}
+	s := make(schemas)
+	r := s.object(reflect.TypeOf(record{}), "")
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name     string
+		property string
+		want     Schema
+	}{
+		{"string", "name", Schema{Type: "string"}},
+		{"int64", "count", Schema{Type: "integer", Format: "int64"}},
+		{"float64", "ratio", Schema{Type: "number", Format: "double"}},
+		{"bool", "enabled", Schema{Type: "boolean"}},
+		{"untagged field", "Plain", Schema{Type: "string"}},
+		{"interface", "any", Schema{}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := r.Properties[tt.property]
+			if p == nil {
+				t.Fatalf("expected property %s", tt.property)
+			}
+			if p.Type != tt.want.Type || p.Format != tt.want.Format {
+				t.Fatalf("expected %+v, got %+v", tt.want, *p)
+			}
+		})
+	}
+	t.Run("slices and maps", func(t *testing.T) {
+		if r.Properties["tags"].Type != "array" || r.Properties["tags"].Items.Type != "string" {
+			t.Fatalf("expected array of strings, got %+v", r.Properties["tags"])
+		}
+		if r.Properties["raw"].Format != "byte" {
+			t.Fatalf("expected byte string, got %+v", r.Properties["raw"])
+		}
+		if r.Properties["labels"].AdditionalProperties.Type != "string" {
+			t.Fatalf("expected map of strings, got %+v", r.Properties["labels"])
+		}
+	})
+	t.Run("named structs are referenced", func(t *testing.T) {
+		if r.Properties["inner"].Ref != "#/components/schemas/openapi.inner" || s["openapi.inner"] == nil {
+			t.Fatalf("expected a component reference, got %+v", r.Properties["inner"])
+		}
+	})
+	t.Run("skipped fields", func(t *testing.T) {
+		for _, v := range []string{"-", "Skipped", "private"} {
+			if r.Properties[v] != nil {
+				t.Fatalf("expected %s to be skipped", v)
+			}
+		}
+	})
+}
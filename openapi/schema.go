@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// schemas - builds component schemas from the model structs.
+type schemas map[string]*Schema
+
+// ref - returns a reference to the component of v, registering it first.
+func (o schemas) ref(v interface{}) *Schema {
+	return o.schema(reflect.TypeOf(v), "")
+}
+
+// schema - returns the schema of t. Named structs are registered as
+// components and referenced; anonymous structs are inlined and their fields
+// documented under key.
+func (o schemas) schema(t reflect.Type, key string) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return o.schema(t.Elem(), key)
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: o.schema(t.Elem(), key)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: o.schema(t.Elem(), key)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return o.object(t, key)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := o[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			o[name] = nil
+			o[name] = o.object(t, name)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// interface{} - any value.
+	return &Schema{}
+}
+
+// object - returns the schema of struct t using the json names of its
+// exported fields.
+func (o schemas) object(t reflect.Type, key string) *Schema {
+	r := &Schema{Type: "object", Description: docs[key].Description, Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			name = tag
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			// Fields of embedded structs are promoted, as by encoding/json.
+			embedded := o.object(field.Type, path.Base(field.Type.PkgPath())+"."+field.Type.Name())
+			for k, v := range embedded.Properties {
+				r.Properties[k] = v
+			}
+			continue
+		}
+		fieldKey := key + "." + field.Name
+		s := o.schema(field.Type, fieldKey)
+		if d, ok := docs[fieldKey]; ok {
+			if s.Ref != "" {
+				// Siblings of $ref are ignored, so wrap the reference.
+				s = &Schema{AllOf: []*Schema{s}}
+			}
+			s.Description = d.Description
+			s.ReadOnly = d.ReadOnly
+			if s.Type == "string" {
+				s.Enum = d.Enum
+			}
+		}
+		r.Properties[name] = s
+	}
+	return r
+}
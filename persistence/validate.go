@@ -0,0 +1,38 @@
+package persistence
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+// SupportedTypes - persistence types supported by mfr. The types of every
+// platform are returned when mfr is empty.
+func SupportedTypes(mfr string) (r []string) {
+	keys := make(map[string]bool)
+	if mfr == "" || mfr == "avi networks" {
+		for k := range new(Avi).SetRefs().System {
+			keys[k] = true
+		}
+	}
+	if mfr == "" || mfr == "netscaler" {
+		for k := range new(Netscaler).SetRefs().System {
+			keys[k] = true
+		}
+	}
+	for k := range keys {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return
+}
+
+// Validate - checks the values of data against the options supported by
+// mfr. Paths are reported relative to path. An empty type disables
+// persistence.
+func Validate(data *Data, mfr string, path string) (r []shared.FieldError) {
+	r = append(r, shared.CheckEnum(shared.JSONPath(path, "type"), data.Type, SupportedTypes(mfr))...)
+	r = append(r, shared.CheckRange(shared.JSONPath(path, "timeout"), data.Timeout, 0, math.MaxInt32)...)
+	return
+}
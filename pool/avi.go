@@ -7,6 +7,7 @@ import (
 	"github.com/ticketmaster/lbapi/loadbalancer"
 	"github.com/ticketmaster/lbapi/monitor"
 	"github.com/ticketmaster/lbapi/persistence"
+	"github.com/ticketmaster/lbapi/shared"
 	"github.com/ticketmaster/lbapi/tmavi"
 	"github.com/avinetworks/sdk/go/clients"
 	"github.com/sirupsen/logrus"
@@ -24,6 +25,7 @@ type Avi struct {
 	Monitor          *monitor.Avi
 	Persistence      *persistence.Avi
 	RemovedArtifacts *RemovedArtifacts
+	Tracker          shared.Tracker
 	////////////////////////////////////////////////////////////////////////////
 }
 
@@ -90,7 +92,9 @@ func (o *Avi) Create(data *Data) (err error) {
 		return
 	}
 	////////////////////////////////////////////////////////////////////////////
+	done := shared.Track(o.Tracker, "pool create")
 	resp, err := o.Client.Pool.Create(req)
+	done(err)
 	if err != nil {
 		return
 	}
@@ -200,7 +200,9 @@ func (o *Avi) SetHealthMonitors(data *Data, source *models.Pool) (r []string, er
 	added, removed, updated := o.Monitor.Diff(data.HealthMonitors, sourceRefs)
 	////////////////////////////////////////////////////////////////////////////
 	for _, v := range added {
+		done := shared.Track(o.Tracker, "monitor create")
 		err = o.Monitor.Create(&v)
+		done(err)
 		if err != nil {
 			return
 		}
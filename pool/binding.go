@@ -0,0 +1,107 @@
+package pool
+
+import (
+	"fmt"
+
+	"github.com/ticketmaster/nitro-go-sdk/model"
+)
+
+// SetBindingState copies the state fields of state (Enabled, GracefulDisable
+// and DisableDelay) to every binding in data that points at ip. Returns the
+// number of bindings updated.
+func SetBindingState(data *Data, ip string, state MemberBinding) (n int) {
+	for k := range data.Bindings {
+		if data.Bindings[k].Server.IP != ip {
+			continue
+		}
+		data.Bindings[k].Enabled = state.Enabled
+		data.Bindings[k].GracefulDisable = state.GracefulDisable
+		data.Bindings[k].DisableDelay = state.DisableDelay
+		n++
+	}
+	return
+}
+
+// HasBinding returns true when any binding in data points at ip.
+func HasBinding(data *Data, ip string) bool {
+	for _, v := range data.Bindings {
+		if v.Server.IP == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// ModifyBindingState enables or disables the pool members pointing at ip
+// without touching the rest of the pool. GracefulDisable and DisableDelay are
+// not supported per server by Avi - the pool graceful_disable_timeout applies.
+func (o *Avi) ModifyBindingState(data *Data, ip string, state MemberBinding) (r *Data, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.Log.Infof("setting %s enabled=%v...", ip, state.Enabled)
+	////////////////////////////////////////////////////////////////////////////
+	if SetBindingState(data, ip, state) == 0 {
+		err = fmt.Errorf("no binding found for %s in pool %s", ip, data.Name)
+		return
+	}
+	return o.Modify(data)
+}
+
+// ModifyBindingState enables or disables the pool members pointing at ip
+// without touching the rest of the pool. Services have a single member, so
+// the service itself is enabled or disabled.
+func (o *Netscaler) ModifyBindingState(data *Data, ip string, state MemberBinding) (r *Data, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.Log.Infof("setting %s enabled=%v...", ip, state.Enabled)
+	////////////////////////////////////////////////////////////////////////////
+	if SetBindingState(data, ip, state) == 0 {
+		err = fmt.Errorf("no binding found for %s in pool %s", ip, data.Name)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if data.IsNsrService {
+		data.Enabled = state.Enabled
+		data.GracefulDisable = state.GracefulDisable
+		data.DisableDelay = state.DisableDelay
+		err = o.modifyState(data)
+		return data, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, v := range data.Bindings {
+		if v.Server.IP != ip {
+			continue
+		}
+		err = o.modifyBindingState(data.SourceUUID, v)
+		if err != nil {
+			return
+		}
+		if o.MemberBindings.Source[data.SourceUUID] != nil {
+			o.MemberBindings.Source[data.SourceUUID][fmt.Sprintf("%s-%v", v.Server.IP, v.Port)] = v
+		}
+	}
+	return data, nil
+}
+
+// modifyBindingState enables or disables a single servicegroup member.
+func (o *Netscaler) modifyBindingState(sg string, binding MemberBinding) (err error) {
+	switch binding.Enabled {
+	case true:
+		req := model.ServicegroupEnable{}
+		req.Servicegroup.Port = binding.Port
+		req.Servicegroup.Servername = binding.Server.SourceUUID
+		req.Servicegroup.Servicegroupname = sg
+		err = o.Client.EnableServicegroupServicegroupmemberBinding(req)
+	case false:
+		req := model.ServicegroupDisable{}
+		req.Servicegroup.Port = binding.Port
+		req.Servicegroup.Servername = binding.Server.SourceUUID
+		req.Servicegroup.Servicegroupname = sg
+		if binding.GracefulDisable {
+			req.Servicegroup.Graceful = "YES"
+		} else {
+			req.Servicegroup.Graceful = "NO"
+		}
+		req.Servicegroup.Delay = binding.DisableDelay
+		err = o.Client.DisableServicegroupServicegroupmemberBinding(req)
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package pool
+
+import "testing"
+
+func TestSetBindingState(t *testing.T) {
+	data := Data{
+		Name: "prd1-pool",
+		Bindings: []MemberBinding{
+			{Port: 80, Enabled: true, Server: Server{IP: "10.0.0.1"}},
+			{Port: 8080, Enabled: true, Server: Server{IP: "10.0.0.1"}},
+			{Port: 80, Enabled: true, Server: Server{IP: "10.0.0.2"}},
+		},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name  string
+		ip    string
+		state MemberBinding
+		n     int
+	}{
+		{"disable every port", "10.0.0.1", MemberBinding{Enabled: false}, 2},
+		{"graceful disable", "10.0.0.2", MemberBinding{Enabled: false, GracefulDisable: true, DisableDelay: 30}, 1},
+		{"enable", "10.0.0.2", MemberBinding{Enabled: true}, 1},
+		{"unknown ip", "10.0.0.3", MemberBinding{Enabled: false}, 0},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := data
+			d.Bindings = append([]MemberBinding(nil), data.Bindings...)
+			if n := SetBindingState(&d, tt.ip, tt.state); n != tt.n {
+				t.Fatalf("expected %d bindings updated, got %d", tt.n, n)
+			}
+			for k, v := range d.Bindings {
+				want := data.Bindings[k]
+				if v.Server.IP == tt.ip {
+					want.Enabled = tt.state.Enabled
+					want.GracefulDisable = tt.state.GracefulDisable
+					want.DisableDelay = tt.state.DisableDelay
+				}
+				if v.Port != want.Port || v.Enabled != want.Enabled || v.GracefulDisable != want.GracefulDisable || v.DisableDelay != want.DisableDelay {
+					t.Fatalf("expected binding %+v, got %+v", want, v)
+				}
+			}
+		})
+	}
+}
+
+func TestHasBinding(t *testing.T) {
+	data := Data{Bindings: []MemberBinding{{Port: 80, Server: Server{IP: "10.0.0.1"}}}}
+	tests := []struct {
+		name string
+		data Data
+		ip   string
+		want bool
+	}{
+		{"bound", data, "10.0.0.1", true},
+		{"not bound", data, "10.0.0.2", false},
+		{"prefix only", data, "10.0.0.", false},
+		{"no bindings", Data{}, "10.0.0.1", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := HasBinding(&tt.data, tt.ip); r != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+		})
+	}
+}
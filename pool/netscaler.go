@@ -5,6 +5,7 @@ import (
 
 	"github.com/ticketmaster/lbapi/loadbalancer"
 	"github.com/ticketmaster/lbapi/monitor"
+	"github.com/ticketmaster/lbapi/shared"
 	"github.com/ticketmaster/nitro-go-sdk/client"
 	"github.com/ticketmaster/nitro-go-sdk/model"
 	"github.com/sirupsen/logrus"
@@ -21,6 +22,7 @@ type Netscaler struct {
 	Log              *logrus.Entry
 	Monitor          *monitor.Netscaler
 	RemovedArtifacts *RemovedArtifacts
+	Tracker          shared.Tracker
 	////////////////////////////////////////////////////////////////////////////
 }
 
@@ -116,12 +118,14 @@ func (o *Netscaler) Create(data *Data) (err error) {
 	var newPool Data
 	newPool = *data
 	////////////////////////////////////////////////////////////////////////////
+	done := shared.Track(o.Tracker, "pool create")
 	switch data.IsNsrService {
 	case true:
 		err = o.createService(&newPool)
 	case false:
 		err = o.createServicegroup(&newPool)
 	}
+	done(err)
 	if err != nil {
 		return
 	}
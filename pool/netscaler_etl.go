@@ -213,7 +213,9 @@ func (o *Netscaler) modifyHealthMonitors(data *Data, source []monitor.Data) (err
 		if data.Name != "" {
 			added[k].Name = fmt.Sprintf("%s-%s-%s", data.Name, strings.ToLower(added[k].Type), shared.RandStringBytesMaskImpr(3))
 		}
+		done := shared.Track(o.Tracker, "monitor create")
 		err := o.Monitor.Create(&added[k])
+		done(err)
 		if err != nil {
 			o.Log.Warn(err)
 			msg := err.Error()
@@ -605,31 +607,9 @@ func (o *Netscaler) modifyBindings(data *Data, sourceData *Data) (err error) {
 		// Modify bindings.
 		////////////////////////////////////////////////////////////////////////
 		for k := range updated {
-			switch updated[k].Enabled {
-			case true:
-				req := model.ServicegroupEnable{}
-				req.Servicegroup.Port = updated[k].Port
-				req.Servicegroup.Servername = updated[k].Server.SourceUUID
-				req.Servicegroup.Servicegroupname = data.SourceUUID
-				err = o.Client.EnableServicegroupServicegroupmemberBinding(req)
-				if err != nil {
-					return
-				}
-			case false:
-				req := model.ServicegroupDisable{}
-				req.Servicegroup.Port = updated[k].Port
-				req.Servicegroup.Servername = updated[k].Server.SourceUUID
-				req.Servicegroup.Servicegroupname = data.SourceUUID
-				if updated[k].GracefulDisable {
-					req.Servicegroup.Graceful = "YES"
-				} else {
-					req.Servicegroup.Graceful = "NO"
-				}
-				req.Servicegroup.Delay = updated[k].DisableDelay
-				err = o.Client.DisableServicegroupServicegroupmemberBinding(req)
-				if err != nil {
-					return
-				}
+			err = o.modifyBindingState(data.SourceUUID, updated[k])
+			if err != nil {
+				return
 			}
 			o.MemberBindings.Source[data.SourceUUID][fmt.Sprintf("%s-%v", updated[k].Server.IP, updated[k].Port)] = updated[k]
 		}
@@ -0,0 +1,36 @@
+package pool
+
+import (
+	"math"
+	"net"
+
+	"github.com/ticketmaster/lbapi/monitor"
+	"github.com/ticketmaster/lbapi/persistence"
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+// Validate - checks the values of data, its bindings, health monitors and
+// persistence against the options supported by mfr. Paths are reported
+// relative to path.
+func Validate(data *Data, mfr string, path string) (r []shared.FieldError) {
+	r = append(r, shared.CheckRange(shared.JSONPath(path, "default_port"), data.DefaultPort, 0, 65535)...)
+	r = append(r, shared.CheckRange(shared.JSONPath(path, "disable_delay"), data.DisableDelay, 0, math.MaxInt32)...)
+	for k, v := range data.Bindings {
+		bindingPath := shared.JSONPath(shared.JSONPath(path, "bindings"), k)
+		ipPath := shared.JSONPath(bindingPath, "server.ip")
+		r = append(r, shared.CheckRequired(ipPath, v.Server.IP != "")...)
+		if v.Server.IP != "" && net.ParseIP(v.Server.IP) == nil {
+			r = append(r, shared.FieldError{Path: ipPath, Reason: "must be an ip address"})
+		}
+		// The default port replaces the binding port when set.
+		if data.DefaultPort == 0 {
+			r = append(r, shared.CheckRange(shared.JSONPath(bindingPath, "port"), v.Port, 1, 65535)...)
+		}
+		r = append(r, shared.CheckRange(shared.JSONPath(bindingPath, "disable_delay"), v.DisableDelay, 0, math.MaxInt32)...)
+	}
+	for k := range data.HealthMonitors {
+		r = append(r, monitor.Validate(&data.HealthMonitors[k], mfr, shared.JSONPath(shared.JSONPath(path, "health_monitors"), k))...)
+	}
+	r = append(r, persistence.Validate(&data.Persistence, mfr, shared.JSONPath(path, "persistence"))...)
+	return
+}
@@ -0,0 +1,70 @@
+package routeconfig
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Approval - Object interface. Change requests are submitted through the
+// create, modify, delete and migrate routes of the record, so only fetch,
+// approve and reject methods are exposed to the handler.
+type Approval struct {
+	common *common.Common
+}
+
+// NewApproval - approval constructor.
+func NewApproval() *Approval {
+	o := new(Approval)
+	o.common = common.New()
+	////////////////////////////////////////////////////////////////////////////
+	o.common.Database.Table = "approvals"
+	o.common.Database.Validate = o.validate
+	o.common.Database.Client = dao.GlobalDAO
+	o.common.Setting = config.GlobalConfig
+	////////////////////////////////////////////////////////////////////////////
+	o.common.ModifyLb = false
+	o.common.Route = "approvals"
+	o.common.Log = logrus.New().WithField("route", "approvals")
+	o.common.Log.Logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+	////////////////////////////////////////////////////////////////////////////
+	return o
+}
+
+// Fetch - returns change requests.
+func (o *Approval) Fetch(p map[string][]string, limit int, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.Fetch(p, limit, oUser)
+}
+
+// FetchByID - returns a single change request.
+func (o *Approval) FetchByID(id string, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.FetchByID(id, oUser)
+}
+
+// Approve - approves a pending change request and releases it.
+func (o *Approval) Approve(id string, oUser *userenv.User) (common.DbRecord, error) {
+	return o.common.Approve(id, map[string]*common.Common{
+		"virtualserver": NewVirtualServer().Common,
+	}, oUser)
+}
+
+// Reject - rejects a pending change request.
+func (o *Approval) Reject(id string, reason string, oUser *userenv.User) (common.DbRecord, error) {
+	return o.common.Reject(id, reason, oUser)
+}
+
+// GetRoute - returns route string.
+func (o *Approval) GetRoute() string {
+	return o.common.GetRoute()
+}
+
+// validate - ensures that the approval object meets the minimum requirements for submission.
+func (o *Approval) validate(dbRecord *common.DbRecord) (ok bool, err error) {
+
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package routeconfig
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Audit - Object interface. Audit records are written by the system and are
+// read only, so only fetch methods are exposed to the handler.
+type Audit struct {
+	common *common.Common
+}
+
+// NewAudit - audit constructor.
+func NewAudit() *Audit {
+	o := new(Audit)
+	o.common = common.New()
+	////////////////////////////////////////////////////////////////////////////
+	o.common.Database.Table = "audit"
+	o.common.Database.Validate = o.validate
+	o.common.Database.Client = dao.GlobalDAO
+	o.common.Setting = config.GlobalConfig
+	////////////////////////////////////////////////////////////////////////////
+	o.common.ModifyLb = false
+	o.common.Route = "audit"
+	o.common.Log = logrus.New().WithField("route", "audit")
+	o.common.Log.Logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+	////////////////////////////////////////////////////////////////////////////
+	return o
+}
+
+// Fetch - returns audit records.
+func (o *Audit) Fetch(p map[string][]string, limit int, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.Fetch(p, limit, oUser)
+}
+
+// FetchByID - returns a single audit record.
+func (o *Audit) FetchByID(id string, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.FetchByID(id, oUser)
+}
+
+// GetRoute - returns route string.
+func (o *Audit) GetRoute() string {
+	return o.common.GetRoute()
+}
+
+// validate - ensures that the audit object meets the minimum requirements for submission.
+func (o *Audit) validate(dbRecord *common.DbRecord) (ok bool, err error) {
+
+	return true, nil
+}
@@ -1,8 +1,6 @@
 package routeconfig
 
 import (
-	"fmt"
-
 	"github.com/sirupsen/logrus"
 	"github.com/ticketmaster/lbapi/common"
 	"github.com/ticketmaster/lbapi/config"
@@ -44,28 +42,13 @@ func (o *LoadBalancer) validate(dbRecord *common.DbRecord) (ok bool, err error)
 		return
 	}
 	////////////////////////////////////////////////////////////////////////////
-	ok = true
-	required := make(map[string]bool)
-	required["ProductCode"] = false
-	required["Mfr"] = false
-	required["LoadBalancerIP"] = false
+	var fields []shared.FieldError
+	fields = append(fields, shared.CheckRequired("data.product_code", data.ProductCode != 0)...)
+	fields = append(fields, shared.CheckRequired("load_balancer_ip", dbRecord.LoadBalancerIP != "")...)
+	fields = append(fields, shared.CheckRequired("data.mfr", data.Mfr != "")...)
+	fields = append(fields, shared.CheckEnum("data.mfr", data.Mfr, []string{"avi networks", "netscaler"})...)
 	////////////////////////////////////////////////////////////////////////////
-	if data.ProductCode != 0 {
-		required["ProductCode"] = true
-	}
-	if dbRecord.LoadBalancerIP != "" {
-		required["LoadBalancerIP"] = true
-	}
-	if data.Mfr != "" {
-		required["Mfr"] = true
-	}
-	for _, val := range required {
-		if val == false {
-			ok = false
-		}
-	}
-	if !ok {
-		err = fmt.Errorf("missing required fields - %+v", required)
-	}
+	err = shared.NewValidationError(fields)
+	ok = err == nil
 	return
 }
@@ -0,0 +1,57 @@
+package routeconfig
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Operation - Object interface. Operations are written by the system and are
+// read only, so only fetch methods are exposed to the handler.
+type Operation struct {
+	common *common.Common
+}
+
+// NewOperation - operation constructor.
+func NewOperation() *Operation {
+	o := new(Operation)
+	o.common = common.New()
+	////////////////////////////////////////////////////////////////////////////
+	o.common.Database.Table = "operations"
+	o.common.Database.Validate = o.validate
+	o.common.Database.Client = dao.GlobalDAO
+	o.common.Setting = config.GlobalConfig
+	////////////////////////////////////////////////////////////////////////////
+	o.common.ModifyLb = false
+	o.common.Route = "operations"
+	o.common.Log = logrus.New().WithField("route", "operations")
+	o.common.Log.Logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+	////////////////////////////////////////////////////////////////////////////
+	return o
+}
+
+// Fetch - returns operation records.
+func (o *Operation) Fetch(p map[string][]string, limit int, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.Fetch(p, limit, oUser)
+}
+
+// FetchByID - returns a single operation record.
+func (o *Operation) FetchByID(id string, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.FetchByID(id, oUser)
+}
+
+// GetRoute - returns route string.
+func (o *Operation) GetRoute() string {
+	return o.common.GetRoute()
+}
+
+// validate - ensures that the operation object meets the minimum requirements for submission.
+func (o *Operation) validate(dbRecord *common.DbRecord) (ok bool, err error) {
+
+	return true, nil
+}
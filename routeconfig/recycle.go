@@ -6,6 +6,7 @@ import (
 	"github.com/ticketmaster/lbapi/dao"
 
 	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
 )
 
 // Recycle - Object interface.
@@ -14,8 +15,8 @@ type Recycle struct {
 }
 
 // NewRecycle - recycle constructor.
-func NewRecycle() *Virtualserver {
-	o := new(Virtualserver)
+func NewRecycle() *Recycle {
+	o := new(Recycle)
 	o.Common = common.New()
 	////////////////////////////////////////////////////////////////////////////
 	o.Database.Table = "recycle"
@@ -33,6 +34,12 @@ func NewRecycle() *Virtualserver {
 	return o
 }
 
+// Restore - recreates a deleted virtual server through the virtualserver
+// Create pipeline.
+func (o *Recycle) Restore(id string, loadBalancerIP string, oUser *userenv.User) (common.DbRecord, error) {
+	return o.Common.Restore(id, loadBalancerIP, NewVirtualServer().Common, oUser)
+}
+
 // validate - ensures that the load balancer object meets the minimum requirements for submission.
 func (o *Recycle) validate(dbRecord *common.DbRecord) (ok bool, err error) {
 
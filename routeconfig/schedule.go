@@ -0,0 +1,70 @@
+package routeconfig
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+
+	"github.com/ticketmaster/lbapi/common"
+	"github.com/ticketmaster/lbapi/userenv"
+)
+
+// Schedule - Object interface. Scheduled changes are submitted through the
+// modify and delete routes of the record, so only fetch and cancel methods
+// are exposed to the handler.
+type Schedule struct {
+	common *common.Common
+}
+
+// NewSchedule - schedule constructor.
+func NewSchedule() *Schedule {
+	o := new(Schedule)
+	o.common = common.New()
+	////////////////////////////////////////////////////////////////////////////
+	o.common.Database.Table = "schedule"
+	o.common.Database.Validate = o.validate
+	o.common.Database.Client = dao.GlobalDAO
+	o.common.Setting = config.GlobalConfig
+	////////////////////////////////////////////////////////////////////////////
+	o.common.ModifyLb = false
+	o.common.Route = "schedule"
+	o.common.Log = logrus.New().WithField("route", "schedule")
+	o.common.Log.Logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+	////////////////////////////////////////////////////////////////////////////
+	return o
+}
+
+// Fetch - returns scheduled changes.
+func (o *Schedule) Fetch(p map[string][]string, limit int, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.Fetch(p, limit, oUser)
+}
+
+// FetchByID - returns a single scheduled change.
+func (o *Schedule) FetchByID(id string, oUser *userenv.User) (common.DbRecordCollection, error) {
+	return o.common.FetchByID(id, oUser)
+}
+
+// Cancel - cancels a pending scheduled change.
+func (o *Schedule) Cancel(id string, oUser *userenv.User) (common.DbRecord, error) {
+	return o.common.CancelChange(id, oUser)
+}
+
+// Start - applies due changes in the background.
+func (o *Schedule) Start() {
+	o.common.StartScheduler(map[string]*common.Common{
+		"virtualserver": NewVirtualServer().Common,
+	})
+}
+
+// GetRoute - returns route string.
+func (o *Schedule) GetRoute() string {
+	return o.common.GetRoute()
+}
+
+// validate - ensures that the schedule object meets the minimum requirements for submission.
+func (o *Schedule) validate(dbRecord *common.DbRecord) (ok bool, err error) {
+
+	return true, nil
+}
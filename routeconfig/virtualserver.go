@@ -1,14 +1,13 @@
 package routeconfig
 
 import (
-	"fmt"
-
 	"github.com/sirupsen/logrus"
 	"github.com/ticketmaster/lbapi/config"
 	"github.com/ticketmaster/lbapi/dao"
 
 	"github.com/ticketmaster/lbapi/common"
 	"github.com/ticketmaster/lbapi/shared"
+	"github.com/ticketmaster/lbapi/userenv"
 	"github.com/ticketmaster/lbapi/virtualserver"
 )
 
@@ -38,6 +37,12 @@ func NewVirtualServer() *Virtualserver {
 	return o
 }
 
+// Schedule - persists a modify or delete to be applied at applyAt or in the
+// maintenance window named window.
+func (o *Virtualserver) Schedule(action string, id string, p []byte, applyAt string, window string, oUser *userenv.User) (common.DbRecord, error) {
+	return o.ScheduleChange(action, id, p, applyAt, window, oUser)
+}
+
 // validate - ensures that the load balancer object meets the minimum requirements for submission.
 func (o *Virtualserver) validate(dbRecord *common.DbRecord) (ok bool, err error) {
 	////////////////////////////////////////////////////////////////////////////
@@ -51,36 +56,14 @@ func (o *Virtualserver) validate(dbRecord *common.DbRecord) (ok bool, err error)
 	////////////////////////////////////////////////////////////////////////////
 	// Test required fields.
 	////////////////////////////////////////////////////////////////////////////
-	ok = true
-	required := make(map[string]bool)
-	required["ProductCode"] = false
-	required["IP"] = false
-	required["Port"] = false
-	required["LoadBalancerIP"] = false
-	required["Name"] = false
+	var fields []shared.FieldError
+	fields = append(fields, shared.CheckRequired("data.product_code", data.ProductCode != 0)...)
+	fields = append(fields, shared.CheckRequired("load_balancer_ip", len(dbRecord.LoadBalancerIP) > 0)...)
+	fields = append(fields, shared.CheckRequired("data.ports", len(data.Ports) != 0)...)
+	fields = append(fields, shared.CheckRequired("data.ip", data.IP != "")...)
+	fields = append(fields, shared.CheckRequired("data.name", data.Name != "")...)
 	////////////////////////////////////////////////////////////////////////////
-	if data.ProductCode != 0 {
-		required["ProductCode"] = true
-	}
-	if len(dbRecord.LoadBalancerIP) > 0 {
-		required["LoadBalancerIP"] = true
-	}
-	if len(data.Ports) != 0 {
-		required["Port"] = true
-	}
-	if data.IP != "" {
-		required["IP"] = true
-	}
-	if data.Name != "" {
-		required["Name"] = true
-	}
-	for _, val := range required {
-		if val == false {
-			ok = false
-		}
-	}
-	if !ok {
-		err = fmt.Errorf("missing required fields - %+v", required)
-	}
+	err = shared.NewValidationError(fields)
+	ok = err == nil
 	return
 }
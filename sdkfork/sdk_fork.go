@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ticketmaster/lbapi/loadbalancer"
+	"github.com/ticketmaster/lbapi/pool"
 	"github.com/ticketmaster/lbapi/shared"
 	"github.com/ticketmaster/lbapi/virtualserver"
 	"github.com/sirupsen/logrus"
@@ -19,8 +20,9 @@ const (
 
 // SdkConf - stores fields for configuring the SdkFork object.
 type SdkConf struct {
-	Target *SdkTarget
-	Log    *logrus.Entry
+	Target  *SdkTarget
+	Log     *logrus.Entry
+	Tracker shared.Tracker
 }
 
 // SdkFork stores Avi and Netscaler methods.
@@ -32,8 +34,9 @@ type SdkFork struct {
 	Virtualserver *virtualserver.VirtualServer
 	Loadbalancer  *loadbalancer.LoadBalancer
 	////////////////////////////////////////////////////////////////////////////
-	Target *SdkTarget
-	Log    *logrus.Entry
+	Target  *SdkTarget
+	Log     *logrus.Entry
+	Tracker shared.Tracker
 	////////////////////////////////////////////////////////////////////////////
 }
 
@@ -49,6 +52,7 @@ func New(conf *SdkConf) *SdkFork {
 	////////////////////////////////////////////////////////////////////////////
 	o.Target = conf.Target
 	o.Log = conf.Log
+	o.Tracker = conf.Tracker
 	////////////////////////////////////////////////////////////////////////////
 	err = o.setConnection()
 	if err != nil {
@@ -97,8 +101,12 @@ func (o *SdkFork) setVsFacts() (err error) {
 	switch o.Target.Mfr {
 	case AVI:
 		o.Virtualserver.Avi = virtualserver.NewAvi(o.Avi.Client, o.Loadbalancer.Avi, o.Log)
+		o.Virtualserver.Avi.Tracker = o.Tracker
+		o.Virtualserver.Avi.Pool.Tracker = o.Tracker
 	case NSR:
 		o.Virtualserver.Netscaler = virtualserver.NewNetscaler(o.Netscaler.Client, o.Loadbalancer.Netscaler, o.Log)
+		o.Virtualserver.Netscaler.Tracker = o.Tracker
+		o.Virtualserver.Netscaler.Pool.Tracker = o.Tracker
 	default:
 		err = fmt.Errorf("%s is not supported by this system", o.Target.Mfr)
 	}
@@ -338,3 +346,91 @@ func (o *SdkFork) Modify(data interface{}, route string) (r interface{}, err err
 	////////////////////////////////////////////////////////////////////////////
 	return
 }
+
+// Plan returns the changes a modify would apply to the loadbalancer without
+// applying them.
+func (o *SdkFork) Plan(data interface{}, route string) (r *virtualserver.Plan, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.setLog("plan")
+	////////////////////////////////////////////////////////////////////////////
+	err = o.setFacts()
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var d virtualserver.Data
+	shared.MarshalInterface(data, &d)
+	////////////////////////////////////////////////////////////////////////////
+	if route != "virtualserver" {
+		err = fmt.Errorf("%s does not support plan method", route)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if o.Avi != nil {
+		r, err = o.Virtualserver.Avi.Plan(&d)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if o.Netscaler != nil {
+		r, err = o.Virtualserver.Netscaler.Plan(&d)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	return
+}
+
+// ModifyBindingState enables or disables the members of a single pool that
+// point at ip. Pools are matched by name or uuid. Returns the record data with
+// the new binding state.
+func (o *SdkFork) ModifyBindingState(data interface{}, poolName string, ip string, state pool.MemberBinding, route string) (r interface{}, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.setLog("modify binding state")
+	////////////////////////////////////////////////////////////////////////////
+	if route != "virtualserver" {
+		err = fmt.Errorf("%s does not support binding state method", route)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	err = o.setFacts()
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var d virtualserver.Data
+	shared.MarshalInterface(data, &d)
+	////////////////////////////////////////////////////////////////////////////
+	k := -1
+	for i, v := range d.Pools {
+		if v.Name == poolName || v.SourceUUID == poolName {
+			k = i
+			break
+		}
+	}
+	if k < 0 {
+		err = fmt.Errorf("no pool found - %s", poolName)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Apply against the pool as it is on the loadbalancer.
+	////////////////////////////////////////////////////////////////////////////
+	var source *pool.Data
+	if o.Avi != nil {
+		source, err = o.Virtualserver.Avi.Pool.Fetch(d.Pools[k].SourceUUID)
+		if err != nil {
+			return
+		}
+		_, err = o.Virtualserver.Avi.Pool.ModifyBindingState(source, ip, state)
+	}
+	if o.Netscaler != nil {
+		source, err = o.Virtualserver.Netscaler.Pool.Fetch(d.Pools[k].SourceUUID)
+		if err != nil {
+			return
+		}
+		_, err = o.Virtualserver.Netscaler.Pool.ModifyBindingState(source, ip, state)
+	}
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	pool.SetBindingState(&d.Pools[k], ip, state)
+	r = d
+	return
+}
@@ -0,0 +1,122 @@
+// Package secret keeps certificate private keys encrypted at rest in the
+// secrets table, keyed by the certificate they belong to. Keys are redacted
+// from the audit log and the version history, and are restored from here when
+// an older version is resubmitted.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ticketmaster/lbapi/certificate"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+)
+
+var (
+	// ErrDisabled - no key is configured under [Secrets].
+	ErrDisabled = errors.New("secure storage is not configured")
+	// ErrNotFound - no private key is stored for the certificate.
+	ErrNotFound = errors.New("no private key is stored for the certificate")
+)
+
+// Enabled - returns true when a key is configured under [Secrets].
+func Enabled() bool {
+	return config.GlobalConfig != nil && config.GlobalConfig.Secrets.Key != ""
+}
+
+// Store - encrypts the private key and passphrase of the PEM certificate
+// cert and saves them, replacing any stored before. Does nothing unless
+// secure storage is enabled.
+func Store(cert string, key certificate.Key, user string) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if !Enabled() || key.PrivateKey == "" {
+		return
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	p, err := json.Marshal(certificate.Key{PrivateKey: key.PrivateKey, PassPhrase: key.PassPhrase})
+	if err != nil {
+		return
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return
+	}
+	sealed := base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, p, nil))
+	////////////////////////////////////////////////////////////////////////////
+	_, err = dao.GlobalDAO.Db.Exec(`INSERT INTO public.secrets (id, data, last_modified, last_modified_by) VALUES ($1, $2, current_timestamp, $3)
+		ON CONFLICT (id) DO UPDATE SET data=EXCLUDED.data, last_modified=EXCLUDED.last_modified, last_modified_by=EXCLUDED.last_modified_by`, ID(cert), sealed, user)
+	return
+}
+
+// Lookup - returns the private key and passphrase stored for the PEM
+// certificate cert.
+func Lookup(cert string) (r certificate.Key, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	if !Enabled() {
+		return r, ErrDisabled
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var sealed string
+	err = dao.GlobalDAO.Db.QueryRow(`SELECT data FROM public.secrets WHERE id=$1`, ID(cert)).Scan(&sealed)
+	if err == sql.ErrNoRows {
+		return r, ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+	p, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return
+	}
+	if len(p) < gcm.NonceSize() {
+		return r, fmt.Errorf("stored private key is malformed")
+	}
+	p, err = gcm.Open(nil, p[:gcm.NonceSize()], p[gcm.NonceSize():], nil)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(p, &r)
+	return
+}
+
+// ID - returns the id of the secret stored for the PEM certificate cert: the
+// hex SHA-256 of the certificate.
+func ID(cert string) string {
+	sum := sha256.Sum256([]byte(cert))
+	return hex.EncodeToString(sum[:])
+}
+
+// newGCM - returns the AES-256-GCM cipher of the configured key.
+func newGCM() (r cipher.AEAD, err error) {
+	key, err := base64.StdEncoding.DecodeString(config.GlobalConfig.Secrets.Key)
+	if err != nil {
+		return nil, fmt.Errorf("Secrets.Key must be base64 encoded - %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Secrets.Key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,67 @@
+package secret
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestID(t *testing.T) {
+	tests := []struct {
+		name string
+		cert string
+		want string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"pem", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := ID(tt.cert); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestNewGCM(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	tests := []struct {
+		name    string
+		key     string
+		enabled bool
+		err     string
+	}{
+		{"disabled", "", false, "must be 32 bytes"},
+		{"not base64", "not base64!", true, "must be base64 encoded"},
+		{"short key", base64.StdEncoding.EncodeToString(make([]byte, 16)), true, "must be 32 bytes"},
+		{"valid key", base64.StdEncoding.EncodeToString(make([]byte, 32)), true, ""},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Secrets: config.Secrets{Key: tt.key}}
+			if Enabled() != tt.enabled {
+				t.Fatalf("expected enabled %v, got %v", tt.enabled, Enabled())
+			}
+			_, err := newGCM()
+			if tt.err == "" && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestLookupDisabled(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = nil
+	if _, err := Lookup("pem"); err != ErrDisabled {
+		t.Fatalf("expected %v, got %v", ErrDisabled, err)
+	}
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"crypto/md5"
+	crand "crypto/rand"
 	b64 "encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -177,6 +178,19 @@ func RandStringBytesMaskImpr(n int) string {
 	return string(b)
 }
 
+// NewUUID generates a random (version 4) uuid.
+func NewUUID() (r string, err error) {
+	b := make([]byte, 16)
+	_, err = io.ReadFull(crand.Reader, b)
+	if err != nil {
+		return
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	r = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	return
+}
+
 // EncodePorts ...
 func EncodePorts(in interface{}) (r string, err error) {
 	var p []Port
@@ -0,0 +1,53 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MergePatch applies an RFC 7396 JSON merge patch to doc. Object members in
+// the patch are merged recursively, null members are removed and any other
+// value replaces the target outright. Numbers are preserved as written.
+func MergePatch(doc []byte, patch []byte) (r []byte, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	var target interface{}
+	if len(bytes.TrimSpace(doc)) > 0 {
+		target, err = decodeJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	////////////////////////////////////////////////////////////////////////////
+	return json.Marshal(mergePatch(target, p))
+}
+
+// mergePatch - implements the MergePatch algorithm from RFC 7396 section 2.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+func decodeJSON(p []byte) (r interface{}, err error) {
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	err = d.Decode(&r)
+	return
+}
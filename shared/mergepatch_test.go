@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Cases from RFC 7396 appendix A plus number handling.
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		ok    bool
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`, true},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`, true},
+		{"remove member", `{"a":"b"}`, `{"a":null}`, `{}`, true},
+		{"remove one of two", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`, true},
+		{"array replaced", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`, true},
+		{"value replaced by array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`, true},
+		{"nested merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`, true},
+		{"array of objects replaced", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`, true},
+		{"arrays not merged", `["a","b"]`, `["c","d"]`, `["c","d"]`, true},
+		{"object replaced by array", `{"a":"b"}`, `["c"]`, `["c"]`, true},
+		{"patch null", `{"a":"foo"}`, `null`, `null`, true},
+		{"patch string", `{"a":"foo"}`, `"bar"`, `"bar"`, true},
+		{"null member kept in doc", `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`, true},
+		{"scalar doc", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`, true},
+		{"nested null creates nothing", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`, true},
+		{"empty doc", ``, `{"a":1}`, `{"a":1}`, true},
+		{"large numbers kept", `{"a":1}`, `{"b":12345678901234567890}`, `{"a":1,"b":12345678901234567890}`, true},
+		{"invalid patch", `{"a":1}`, `{"a":`, ``, false},
+		{"invalid doc", `{"a":`, `{"a":1}`, ``, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected patch to apply, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected an error, got %s", r)
+			case !tt.ok:
+				return
+			}
+			want, err := decodeJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, err := json.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(r) != string(w) {
+				t.Fatalf("expected %s, got %s", w, r)
+			}
+		})
+	}
+}
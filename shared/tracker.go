@@ -0,0 +1,21 @@
+package shared
+
+// Tracker - records step level progress of long running operations.
+type Tracker interface {
+	// Start - marks step as running.
+	Start(step string)
+	// Done - marks the most recent running step as complete or failed.
+	Done(step string, err error)
+}
+
+// Track starts step on t and returns a function that completes it. Safe to
+// call with a nil tracker.
+func Track(t Tracker, step string) func(error) {
+	if t == nil {
+		return func(error) {}
+	}
+	t.Start(step)
+	return func(err error) {
+		t.Done(step, err)
+	}
+}
@@ -0,0 +1,72 @@
+package shared
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+// recorder - tracker that records calls.
+type recorder struct {
+	calls []string
+}
+
+func (o *recorder) Start(step string) {
+	o.calls = append(o.calls, "start "+step)
+}
+
+func (o *recorder) Done(step string, err error) {
+	if err != nil {
+		o.calls = append(o.calls, "fail "+step)
+		return
+	}
+	o.calls = append(o.calls, "done "+step)
+}
+
+func TestTrack(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls []string
+	}{
+		{"complete", nil, []string{"start dns", "done dns"}},
+		{"failed", errors.New("timeout"), []string{"start dns", "fail dns"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := new(recorder)
+			done := Track(r, "dns")
+			done(tt.err)
+			if len(r.calls) != len(tt.calls) {
+				t.Fatalf("expected %v, got %v", tt.calls, r.calls)
+			}
+			for k := range tt.calls {
+				if r.calls[k] != tt.calls[k] {
+					t.Fatalf("expected %v, got %v", tt.calls, r.calls)
+				}
+			}
+		})
+	}
+	t.Run("nil tracker", func(t *testing.T) {
+		Track(nil, "dns")(nil)
+	})
+}
+
+func TestNewUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		r, err := NewUUID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !re.MatchString(r) {
+			t.Fatalf("expected a version 4 uuid, got %s", r)
+		}
+		if seen[r] {
+			t.Fatalf("duplicate uuid %s", r)
+		}
+		seen[r] = true
+	}
+}
@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError - invalid field in a payload.
+type FieldError struct {
+	// Path - json path of the field, e.g. data.pools[0].bindings[1].port.
+	Path string `json:"path"`
+	// Reason - why the value was rejected.
+	Reason string `json:"reason"`
+	// Allowed - accepted values when the field is limited to a set.
+	Allowed []string `json:"allowed,omitempty"`
+}
+
+// ValidationError - returned when a payload fails validation. Lists every
+// invalid field rather than stopping at the first.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error - summarizes the invalid fields.
+func (e *ValidationError) Error() string {
+	var fields []string
+	for _, v := range e.Fields {
+		fields = append(fields, v.Path+": "+v.Reason)
+	}
+	return "payload did not pass validation - " + strings.Join(fields, "; ")
+}
+
+// NewValidationError - returns nil when fields is empty so the result can be
+// returned as an error directly.
+func NewValidationError(fields []FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// JSONPath - joins a field or index to a json path.
+func JSONPath(path string, field interface{}) string {
+	if i, ok := field.(int); ok {
+		return fmt.Sprintf("%s[%d]", path, i)
+	}
+	if path == "" {
+		return fmt.Sprint(field)
+	}
+	return fmt.Sprintf("%s.%v", path, field)
+}
+
+// CheckRequired - returns an error when the field is unset.
+func CheckRequired(path string, set bool) []FieldError {
+	if set {
+		return nil
+	}
+	return []FieldError{{Path: path, Reason: "required"}}
+}
+
+// CheckEnum - returns an error when value is set and is not one of allowed.
+func CheckEnum(path string, value string, allowed []string) []FieldError {
+	if value == "" {
+		return nil
+	}
+	for _, v := range allowed {
+		if v == value {
+			return nil
+		}
+	}
+	return []FieldError{{Path: path, Reason: fmt.Sprintf("%q is not supported", value), Allowed: allowed}}
+}
+
+// CheckRange - returns an error when value is outside min and max.
+func CheckRange(path string, value int, min int, max int) []FieldError {
+	if value >= min && value <= max {
+		return nil
+	}
+	return []FieldError{{Path: path, Reason: fmt.Sprintf("must be between %d and %d", min, max)}}
+}
+
+// MarshalValidate - MarshalInterface that reports type mismatches as a
+// ValidationError against the field under path.
+func MarshalValidate(path string, in interface{}, out interface{}) (err error) {
+	err = MarshalInterface(in, out)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &ValidationError{Fields: []FieldError{{
+			Path:   JSONPath(path, typeErr.Field),
+			Reason: fmt.Sprintf("must be %s, not %s", typeErr.Type.String(), typeErr.Value),
+		}}}
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		field interface{}
+		want  string
+	}{
+		{"root field", "", "name", "name"},
+		{"nested field", "data", "name", "data.name"},
+		{"index", "data.pools", 0, "data.pools[0]"},
+		{"field of index", "data.pools[0]", "bindings", "data.pools[0].bindings"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := JSONPath(tt.path, tt.field); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	allowed := []string{"http", "https"}
+	tests := []struct {
+		name   string
+		fields []FieldError
+		reason string
+	}{
+		{"required set", CheckRequired("name", true), ""},
+		{"required unset", CheckRequired("name", false), "required"},
+		{"enum allowed", CheckEnum("service_type", "http", allowed), ""},
+		{"enum empty", CheckEnum("service_type", "", allowed), ""},
+		{"enum not allowed", CheckEnum("service_type", "ftp", allowed), `"ftp" is not supported`},
+		{"range low bound", CheckRange("port", 1, 1, 65535), ""},
+		{"range high bound", CheckRange("port", 65535, 1, 65535), ""},
+		{"range below", CheckRange("port", 0, 1, 65535), "must be between 1 and 65535"},
+		{"range above", CheckRange("port", 65536, 1, 65535), "must be between 1 and 65535"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.reason == "" {
+				if len(tt.fields) != 0 {
+					t.Fatalf("expected no errors, got %+v", tt.fields)
+				}
+				return
+			}
+			if len(tt.fields) != 1 || tt.fields[0].Reason != tt.reason {
+				t.Fatalf("expected %q, got %+v", tt.reason, tt.fields)
+			}
+		})
+	}
+}
+
+func TestMarshalValidate(t *testing.T) {
+	type port struct {
+		Port int `json:"port"`
+	}
+	type data struct {
+		Name  string `json:"name"`
+		Ports []port `json:"ports"`
+	}
+	tests := []struct {
+		name string
+		in   interface{}
+		// path - prefix and suffix of the reported path. Newer go releases
+		// include the array index.
+		path [2]string
+	}{
+		{"valid", map[string]interface{}{"name": "prd1-vip", "ports": []interface{}{map[string]interface{}{"port": 80}}}, [2]string{}},
+		{"string for int", map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": "80"}}}, [2]string{"data.ports.", ".port"}},
+		{"number for string", map[string]interface{}{"name": 1}, [2]string{"data.name", "data.name"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out data
+			err := MarshalValidate("data", tt.in, &out)
+			if tt.path[0] == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var v *ValidationError
+			if !errors.As(err, &v) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if len(v.Fields) != 1 || !strings.HasPrefix(v.Fields[0].Path, tt.path[0]) || !strings.HasSuffix(v.Fields[0].Path, tt.path[1]) {
+				t.Fatalf("expected path %s...%s, got %+v", tt.path[0], tt.path[1], v.Fields)
+			}
+		})
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	if NewValidationError(nil) != nil {
+		t.Fatal("expected nil for no fields")
+	}
+	err := NewValidationError([]FieldError{{Path: "name", Reason: "required"}, {Path: "ip", Reason: "must be an ipv4 address"}})
+	if err == nil || err.Error() != "payload did not pass validation - name: required; ip: must be an ipv4 address" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
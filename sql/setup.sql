@@ -39,9 +39,12 @@ CREATE TABLE public.virtualservers (
   md5hash text,
   last_error varchar,
   last_modified_by varchar,
+  alias varchar,
   CONSTRAINT virtualservers_pkey PRIMARY KEY (id)
 ) WITH (OIDS = FALSE) TABLESPACE pg_default;
 ALTER TABLE public.virtualservers OWNER to postgres;
+CREATE INDEX virtualservers_alias_idx ON public.virtualservers (alias);
+CREATE INDEX virtualservers_data_idx ON public.virtualservers USING gin (data jsonb_path_ops);
 -------------------------------------------------------
 -- Table: public.migrate
 -------------------------------------------------------
@@ -92,6 +95,158 @@ CREATE TABLE public.status (
 ) WITH (OIDS = FALSE) TABLESPACE pg_default;
 ALTER TABLE public.status OWNER to postgres;
 -------------------------------------------------------
+-- Table: public.operations
+-------------------------------------------------------
+CREATE TABLE public.operations (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT operations_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.operations OWNER to postgres;
+-------------------------------------------------------
+-- Table: public.audit
+-------------------------------------------------------
+CREATE TABLE public.audit (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT audit_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.audit OWNER to postgres;
+CREATE INDEX audit_last_modified_idx ON public.audit (last_modified);
+CREATE INDEX audit_record_id_idx ON public.audit ((data->>'record_id'));
+-------------------------------------------------------
+-- Audit records are append only.
+-------------------------------------------------------
+CREATE FUNCTION public.audit_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit records cannot be changed or removed';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_immutable BEFORE UPDATE OR DELETE ON public.audit
+  FOR EACH ROW EXECUTE PROCEDURE public.audit_immutable();
+CREATE TRIGGER audit_immutable_truncate BEFORE TRUNCATE ON public.audit
+  FOR EACH STATEMENT EXECUTE PROCEDURE public.audit_immutable();
+-------------------------------------------------------
+-- Table: public.secrets
+-------------------------------------------------------
+CREATE TABLE public.secrets (
+  id varchar,
+  data text,
+  last_modified timestamptz,
+  last_modified_by varchar,
+  CONSTRAINT secrets_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.secrets OWNER to postgres;
+CREATE INDEX audit_versions_idx ON public.audit ((data->>'route'), (data->>'record_id'), last_modified);
+-------------------------------------------------------
+-- Table: public.schedule
+-------------------------------------------------------
+CREATE TABLE public.schedule (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT schedule_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.schedule OWNER to postgres;
+CREATE INDEX schedule_due_idx ON public.schedule ((data->>'status'), (data->>'apply_at'));
+-------------------------------------------------------
+-- Table: public.approvals
+-------------------------------------------------------
+CREATE TABLE public.approvals (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT approvals_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.approvals OWNER to postgres;
+CREATE INDEX approvals_status_idx ON public.approvals ((data->>'status'));
+-------------------------------------------------------
+-- Table: public.serviceaccounts
+-------------------------------------------------------
+CREATE TABLE public.serviceaccounts (
+  name varchar,
+  description text,
+  owners text[],
+  created timestamptz,
+  created_by varchar,
+  CONSTRAINT serviceaccounts_pkey PRIMARY KEY (name)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.serviceaccounts OWNER to postgres;
+-------------------------------------------------------
+-- Table: public.apitokens
+-------------------------------------------------------
+CREATE TABLE public.apitokens (
+  id varchar,
+  service_account varchar,
+  description text,
+  secret_hash varchar,
+  product_codes integer[],
+  permissions text[],
+  expires timestamptz,
+  created timestamptz,
+  created_by varchar,
+  revoked timestamptz,
+  revoked_by varchar,
+  last_used timestamptz,
+  CONSTRAINT apitokens_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.apitokens OWNER to postgres;
+CREATE INDEX apitokens_service_account_idx ON public.apitokens (service_account);
+-------------------------------------------------------
+-- Table: public.idempotency
+-------------------------------------------------------
+CREATE TABLE public.idempotency (
+  id varchar,
+  request_hash text,
+  status_code integer,
+  data jsonb,
+  source varchar,
+  last_modified timestamptz,
+  last_modified_by varchar,
+  CONSTRAINT idempotency_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.idempotency OWNER to postgres;
+-------------------------------------------------------
+-- Table: public.webhook_deadletter
+-------------------------------------------------------
+CREATE TABLE public.webhook_deadletter (
+  id varchar,
+  url varchar,
+  event_type varchar,
+  data jsonb,
+  attempts integer,
+  last_error varchar,
+  last_modified timestamptz
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.webhook_deadletter OWNER to postgres;
+CREATE INDEX webhook_deadletter_id_idx ON public.webhook_deadletter (id);
+-------------------------------------------------------
 -- Table: public.status_description
 -------------------------------------------------------
 CREATE TABLE public.statusdescription (
@@ -0,0 +1,17 @@
+-------------------------------------------------------
+-- Changes to protected product codes waiting for approval.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.approvals (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT approvals_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.approvals OWNER to postgres;
+CREATE INDEX IF NOT EXISTS approvals_status_idx ON public.approvals ((data->>'status'));
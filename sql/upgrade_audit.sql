@@ -0,0 +1,32 @@
+-------------------------------------------------------
+-- Append only audit log.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.audit (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT audit_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.audit OWNER to postgres;
+CREATE INDEX IF NOT EXISTS audit_last_modified_idx ON public.audit (last_modified);
+CREATE INDEX IF NOT EXISTS audit_record_id_idx ON public.audit ((data->>'record_id'));
+-------------------------------------------------------
+-- Audit records are append only.
+-------------------------------------------------------
+CREATE OR REPLACE FUNCTION public.audit_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit records cannot be changed or removed';
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS audit_immutable ON public.audit;
+CREATE TRIGGER audit_immutable BEFORE UPDATE OR DELETE ON public.audit
+  FOR EACH ROW EXECUTE PROCEDURE public.audit_immutable();
+DROP TRIGGER IF EXISTS audit_immutable_truncate ON public.audit;
+CREATE TRIGGER audit_immutable_truncate BEFORE TRUNCATE ON public.audit
+  FOR EACH STATEMENT EXECUTE PROCEDURE public.audit_immutable();
@@ -0,0 +1,14 @@
+-------------------------------------------------------
+-- Idempotency-Key responses of create requests.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.idempotency (
+  id varchar,
+  request_hash text,
+  status_code integer,
+  data jsonb,
+  source varchar,
+  last_modified timestamptz,
+  last_modified_by varchar,
+  CONSTRAINT idempotency_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.idempotency OWNER to postgres;
@@ -0,0 +1,16 @@
+-------------------------------------------------------
+-- Progress of asynchronous load balancer changes.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.operations (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT operations_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.operations OWNER to postgres;
@@ -0,0 +1,5 @@
+-------------------------------------------------------
+-- GIN index serving the jsonpath filters on virtualserver
+-- data, e.g. pools.bindings.server.ip=10.1.2.3.
+-------------------------------------------------------
+CREATE INDEX IF NOT EXISTS virtualservers_data_idx ON public.virtualservers USING gin (data jsonb_path_ops);
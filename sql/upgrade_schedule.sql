@@ -0,0 +1,17 @@
+-------------------------------------------------------
+-- Modify and delete requests scheduled for later.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.schedule (
+  id varchar,
+  data jsonb,
+  load_balancer_ip varchar,
+  load_balancer jsonb,
+  last_modified timestamptz,
+  source varchar,
+  md5hash text,
+  last_error varchar,
+  last_modified_by varchar,
+  CONSTRAINT schedule_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.schedule OWNER to postgres;
+CREATE INDEX IF NOT EXISTS schedule_due_idx ON public.schedule ((data->>'status'), (data->>'apply_at'));
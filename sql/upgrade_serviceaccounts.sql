@@ -0,0 +1,32 @@
+-------------------------------------------------------
+-- Service accounts and their API tokens.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.serviceaccounts (
+  name varchar,
+  description text,
+  owners text[],
+  created timestamptz,
+  created_by varchar,
+  CONSTRAINT serviceaccounts_pkey PRIMARY KEY (name)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.serviceaccounts OWNER to postgres;
+-------------------------------------------------------
+-- Table: public.apitokens
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.apitokens (
+  id varchar,
+  service_account varchar,
+  description text,
+  secret_hash varchar,
+  product_codes integer[],
+  permissions text[],
+  expires timestamptz,
+  created timestamptz,
+  created_by varchar,
+  revoked timestamptz,
+  revoked_by varchar,
+  last_used timestamptz,
+  CONSTRAINT apitokens_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.apitokens OWNER to postgres;
+CREATE INDEX IF NOT EXISTS apitokens_service_account_idx ON public.apitokens (service_account);
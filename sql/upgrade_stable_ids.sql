@@ -0,0 +1,7 @@
+-------------------------------------------------------
+-- Stable virtualserver ids. Existing records keep their
+-- md5 id, which also becomes their alias.
+-------------------------------------------------------
+ALTER TABLE public.virtualservers ADD COLUMN IF NOT EXISTS alias varchar;
+UPDATE public.virtualservers SET alias = id WHERE alias IS NULL;
+CREATE INDEX IF NOT EXISTS virtualservers_alias_idx ON public.virtualservers (alias);
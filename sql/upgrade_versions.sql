@@ -0,0 +1,15 @@
+-------------------------------------------------------
+-- Encrypted certificate private keys restored on rollback.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.secrets (
+  id varchar,
+  data text,
+  last_modified timestamptz,
+  last_modified_by varchar,
+  CONSTRAINT secrets_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.secrets OWNER to postgres;
+-------------------------------------------------------
+-- Version history is read from the audit log.
+-------------------------------------------------------
+CREATE INDEX IF NOT EXISTS audit_versions_idx ON public.audit ((data->>'route'), (data->>'record_id'), last_modified);
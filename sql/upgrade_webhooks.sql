@@ -0,0 +1,14 @@
+-------------------------------------------------------
+-- Webhook events that could not be delivered.
+-------------------------------------------------------
+CREATE TABLE IF NOT EXISTS public.webhook_deadletter (
+  id varchar,
+  url varchar,
+  event_type varchar,
+  data jsonb,
+  attempts integer,
+  last_error varchar,
+  last_modified timestamptz
+) WITH (OIDS = FALSE) TABLESPACE pg_default;
+ALTER TABLE public.webhook_deadletter OWNER to postgres;
+CREATE INDEX IF NOT EXISTS webhook_deadletter_id_idx ON public.webhook_deadletter (id);
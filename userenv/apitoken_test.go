@@ -0,0 +1,100 @@
+package userenv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestTokenCodes(t *testing.T) {
+	scope := &TokenScope{ProductCodes: []string{"2", "1"}, Permissions: []string{"fetch", "Modify"}}
+	tests := []struct {
+		action string
+		want   []string
+	}{
+		{ActionFetch, []string{"1", "2"}},
+		{ActionModify, []string{"1", "2"}},
+		{ActionDelete, nil},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if r := scope.tokenCodes(tt.action); !reflect.DeepEqual(r, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestTokenUser(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{Lbm: config.Lbm{AdminGroup: "lbadmins"}}
+	scope := &TokenScope{ServiceAccount: "ci", ProductCodes: []string{"1"}, Permissions: []string{ActionFetch}}
+	tests := []struct {
+		name  string
+		user  *User
+		human bool
+		admin bool
+		all   bool
+	}{
+		{"human", &User{Group: []string{"lbadmins"}}, true, true, true},
+		{"api token ignores groups", &User{Group: []string{"lbadmins"}, Token: scope}, false, false, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.user.IsHuman(); (err == nil) != tt.human {
+				t.Fatalf("expected human %v, got %v", tt.human, err)
+			}
+			if err := tt.user.IsAdmin(); (err == nil) != tt.admin {
+				t.Fatalf("expected admin %v, got %v", tt.admin, err)
+			}
+			if _, all := tt.user.Codes(ActionFetch); all != tt.all {
+				t.Fatalf("expected all %v, got %v", tt.all, all)
+			}
+		})
+	}
+}
+
+func TestTokenScope(t *testing.T) {
+	defer func(f func(string) (TokenScope, error)) { LookupToken = f }(LookupToken)
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		lookup func(string) (TokenScope, error)
+		token  string
+		scope  bool
+		ok     bool
+	}{
+		{"no token", nil, "", false, true},
+		{"jwt", nil, "Bearer a.b.c", false, true},
+		{"lookup disabled", nil, "Bearer " + TokenPrefix + "id_secret", false, false},
+		{"unknown token", func(string) (TokenScope, error) { return TokenScope{}, errors.New("not found") }, "Bearer " + TokenPrefix + "id_secret", false, false},
+		{"valid token", func(string) (TokenScope, error) { return TokenScope{TokenID: "id"}, nil }, "Bearer " + TokenPrefix + "id_secret", true, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			LookupToken = tt.lookup
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				c.Request.Header.Set("Authorization", tt.token)
+			}
+			r, err := tokenScope(c)
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected no error, got %v", err)
+			case !tt.ok && !errors.Is(err, ErrInvalidToken):
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			case (r != nil) != tt.scope:
+				t.Fatalf("expected scope %v, got %+v", tt.scope, r)
+			}
+		})
+	}
+}
@@ -8,6 +8,12 @@ type User struct {
 	Username string
 	Error    error
 	Context  *gin.Context
+	// ApprovedBy - second user that approved the change being made on behalf
+	// of this user.
+	ApprovedBy string
+	// Token - scope of the API token the user authenticated with. Nil for
+	// sessions and JWTs.
+	Token *TokenScope
 }
 
 type Login struct {
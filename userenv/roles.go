@@ -0,0 +1,239 @@
+package userenv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+// Actions permissions are granted for.
+const (
+	ActionFetch   = "fetch"
+	ActionCreate  = "create"
+	ActionModify  = "modify"
+	ActionDelete  = "delete"
+	ActionMigrate = "migrate"
+	ActionImport  = "import"
+	ActionBackup  = "backup"
+)
+
+// Built-in roles.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleMigrator = "migrator"
+	RoleAdmin    = "admin"
+)
+
+// builtinPermissions - actions of the built-in roles, unless Roles.Permissions
+// says otherwise.
+var builtinPermissions = map[string][]string{
+	RoleViewer:   {ActionFetch},
+	RoleOperator: {ActionFetch, ActionCreate, ActionModify, ActionDelete, ActionMigrate, ActionImport},
+	RoleMigrator: {ActionFetch, ActionMigrate},
+	RoleAdmin:    {ActionFetch, ActionCreate, ActionModify, ActionDelete, ActionMigrate, ActionImport, ActionBackup},
+}
+
+// mappingCache - compiled Group expressions of role mappings.
+var mappingCache sync.Map
+
+// grant - product codes a role is held for.
+type grant struct {
+	all   bool
+	codes map[string]bool
+}
+
+// HasRight - returns an error unless one of the user's roles allows action on
+// assets of the product code.
+func (o *User) HasRight(action string, code string) (err error) {
+	return o.HasAnyRight(code, action)
+}
+
+// HasAnyRight - returns an error unless one of the user's roles allows any of
+// actions on assets of the product code.
+func (o *User) HasAnyRight(code string, actions ...string) (err error) {
+	if code == "" {
+		return errors.New("you did not provide a product code for this asset")
+	}
+	for _, action := range actions {
+		codes, all := o.Codes(action)
+		if all {
+			return nil
+		}
+		for _, v := range codes {
+			if v == code {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w. product code: %s - %s", ErrNotAuthorized, code, strings.Join(actions, ", "))
+}
+
+// HasPermission - returns an error unless one of the user's roles allows
+// action on at least one product code.
+func (o *User) HasPermission(action string) (err error) {
+	codes, all := o.Codes(action)
+	if all || len(codes) > 0 {
+		return nil
+	}
+	return fmt.Errorf("%w. none of your roles allow %s", ErrNotAuthorized, action)
+}
+
+// Codes - returns the product codes the user's roles allow action on. all is
+// true when a role allows it on every product code.
+func (o *User) Codes(action string) (codes []string, all bool) {
+	if o.Token != nil {
+		return o.Token.tokenCodes(action), false
+	}
+	seen := make(map[string]bool)
+	for role, g := range o.grants() {
+		if !permits(role, action) {
+			continue
+		}
+		if g.all {
+			return nil, true
+		}
+		for code := range g.codes {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes, false
+}
+
+// IsAdmin - returns an error unless the user holds the admin role for every
+// product code. API tokens never do.
+func (o *User) IsAdmin() (err error) {
+	if o.Token != nil {
+		return fmt.Errorf("%w. api tokens cannot hold the admin role", ErrNotAuthorized)
+	}
+	mappings, _, _ := roleMappings()
+	mapped := false
+	for _, m := range mappings {
+		mapped = mapped || m.Role == RoleAdmin
+	}
+	if !mapped {
+		return fmt.Errorf("%w. no group is mapped to the admin role", ErrNotAuthorized)
+	}
+	if g := o.grants()[RoleAdmin]; g != nil && g.all {
+		return nil
+	}
+	return fmt.Errorf("%w. admin group membership is required", ErrNotAuthorized)
+}
+
+// grants - returns the roles the groups of the user map to.
+func (o *User) grants() (r map[string]*grant) {
+	////////////////////////////////////////////////////////////////////////////
+	r = make(map[string]*grant)
+	add := func(role string, code string) {
+		g, ok := r[role]
+		if !ok {
+			g = &grant{codes: make(map[string]bool)}
+			r[role] = g
+		}
+		if code == "" {
+			g.all = true
+			return
+		}
+		g.codes[code] = true
+	}
+	mappings, defaultRole, builtin := roleMappings()
+	if defaultRole != "" {
+		add(defaultRole, "")
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, m := range mappings {
+		re, err := mappingRegexp(m.Group, !builtin)
+		if err != nil {
+			continue
+		}
+		index := subexpIndex(re, "code")
+		for _, group := range o.Group {
+			match := re.FindStringSubmatch(strings.ToLower(group))
+			if match == nil {
+				continue
+			}
+			if index < 0 {
+				add(m.Role, "")
+				continue
+			}
+			add(m.Role, match[index])
+		}
+	}
+	return
+}
+
+// roleMappings - returns the configured role mappings and default role, or
+// the built-in ones when none are configured: prd<code>-operator and
+// prd<code>-limitedaccess are operators of the code, Lbm.AdminGroup is admin
+// and every user is a viewer. builtin is true for the built-in mappings,
+// which match anywhere in a group as they always have, e.g. in the CN of a
+// group given as a DN.
+func roleMappings() (r []config.RoleMapping, defaultRole string, builtin bool) {
+	roles := config.GlobalConfig.Roles
+	if len(roles.Mappings) > 0 {
+		return roles.Mappings, roles.DefaultRole, false
+	}
+	r = []config.RoleMapping{
+		{Group: "prd{code}-operator", Role: RoleOperator},
+		{Group: "prd{code}-limitedaccess", Role: RoleOperator},
+	}
+	if admin := config.GlobalConfig.Lbm.AdminGroup; admin != "" {
+		r = append(r, config.RoleMapping{Group: admin, Role: RoleAdmin})
+	}
+	return r, RoleViewer, true
+}
+
+// permits - returns true when role allows action.
+func permits(role string, action string) bool {
+	actions, ok := config.GlobalConfig.Roles.Permissions[role]
+	if !ok {
+		actions = builtinPermissions[role]
+	}
+	for _, v := range actions {
+		if strings.EqualFold(v, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingRegexp - compiles the Group expression of a role mapping. {code}
+// matches the product code. Matching is case insensitive, and an anchored
+// expression must match the whole group.
+func mappingRegexp(group string, anchored bool) (r *regexp.Regexp, err error) {
+	if group == "" {
+		return nil, errors.New("role mapping has no group")
+	}
+	expr := "(?i)" + strings.Replace(group, "{code}", `(?P<code>\d+)`, -1)
+	if anchored {
+		expr = "(?i)^(?:" + strings.Replace(group, "{code}", `(?P<code>\d+)`, -1) + ")$"
+	}
+	if v, ok := mappingCache.Load(expr); ok {
+		return v.(*regexp.Regexp), nil
+	}
+	r, err = regexp.Compile(expr)
+	if err != nil {
+		return
+	}
+	mappingCache.Store(expr, r)
+	return
+}
+
+// subexpIndex - returns the index of the group named name in re, or -1.
+func subexpIndex(re *regexp.Regexp, name string) int {
+	for k, v := range re.SubexpNames() {
+		if v == name {
+			return k
+		}
+	}
+	return -1
+}
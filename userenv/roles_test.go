@@ -0,0 +1,150 @@
+package userenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestMappingRegexp(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    string
+		anchored bool
+		input    string
+		code     string
+		match    bool
+	}{
+		{"anchored code", "prd{code}-operator", true, "prd1234-operator", "1234", true},
+		{"anchored rejects prefix", "prd{code}-operator", true, "xprd1234-operator", "", false},
+		{"anchored rejects suffix", "prd{code}-operator", true, "prd1234-operator-old", "", false},
+		{"anchored alternation", "a|b", true, "ab", "", false},
+		{"substring in a dn", "prd{code}-operator", false, "cn=prd1234-operator,ou=groups", "1234", true},
+		{"case insensitive", "PRD{code}-Operator", true, "prd1234-operator", "1234", true},
+		{"no code", "lbadmins", true, "lbadmins", "", true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := mappingRegexp(tt.group, tt.anchored)
+			if err != nil {
+				t.Fatal(err)
+			}
+			match := re.FindStringSubmatch(tt.input)
+			if (match != nil) != tt.match {
+				t.Fatalf("expected match %v, got %v", tt.match, match)
+			}
+			if index := subexpIndex(re, "code"); tt.code != "" && match[index] != tt.code {
+				t.Fatalf("expected code %s, got %s", tt.code, match[index])
+			}
+		})
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if _, err := mappingRegexp("", true); err == nil {
+		t.Fatal("expected an error for an empty group")
+	}
+	if _, err := mappingRegexp("(", true); err == nil {
+		t.Fatal("expected an error for an invalid group")
+	}
+}
+
+func TestCodes(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	configured := config.Roles{
+		Mappings: []config.RoleMapping{
+			{Group: "prd{code}-operator", Role: RoleOperator},
+			{Group: "lb-migrators", Role: RoleMigrator},
+		},
+		Permissions: map[string][]string{RoleMigrator: {ActionMigrate}},
+	}
+	tests := []struct {
+		name   string
+		roles  config.Roles
+		admin  string
+		groups []string
+		action string
+		codes  []string
+		all    bool
+	}{
+		{"built-in viewer fetches everything", config.Roles{}, "", nil, ActionFetch, nil, true},
+		{"built-in operator", config.Roles{}, "", []string{"prd2-operator", "prd1-limitedaccess"}, ActionModify, []string{"1", "2"}, false},
+		{"built-in substring match", config.Roles{}, "", []string{"CN=prd1-operator,OU=Groups"}, ActionModify, []string{"1"}, false},
+		{"built-in admin", config.Roles{}, "lbadmins", []string{"lbadmins"}, ActionBackup, nil, true},
+		{"built-in no rights", config.Roles{}, "", []string{"prd1-viewer"}, ActionDelete, nil, false},
+		{"configured mappings are anchored", configured, "", []string{"CN=prd1-operator,OU=Groups"}, ActionModify, nil, false},
+		{"configured operator", configured, "", []string{"prd1-operator"}, ActionModify, []string{"1"}, false},
+		{"configured without a default role", configured, "", nil, ActionFetch, nil, false},
+		{"configured permissions", configured, "", []string{"lb-migrators"}, ActionMigrate, nil, true},
+		{"configured permissions replace built-in ones", configured, "", []string{"lb-migrators"}, ActionFetch, nil, false},
+		{"configured default role", config.Roles{Mappings: configured.Mappings, DefaultRole: RoleViewer}, "", nil, ActionFetch, nil, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Roles: tt.roles, Lbm: config.Lbm{AdminGroup: tt.admin}}
+			codes, all := (&User{Group: tt.groups}).Codes(tt.action)
+			if all != tt.all || !reflect.DeepEqual(codes, tt.codes) {
+				t.Fatalf("expected %v/%v, got %v/%v", tt.codes, tt.all, codes, all)
+			}
+		})
+	}
+}
+
+func TestHasRight(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	config.GlobalConfig = &config.Setting{Lbm: config.Lbm{AdminGroup: "lbadmins"}}
+	operator := &User{Group: []string{"prd1-operator"}}
+	admin := &User{Group: []string{"lbadmins"}}
+	tests := []struct {
+		name   string
+		user   *User
+		action string
+		code   string
+		ok     bool
+	}{
+		{"own code", operator, ActionModify, "1", true},
+		{"other code", operator, ActionModify, "2", false},
+		{"viewer fetch", operator, ActionFetch, "2", true},
+		{"backup needs admin", operator, ActionBackup, "1", false},
+		{"admin", admin, ActionBackup, "2", true},
+		{"no code", admin, ActionFetch, "", false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.user.HasRight(tt.action, tt.code)
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected ok %v, got %v", tt.ok, err)
+			}
+			if err != nil && tt.code != "" && !errors.Is(err, ErrNotAuthorized) {
+				t.Fatalf("expected ErrNotAuthorized, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	defer func(c *config.Setting) { config.GlobalConfig = c }(config.GlobalConfig)
+	tests := []struct {
+		name   string
+		admin  string
+		groups []string
+		ok     bool
+	}{
+		{"admin", "lbadmins", []string{"lbadmins"}, true},
+		{"not a member", "lbadmins", []string{"prd1-operator"}, false},
+		{"no admin group", "", []string{"lbadmins"}, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Setting{Lbm: config.Lbm{AdminGroup: tt.admin}}
+			err := (&User{Group: tt.groups}).IsAdmin()
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected ok %v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package userenv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	b64 "encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/config"
+)
+
+// ErrInvalidToken - returned when a bearer token fails verification.
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// claimsKey - context key the claims of a verified token are kept under.
+const claimsKey = "userenv.claims"
+
+// signingMethods - algorithms tokens may be signed with. Hmac and none are
+// never accepted, so a public key cannot be used as a shared secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+var (
+	staticKey = &pemKey{}
+	jwks      = &keySet{}
+)
+
+// VerifyToken - checks the signature of token against the configured keys and
+// its exp, nbf, iss and aud claims, and returns its claims.
+func VerifyToken(token string) (claims jwt.MapClaims, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	setting := config.GlobalConfig.Token
+	parser := &jwt.Parser{ValidMethods: signingMethods, SkipClaimsValidation: true}
+	claims = jwt.MapClaims{}
+	_, err = parser.ParseWithClaims(token, claims, verificationKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %v", ErrInvalidToken, err)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Test - Claims.
+	////////////////////////////////////////////////////////////////////////////
+	leeway := int64(setting.LeewaySeconds)
+	now := time.Now().Unix()
+	switch {
+	case !claims.VerifyExpiresAt(now-leeway, true):
+		err = fmt.Errorf("%w - token is expired or has no exp claim", ErrInvalidToken)
+	case !claims.VerifyNotBefore(now+leeway, false):
+		err = fmt.Errorf("%w - token is not valid yet", ErrInvalidToken)
+	case setting.Issuer != "" && !claims.VerifyIssuer(setting.Issuer, true):
+		err = fmt.Errorf("%w - token was not issued by %s", ErrInvalidToken, setting.Issuer)
+	case len(setting.Audience) > 0 && !verifyAudience(claims, setting.Audience):
+		err = fmt.Errorf("%w - token is not meant for %s", ErrInvalidToken, strings.Join(setting.Audience, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// bearerToken - returns the bearer token of the request.
+func bearerToken(c *gin.Context) (token string, ok bool) {
+	authString := c.Request.Header.Get("Authorization")
+	if authString == "" || !strings.Contains(authString, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Replace(authString, "Bearer ", "", 1)), true
+}
+
+// tokenClaims - returns the verified claims of the bearer token of the
+// request, verifying it once per request.
+func tokenClaims(c *gin.Context) (claims jwt.MapClaims, err error) {
+	if v, ok := c.Get(claimsKey); ok {
+		return v.(jwt.MapClaims), nil
+	}
+	token, _ := bearerToken(c)
+	claims, err = VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(claimsKey, claims)
+	return claims, nil
+}
+
+// verifyAudience - returns true when the aud claim, a string or an array,
+// holds any of audience.
+func verifyAudience(claims jwt.MapClaims, audience []string) bool {
+	var aud []string
+	switch v := claims["aud"].(type) {
+	case string:
+		aud = append(aud, v)
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				aud = append(aud, s)
+			}
+		}
+	}
+	for _, a := range aud {
+		for _, v := range audience {
+			if a == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verificationKey - returns the key token is verified with. Keys of the JWKS
+// are matched on the kid header, falling back to the PEM key.
+func verificationKey(token *jwt.Token) (key interface{}, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	setting := config.GlobalConfig.Token
+	if setting.JWKS == "" && setting.PublicKey == "" {
+		return nil, errors.New("no token verification keys are configured")
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if setting.JWKS != "" {
+		kid, _ := token.Header["kid"].(string)
+		key, err = jwks.key(setting.JWKS, kid, time.Duration(setting.CacheMinutes)*time.Minute)
+		if err == nil || setting.PublicKey == "" {
+			return
+		}
+	}
+	return staticKey.key(setting.PublicKey)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PEM.
+////////////////////////////////////////////////////////////////////////////////
+
+// pemKey - public key read from a PEM file, kept until the path changes.
+type pemKey struct {
+	sync.Mutex
+	path   string
+	public interface{}
+}
+
+// key - returns the RSA or ECDSA public key in the PEM file at path.
+func (o *pemKey) key(path string) (r interface{}, err error) {
+	o.Lock()
+	defer o.Unlock()
+	if o.path == path && o.public != nil {
+		return o.public, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err = jwt.ParseRSAPublicKeyFromPEM(b)
+	if err != nil {
+		r, err = jwt.ParseECPublicKeyFromPEM(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s does not hold an RSA or ECDSA public key", path)
+	}
+	o.path = path
+	o.public = r
+	return r, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// JWKS.
+////////////////////////////////////////////////////////////////////////////////
+
+// keySet - keys read from a JSON Web Key Set file or url. Keys are read again
+// once they are older than the cache time, or when a token names an unknown
+// kid, at most once a minute.
+type keySet struct {
+	sync.Mutex
+	source  string
+	keys    map[string]interface{}
+	fetched time.Time
+}
+
+// jsonWebKey - RSA or EC key of a JSON Web Key Set.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// key - returns the key of source with kid. A token without a kid is
+// verified with the only key of the set.
+func (o *keySet) key(source string, kid string, cache time.Duration) (r interface{}, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.Lock()
+	defer o.Unlock()
+	if cache <= 0 {
+		cache = time.Hour
+	}
+	age := time.Since(o.fetched)
+	_, known := o.keys[kid]
+	if o.source != source || age > cache || (!known && age > time.Minute) {
+		keys, fetchErr := fetchKeySet(source)
+		switch {
+		case fetchErr == nil:
+			o.source, o.keys, o.fetched = source, keys, time.Now()
+		case o.source != source:
+			return nil, fetchErr
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	if kid == "" && len(o.keys) == 1 {
+		for _, v := range o.keys {
+			return v, nil
+		}
+	}
+	r, ok := o.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no key with kid %q in %s", kid, source)
+	}
+	return r, nil
+}
+
+// fetchKeySet - reads the keys of the JSON Web Key Set at source, a file or
+// an http(s) url.
+func fetchKeySet(source string) (r map[string]interface{}, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	var b []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s returned %s", source, resp.Status)
+		}
+		b, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		b, err = ioutil.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err = json.Unmarshal(b, &set)
+	if err != nil {
+		return nil, err
+	}
+	r = make(map[string]interface{})
+	for _, v := range set.Keys {
+		if v.Use != "" && v.Use != "sig" {
+			continue
+		}
+		key, err := v.public()
+		if err != nil {
+			continue
+		}
+		r[v.Kid] = key
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("%s holds no RSA or EC signing keys", source)
+	}
+	return r, nil
+}
+
+// public - returns the RSA or ECDSA public key of o.
+func (o jsonWebKey) public() (r interface{}, err error) {
+	switch o.Kty {
+	case "RSA":
+		n, err := decodeBigInt(o.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(o.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch o.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", o.Crv)
+		}
+		x, err := decodeBigInt(o.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(o.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", o.Kty)
+}
+
+// decodeBigInt - decodes a base64url encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := b64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
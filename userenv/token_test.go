@@ -0,0 +1,227 @@
+package userenv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	b64 "encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/ticketmaster/lbapi/config"
+)
+
+// testKeys - keys generated for a test run and the files holding their public
+// halves.
+type testKeys struct {
+	rsa, other *rsa.PrivateKey
+	ec         *ecdsa.PrivateKey
+	rsaPEM     string
+	ecPEM      string
+	jwks       string
+}
+
+// newTestKeys - generates RSA and ECDSA keys and writes a PEM file for each
+// and a JSON Web Key Set holding both to dir.
+func newTestKeys(t *testing.T, dir string) (r testKeys) {
+	t.Helper()
+	var err error
+	if r.rsa, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if r.other, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if r.ec, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	r.rsaPEM = writePublicKey(t, filepath.Join(dir, "rsa.pem"), &r.rsa.PublicKey)
+	r.ecPEM = writePublicKey(t, filepath.Join(dir, "ec.pem"), &r.ec.PublicKey)
+	////////////////////////////////////////////////////////////////////////////
+	encode := func(i *big.Int) string {
+		return b64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	set := map[string][]jsonWebKey{"keys": {
+		{Kty: "RSA", Kid: "rsa-1", Use: "sig", N: encode(r.rsa.N), E: encode(big.NewInt(int64(r.rsa.E)))},
+		{Kty: "EC", Kid: "ec-1", Use: "sig", Crv: "P-256", X: encode(r.ec.X), Y: encode(r.ec.Y)},
+	}}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.jwks = filepath.Join(dir, "jwks.json")
+	if err = ioutil.WriteFile(r.jwks, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// writePublicKey - writes key to path as a PEM encoded PKIX public key.
+func writePublicKey(t *testing.T, path string, key interface{}) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err = ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useToken - sets the token config and clears the cached keys.
+func useToken(setting config.Token) {
+	config.GlobalConfig = &config.Setting{Token: setting}
+	staticKey = &pemKey{}
+	jwks = &keySet{}
+}
+
+// sign - returns claims signed with key by method, with kid in the header
+// when set.
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVerifyToken(t *testing.T) {
+	////////////////////////////////////////////////////////////////////////////
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keys := newTestKeys(t, dir)
+	secret, err := ioutil.ReadFile(keys.rsaPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Unix()
+	valid := func(extra jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{"sub": "jdoe", "exp": now + 300}
+		for k, v := range extra {
+			claims[k] = v
+		}
+		return claims
+	}
+	pemRSA := config.Token{PublicKey: keys.rsaPEM}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name    string
+		setting config.Token
+		method  jwt.SigningMethod
+		key     interface{}
+		kid     string
+		claims  jwt.MapClaims
+		ok      bool
+	}{
+		{"rsa pem", pemRSA, jwt.SigningMethodRS256, keys.rsa, "", valid(nil), true},
+		{"rsa pss pem", pemRSA, jwt.SigningMethodPS256, keys.rsa, "", valid(nil), true},
+		{"ecdsa pem", config.Token{PublicKey: keys.ecPEM}, jwt.SigningMethodES256, keys.ec, "", valid(nil), true},
+		{"bad signature", pemRSA, jwt.SigningMethodRS256, keys.other, "", valid(nil), false},
+		{"alg none", pemRSA, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", valid(nil), false},
+		{"hs256 with the public key", pemRSA, jwt.SigningMethodHS256, secret, "", valid(nil), false},
+		{"no exp", pemRSA, jwt.SigningMethodRS256, keys.rsa, "", jwt.MapClaims{"sub": "jdoe"}, false},
+		{"expired", pemRSA, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"exp": now - 60}), false},
+		{"expired within leeway", config.Token{PublicKey: keys.rsaPEM, LeewaySeconds: 120}, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"exp": now - 60}), true},
+		{"nbf in the future", pemRSA, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"nbf": now + 60}), false},
+		{"nbf in the past", pemRSA, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"nbf": now - 60}), true},
+		{"iss match", config.Token{PublicKey: keys.rsaPEM, Issuer: "idp"}, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"iss": "idp"}), true},
+		{"iss mismatch", config.Token{PublicKey: keys.rsaPEM, Issuer: "idp"}, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"iss": "other"}), false},
+		{"no iss", config.Token{PublicKey: keys.rsaPEM, Issuer: "idp"}, jwt.SigningMethodRS256, keys.rsa, "", valid(nil), false},
+		{"aud match", config.Token{PublicKey: keys.rsaPEM, Audience: []string{"lbapi"}}, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"aud": []string{"other", "lbapi"}}), true},
+		{"aud mismatch", config.Token{PublicKey: keys.rsaPEM, Audience: []string{"lbapi"}}, jwt.SigningMethodRS256, keys.rsa, "", valid(jwt.MapClaims{"aud": "other"}), false},
+		{"jwks rsa kid", config.Token{JWKS: keys.jwks}, jwt.SigningMethodRS256, keys.rsa, "rsa-1", valid(nil), true},
+		{"jwks ec kid", config.Token{JWKS: keys.jwks}, jwt.SigningMethodES256, keys.ec, "ec-1", valid(nil), true},
+		{"jwks wrong kid", config.Token{JWKS: keys.jwks}, jwt.SigningMethodRS256, keys.rsa, "ec-1", valid(nil), false},
+		{"jwks unknown kid", config.Token{JWKS: keys.jwks}, jwt.SigningMethodRS256, keys.rsa, "rsa-2", valid(nil), false},
+		{"jwks unknown kid falls back to pem", config.Token{JWKS: keys.jwks, PublicKey: keys.rsaPEM}, jwt.SigningMethodRS256, keys.rsa, "rsa-2", valid(nil), true},
+		{"no keys configured", config.Token{}, jwt.SigningMethodRS256, keys.rsa, "", valid(nil), false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useToken(tt.setting)
+			claims, err := VerifyToken(sign(t, tt.method, tt.key, tt.kid, tt.claims))
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected token to verify, got %v", err)
+			case !tt.ok && err == nil:
+				t.Fatal("expected token to be rejected")
+			case !tt.ok && !errors.Is(err, ErrInvalidToken):
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			case tt.ok && claims["sub"] != "jdoe":
+				t.Fatalf("expected sub jdoe, got %v", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	////////////////////////////////////////////////////////////////////////////
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keys := newTestKeys(t, dir)
+	useToken(config.Token{PublicKey: keys.rsaPEM})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(nil))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	exp := time.Now().Unix() + 300
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"valid token", "Bearer " + sign(t, jwt.SigningMethodRS256, keys.rsa, "", jwt.MapClaims{"sub": "jdoe", "exp": exp}), http.StatusOK},
+		{"bad signature", "Bearer " + sign(t, jwt.SigningMethodRS256, keys.other, "", jwt.MapClaims{"sub": "jdoe", "exp": exp}), http.StatusUnauthorized},
+		{"expired token", "Bearer " + sign(t, jwt.SigningMethodRS256, keys.rsa, "", jwt.MapClaims{"sub": "jdoe", "exp": exp - 600}), http.StatusUnauthorized},
+		{"malformed token", "Bearer not.a.token", http.StatusUnauthorized},
+		{"api token without lookup", "Bearer " + TokenPrefix + "id_secret", http.StatusUnauthorized},
+		{"no token", "", http.StatusOK},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("expected a WWW-Authenticate header")
+			}
+		})
+	}
+}
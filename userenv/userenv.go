@@ -6,12 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
-	"regexp"
 	"strings"
 
-	"github.com/ticketmaster/lbapi/config"
-
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
@@ -19,8 +15,20 @@ import (
 	"github.com/ticketmaster/lbapi/shared"
 )
 
-// New - package constructor
+// ErrNotAuthorized - returned when the user holds no role for the product code
+// of an asset.
+var ErrNotAuthorized = errors.New("you are not authorized to make modifications to this asset")
+
+// New - package constructor. Requests made with an API token act as the
+// service account the token was issued to.
 func New(c *gin.Context) *User {
+	if scope, err := tokenScope(c); scope != nil || err != nil {
+		u := &User{Context: c, Error: err, Token: scope}
+		if scope != nil {
+			u.Username = "serviceaccount/" + scope.ServiceAccount
+		}
+		return u
+	}
 	return &User{
 		Group:    fetchRoles(c),
 		Username: fetchUser(c),
@@ -29,18 +37,24 @@ func New(c *gin.Context) *User {
 }
 
 // FetchRoles parses a session token and returns all roles.
-// associated to a logged in user.
+// associated to a logged in user. Bearer tokens that fail verification carry
+// no roles.
 func fetchRoles(c *gin.Context) []string {
 	////////////////////////////////////////////////////////////////////////////
 	// Fetch authorization (Post-Token Auth).
 	////////////////////////////////////////////////////////////////////////////
 	authString := c.Request.Header.Get("Authorization")
 	if authString != "" && strings.Contains(authString, "Bearer") {
+		claims, err := tokenClaims(c)
+		if err != nil {
+			c.Error(err)
+			return nil
+		}
 		var user *common.User
-		token := strings.Replace(authString, "Bearer ", "", 1)
-		parsed, _ := jwt.Parse(token, nil)
-		claims, _ := parsed.Claims.(jwt.MapClaims)
 		mapstructure.Decode(claims, &user)
+		if user == nil {
+			return nil
+		}
 		return user.Roles
 	}
 	////////////////////////////////////////////////////////////////////////////
@@ -86,11 +100,15 @@ func fetchUser(c *gin.Context) (r string) {
 	////////////////////////////////////////////////////////////////////////////
 	authString := c.Request.Header.Get("Authorization")
 	if authString != "" && strings.Contains(authString, "Bearer") {
+		claims, err := tokenClaims(c)
+		if err != nil {
+			return
+		}
 		var user *common.User
-		token := strings.Replace(authString, "Bearer ", "", 1)
-		parsed, _ := jwt.Parse(token, nil)
-		claims, _ := parsed.Claims.(jwt.MapClaims)
 		mapstructure.Decode(claims, &user)
+		if user == nil {
+			return
+		}
 		return user.Username
 	}
 	////////////////////////////////////////////////////////////////////////////
@@ -108,42 +126,3 @@ func fetchUser(c *gin.Context) (r string) {
 	}
 	return r
 }
-
-// HasAdminRight matches a product code to roles associated with a user.
-func (o *User) HasAdminRight(code string) (err error) {
-	if code == "" {
-		return errors.New("you did not provide a product code for this asset")
-	}
-	////////////////////////////////////////////////////////////////////////////
-	operator, err := o.hasRole("prd"+code+"-operator", o.Group)
-	if err != nil {
-		return
-	}
-	////////////////////////////////////////////////////////////////////////////
-	limited, err := o.hasRole("prd"+code+"-limitedaccess", o.Group)
-	if err != nil {
-		return
-	}
-	////////////////////////////////////////////////////////////////////////////
-	if operator == false && limited == false {
-		return errors.New("you are not authorized to make modifications to this asset. product code: " + code)
-	}
-	return nil
-}
-
-// hasRole matches a role (defined by regexp) to a security group.
-// in LDAP.
-func (o *User) hasRole(role string, roles []string) (matched bool, err error) {
-	for _, r := range roles {
-		matched, err = regexp.MatchString(role, strings.ToLower(r))
-		if matched == true {
-			return matched, err
-		}
-		adminRole := config.GlobalConfig.Lbm.AdminGroup
-		matched, err = regexp.MatchString(adminRole, strings.ToLower(r))
-		if matched == true {
-			return matched, err
-		}
-	}
-	return matched, err
-}
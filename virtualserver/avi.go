@@ -26,6 +26,7 @@ type Avi struct {
 	Pool             *pool.Avi
 	PoolGroup        *poolgroup.Avi
 	RemovedArtifacts *RemovedArtifacts
+	Tracker          shared.Tracker
 }
 
 // Cleanup deletes are dependencies.
@@ -95,7 +96,9 @@ func (o *Avi) Create(data *Data) (err error) {
 	////////////////////////////////////////////////////////////////////////////
 	// Create virtualservice.
 	////////////////////////////////////////////////////////////////////////////
+	done := shared.Track(o.Tracker, "vs create")
 	resp, err := o.Client.VirtualService.Create(req)
+	done(err)
 	if err != nil {
 		return
 	}
@@ -26,6 +26,7 @@ type Netscaler struct {
 	Pool             *pool.Netscaler
 	Log              *logrus.Entry
 	RemovedArtifacts *RemovedArtifacts
+	Tracker          shared.Tracker
 }
 
 // Cleanup deletes are dependencies.
@@ -94,7 +95,9 @@ func (o *Netscaler) Create(data *Data) (err error) {
 	// Create virtual service.
 	// Required
 	////////////////////////////////////////////////////////////////////////////
+	done := shared.Track(o.Tracker, "vs create")
 	resp, err := o.Client.AddLbvserver(*req)
+	done(err)
 	if err != nil {
 		return
 	}
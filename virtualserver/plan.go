@@ -0,0 +1,294 @@
+package virtualserver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/certificate"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/infoblox"
+	"github.com/ticketmaster/lbapi/monitor"
+	"github.com/ticketmaster/lbapi/pool"
+)
+
+// Plan - changes a modify request would apply to the load balancer. Plans are
+// produced by dry runs and are never applied.
+type Plan struct {
+	// Changes - top level fields that differ from the load balancer.
+	Changes []FieldChange `json:"changes,omitempty"`
+	// Pools - pool changes.
+	Pools PoolsPlan `json:"pools"`
+	// Certificates - certificate changes. Only applies to Avi.
+	Certificates CertificatesPlan `json:"certificates"`
+	// DNS - dns changes. Only populated when infoblox is enabled.
+	DNS DNSPlan `json:"dns"`
+}
+
+// FieldChange - single field that differs between the request and the lb.
+type FieldChange struct {
+	// Field - json name of the field.
+	Field string `json:"field"`
+	// From - value on the load balancer.
+	From interface{} `json:"from"`
+	// To - requested value.
+	To interface{} `json:"to"`
+}
+
+// PoolsPlan - pool changes.
+type PoolsPlan struct {
+	// Added - pools that will be created.
+	Added []pool.Data `json:"added,omitempty"`
+	// Removed - pools that will be unbound and marked for deletion.
+	Removed []pool.Data `json:"removed,omitempty"`
+	// Updated - pools that will be modified.
+	Updated []PoolPlan `json:"updated,omitempty"`
+}
+
+// PoolPlan - changes to an existing pool.
+type PoolPlan struct {
+	// Name - name of the pool on the load balancer.
+	Name string `json:"name"`
+	// SourceUUID [system] - uuid of the pool on the load balancer.
+	SourceUUID string `json:"_uuid,omitempty"`
+	// Changes - pool fields that differ from the load balancer.
+	Changes []FieldChange `json:"changes,omitempty"`
+	// Bindings - backend server changes.
+	Bindings BindingsPlan `json:"bindings"`
+	// HealthMonitors - health monitor changes.
+	HealthMonitors MonitorsPlan `json:"health_monitors"`
+}
+
+// BindingsPlan - backend server changes.
+type BindingsPlan struct {
+	Added   []pool.MemberBinding `json:"added,omitempty"`
+	Removed []pool.MemberBinding `json:"removed,omitempty"`
+	Updated []pool.MemberBinding `json:"updated,omitempty"`
+}
+
+// MonitorsPlan - health monitor changes.
+type MonitorsPlan struct {
+	Added   []monitor.Data `json:"added,omitempty"`
+	Removed []monitor.Data `json:"removed,omitempty"`
+	Updated []monitor.Data `json:"updated,omitempty"`
+}
+
+// CertificatesPlan - certificate changes. Private keys are never returned.
+type CertificatesPlan struct {
+	Added   []certificate.Data `json:"added,omitempty"`
+	Removed []certificate.Data `json:"removed,omitempty"`
+	Updated []certificate.Data `json:"updated,omitempty"`
+}
+
+// DNSPlan - dns changes.
+type DNSPlan struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// monitorDiff - compares requested and source health monitors using the
+// manufacturer specific diff.
+type monitorDiff func(req []monitor.Data, source []monitor.Data) (added []monitor.Data, removed []monitor.Data, updated []monitor.Data)
+
+// Plan returns the changes a modify would apply without applying them.
+func (o *Avi) Plan(data *Data) (r *Plan, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.Log.Info("planning...")
+	////////////////////////////////////////////////////////////////////////////
+	source := *data
+	exists, err := o.Exists(&source)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = fmt.Errorf("no loadbalancer record found - %s", data.Name)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Avi diffs monitors by name, so names are assigned the same way
+	// SetHealthMonitors does before comparing.
+	////////////////////////////////////////////////////////////////////////////
+	diff := func(req []monitor.Data, src []monitor.Data) ([]monitor.Data, []monitor.Data, []monitor.Data) {
+		var refs []string
+		for _, v := range src {
+			refs = append(refs, v.SourceUUID)
+		}
+		named := make([]monitor.Data, len(req))
+		for k, v := range req {
+			if v.Name == "" {
+				v.Name = fmt.Sprintf("%s-%s", strings.ToLower(data.Name), strings.ToLower(v.Type))
+			}
+			named[k] = v
+		}
+		return o.Pool.Monitor.Diff(named, refs)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r = newPlan(data, &source, diff)
+	r.DNS, err = planDNS(data)
+	return
+}
+
+// Plan returns the changes a modify would apply without applying them.
+func (o *Netscaler) Plan(data *Data) (r *Plan, err error) {
+	////////////////////////////////////////////////////////////////////////////
+	o.Log.Info("planning...")
+	////////////////////////////////////////////////////////////////////////////
+	lookup := *data
+	exists, err := o.Exists(&lookup)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = fmt.Errorf("no loadbalancer record found - %s", data.Name)
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	source, err := o.Fetch(lookup.SourceUUID)
+	if err != nil {
+		return
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r = newPlan(data, source, o.Monitor.Diff)
+	r.DNS, err = planDNS(data)
+	return
+}
+
+// newPlan compares the requested record against the source record.
+func newPlan(req *Data, source *Data, diffMonitors monitorDiff) (r *Plan) {
+	r = new(Plan)
+	////////////////////////////////////////////////////////////////////////////
+	// Virtual service.
+	////////////////////////////////////////////////////////////////////////////
+	r.Changes = appendChange(r.Changes, "name", source.Name, req.Name)
+	r.Changes = appendChange(r.Changes, "service_type", source.ServiceType, req.ServiceType)
+	r.Changes = appendChange(r.Changes, "ip", source.IP, req.IP)
+	r.Changes = appendChange(r.Changes, "ports", source.Ports, req.Ports)
+	r.Changes = appendChange(r.Changes, "enabled", source.Enabled, req.Enabled)
+	r.Changes = appendChange(r.Changes, "load_balancing_method", source.LoadBalancingMethod, req.LoadBalancingMethod)
+	////////////////////////////////////////////////////////////////////////////
+	// Pools.
+	////////////////////////////////////////////////////////////////////////////
+	mSource := make(map[string]pool.Data)
+	mReq := make(map[string]pool.Data)
+	for _, v := range source.Pools {
+		mSource[v.SourceUUID] = v
+	}
+	for _, v := range req.Pools {
+		if v.SourceUUID != "" {
+			mReq[v.SourceUUID] = v
+		}
+	}
+	for _, v := range req.Pools {
+		if v.SourceUUID == "" || mSource[v.SourceUUID].SourceUUID == "" {
+			r.Pools.Added = append(r.Pools.Added, v)
+			continue
+		}
+		p := planPool(v, mSource[v.SourceUUID], diffMonitors)
+		if len(p.Changes) > 0 || !p.Bindings.empty() || !p.HealthMonitors.empty() {
+			r.Pools.Updated = append(r.Pools.Updated, p)
+		}
+	}
+	for _, v := range source.Pools {
+		if mReq[v.SourceUUID].SourceUUID == "" {
+			r.Pools.Removed = append(r.Pools.Removed, v)
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Certificates.
+	////////////////////////////////////////////////////////////////////////////
+	added, removed, updated := pool.DiffCertificates(req.Certificates, source.Certificates)
+	r.Certificates.Added = redactCertificates(added)
+	r.Certificates.Removed = redactCertificates(removed)
+	mCerts := make(map[string]certificate.Data)
+	for _, v := range source.Certificates {
+		mCerts[v.SourceUUID] = v
+	}
+	for _, v := range updated {
+		if v.Key.PrivateKey != "" || (v.Certificate != "" && v.Certificate != mCerts[v.SourceUUID].Certificate) {
+			r.Certificates.Updated = append(r.Certificates.Updated, v)
+		}
+	}
+	r.Certificates.Updated = redactCertificates(r.Certificates.Updated)
+	return
+}
+
+// planPool compares a requested pool against its source.
+func planPool(req pool.Data, source pool.Data, diffMonitors monitorDiff) (r PoolPlan) {
+	r.Name = source.Name
+	r.SourceUUID = source.SourceUUID
+	////////////////////////////////////////////////////////////////////////////
+	r.Changes = appendChange(r.Changes, "enabled", source.Enabled, req.Enabled)
+	r.Changes = appendChange(r.Changes, "default_port", source.DefaultPort, req.DefaultPort)
+	r.Changes = appendChange(r.Changes, "ssl_enabled", source.SSLEnabled, req.SSLEnabled)
+	r.Changes = appendChange(r.Changes, "graceful_disable", source.GracefulDisable, req.GracefulDisable)
+	r.Changes = appendChange(r.Changes, "disable_delay", source.DisableDelay, req.DisableDelay)
+	r.Changes = appendChange(r.Changes, "max_client_connections", source.MaxClientConnections, req.MaxClientConnections)
+	r.Changes = appendChange(r.Changes, "persistence", source.Persistence.Type, req.Persistence.Type)
+	r.Changes = appendChange(r.Changes, "priority", source.Priority, req.Priority)
+	r.Changes = appendChange(r.Changes, "weight", source.Weight, req.Weight)
+	////////////////////////////////////////////////////////////////////////////
+	// Bindings. Unchanged bindings are omitted from updated.
+	////////////////////////////////////////////////////////////////////////////
+	mBindings := make(map[string]pool.MemberBinding)
+	for _, v := range source.Bindings {
+		mBindings[v.Server.SourceUUID] = v
+	}
+	var updated []pool.MemberBinding
+	r.Bindings.Added, r.Bindings.Removed, updated = pool.DiffBindings(req.Bindings, source.Bindings)
+	for _, v := range updated {
+		s := mBindings[v.Server.SourceUUID]
+		if v.Port != s.Port || v.Enabled != s.Enabled || v.GracefulDisable != s.GracefulDisable || v.DisableDelay != s.DisableDelay || v.Server.IP != s.Server.IP {
+			r.Bindings.Updated = append(r.Bindings.Updated, v)
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Health monitors. Unchanged monitors are omitted from updated.
+	////////////////////////////////////////////////////////////////////////////
+	mMonitors := make(map[string]monitor.Data)
+	for _, v := range source.HealthMonitors {
+		mMonitors[v.SourceUUID] = v
+	}
+	var updatedMonitors []monitor.Data
+	r.HealthMonitors.Added, r.HealthMonitors.Removed, updatedMonitors = diffMonitors(req.HealthMonitors, source.HealthMonitors)
+	for _, v := range updatedMonitors {
+		if !reflect.DeepEqual(v, mMonitors[v.SourceUUID]) {
+			r.HealthMonitors.Updated = append(r.HealthMonitors.Updated, v)
+		}
+	}
+	return
+}
+
+// planDNS compares requested dns names against infoblox.
+func planDNS(data *Data) (r DNSPlan, err error) {
+	if !config.GlobalConfig.Infoblox.Enable {
+		return
+	}
+	ib := infoblox.NewInfoblox()
+	defer ib.Client.Unset()
+	r.Added, r.Removed, err = ib.Diff(data.IP, data.ProductCode, data.DNS)
+	return
+}
+
+func appendChange(changes []FieldChange, field string, from interface{}, to interface{}) []FieldChange {
+	if reflect.DeepEqual(from, to) {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, From: from, To: to})
+}
+
+func redactCertificates(data []certificate.Data) (r []certificate.Data) {
+	for _, v := range data {
+		v.Key.PrivateKey = ""
+		v.Key.PassPhrase = ""
+		r = append(r, v)
+	}
+	return
+}
+
+func (o BindingsPlan) empty() bool {
+	return len(o.Added) == 0 && len(o.Removed) == 0 && len(o.Updated) == 0
+}
+
+func (o MonitorsPlan) empty() bool {
+	return len(o.Added) == 0 && len(o.Removed) == 0 && len(o.Updated) == 0
+}
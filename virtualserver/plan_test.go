@@ -0,0 +1,153 @@
+package virtualserver
+
+import (
+	"testing"
+
+	"github.com/ticketmaster/lbapi/certificate"
+	"github.com/ticketmaster/lbapi/monitor"
+	"github.com/ticketmaster/lbapi/pool"
+)
+
+// noMonitors - monitor diff that reports no changes.
+func noMonitors(req []monitor.Data, source []monitor.Data) (added []monitor.Data, removed []monitor.Data, updated []monitor.Data) {
+	return
+}
+
+func binding(uuid string, ip string, port int) pool.MemberBinding {
+	return pool.MemberBinding{Port: port, Enabled: true, Server: pool.Server{IP: ip, SourceUUID: uuid}}
+}
+
+func TestAppendChange(t *testing.T) {
+	tests := []struct {
+		name string
+		from interface{}
+		to   interface{}
+		want int
+	}{
+		{"equal strings", "a", "a", 0},
+		{"different strings", "a", "b", 1},
+		{"equal slices", []string{"a"}, []string{"a"}, 0},
+		{"different slices", []string{"a"}, []string{"b"}, 1},
+		{"nil and empty", []string(nil), []string{}, 1},
+		{"different bools", false, true, 1},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := appendChange(nil, "field", tt.from, tt.to)
+			if len(r) != tt.want {
+				t.Fatalf("expected %d changes, got %d", tt.want, len(r))
+			}
+			if tt.want == 1 && r[0].Field != "field" {
+				t.Fatalf("expected field, got %s", r[0].Field)
+			}
+		})
+	}
+}
+
+func TestPlanPool(t *testing.T) {
+	source := pool.Data{
+		Name:       "prd1-pool",
+		SourceUUID: "pool-1",
+		Enabled:    true,
+		Bindings:   []pool.MemberBinding{binding("b1", "10.0.0.1", 80), binding("b2", "10.0.0.2", 80)},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name     string
+		edit     func(p *pool.Data)
+		changes  int
+		added    int
+		removed  int
+		updated  int
+		monitors bool
+	}{
+		{"unchanged", func(p *pool.Data) {}, 0, 0, 0, 0, false},
+		{"disabled", func(p *pool.Data) { p.Enabled = false }, 1, 0, 0, 0, false},
+		{"binding added", func(p *pool.Data) { p.Bindings = append(p.Bindings, binding("b3", "10.0.0.3", 80)) }, 0, 1, 0, 0, false},
+		{"binding removed", func(p *pool.Data) { p.Bindings = p.Bindings[:1] }, 0, 0, 1, 0, false},
+		{"binding port changed", func(p *pool.Data) { p.Bindings[0].Port = 8080 }, 0, 0, 0, 1, false},
+		{"monitor added", func(p *pool.Data) { p.HealthMonitors = []monitor.Data{{Type: "http"}} }, 0, 0, 0, 0, true},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := source
+			req.Bindings = append([]pool.MemberBinding(nil), source.Bindings...)
+			tt.edit(&req)
+			diff := func(req []monitor.Data, source []monitor.Data) ([]monitor.Data, []monitor.Data, []monitor.Data) {
+				return req, nil, nil
+			}
+			r := planPool(req, source, diff)
+			if r.Name != source.Name || r.SourceUUID != source.SourceUUID {
+				t.Fatalf("expected pool %s/%s, got %s/%s", source.Name, source.SourceUUID, r.Name, r.SourceUUID)
+			}
+			if len(r.Changes) != tt.changes {
+				t.Fatalf("expected %d changes, got %+v", tt.changes, r.Changes)
+			}
+			if len(r.Bindings.Added) != tt.added || len(r.Bindings.Removed) != tt.removed || len(r.Bindings.Updated) != tt.updated {
+				t.Fatalf("expected bindings %d/%d/%d, got %+v", tt.added, tt.removed, tt.updated, r.Bindings)
+			}
+			if r.HealthMonitors.empty() == tt.monitors {
+				t.Fatalf("expected monitor changes %v, got %+v", tt.monitors, r.HealthMonitors)
+			}
+		})
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	source := Data{
+		Name:    "prd1-vip",
+		IP:      "10.0.1.1",
+		Enabled: true,
+		Pools: []pool.Data{
+			{Name: "prd1-pool-a", SourceUUID: "pool-a", Bindings: []pool.MemberBinding{binding("b1", "10.0.0.1", 80)}},
+			{Name: "prd1-pool-b", SourceUUID: "pool-b"},
+		},
+		Certificates: []certificate.Data{{Name: "cert", SourceUUID: "cert-1", Certificate: "pem"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name         string
+		edit         func(d *Data)
+		changes      int
+		added        int
+		removed      int
+		updated      int
+		certificates int
+	}{
+		{"unchanged", func(d *Data) {}, 0, 0, 0, 0, 0},
+		{"ip changed", func(d *Data) { d.IP = "10.0.1.2" }, 1, 0, 0, 0, 0},
+		{"pool added", func(d *Data) { d.Pools = append(d.Pools, pool.Data{Name: "prd1-pool-c"}) }, 0, 1, 0, 0, 0},
+		{"pool removed", func(d *Data) { d.Pools = d.Pools[:1] }, 0, 0, 1, 0, 0},
+		{"unknown pool uuid is added", func(d *Data) { d.Pools[1].SourceUUID = "pool-x" }, 0, 1, 1, 0, 0},
+		{"pool updated", func(d *Data) { d.Pools[0].Bindings = nil }, 0, 0, 0, 1, 0},
+		{"certificate replaced", func(d *Data) { d.Certificates[0].Certificate = "new" }, 0, 0, 0, 0, 1},
+		{"certificate unchanged", func(d *Data) { d.Certificates[0].Certificate = "" }, 0, 0, 0, 0, 0},
+		{"certificate key rotated", func(d *Data) { d.Certificates[0].Key.PrivateKey = "key" }, 0, 0, 0, 0, 1},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := source
+			req.Pools = append([]pool.Data(nil), source.Pools...)
+			req.Certificates = append([]certificate.Data(nil), source.Certificates...)
+			tt.edit(&req)
+			r := newPlan(&req, &source, noMonitors)
+			if len(r.Changes) != tt.changes {
+				t.Fatalf("expected %d changes, got %+v", tt.changes, r.Changes)
+			}
+			if len(r.Pools.Added) != tt.added || len(r.Pools.Removed) != tt.removed || len(r.Pools.Updated) != tt.updated {
+				t.Fatalf("expected pools %d/%d/%d, got %+v", tt.added, tt.removed, tt.updated, r.Pools)
+			}
+			if len(r.Certificates.Updated) != tt.certificates {
+				t.Fatalf("expected %d certificate updates, got %+v", tt.certificates, r.Certificates.Updated)
+			}
+			for _, v := range r.Certificates.Updated {
+				if v.Key.PrivateKey != "" || v.Key.PassPhrase != "" {
+					t.Fatal("expected private key to be redacted")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package virtualserver
+
+import (
+	"net"
+	"strings"
+
+	"github.com/ticketmaster/lbapi/loadbalancer"
+	"github.com/ticketmaster/lbapi/pool"
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+// LoadBalancingMethods - methods matched by SetLoadbalancingMethod (Avi) and
+// setLBMethodNsr (Netscaler).
+var LoadBalancingMethods = []string{"roundrobin", "leastconnection"}
+
+// Validate - checks the values of data against the options supported by mfr
+// ("avi networks" or "netscaler") so bad payloads are rejected before the
+// ETL runs. The options of every platform are accepted when mfr is empty.
+// Paths are reported relative to path.
+func Validate(data *Data, mfr string, path string) (r []shared.FieldError) {
+	////////////////////////////////////////////////////////////////////////////
+	r = append(r, shared.CheckRequired(shared.JSONPath(path, "name"), data.Name != "")...)
+	r = append(r, shared.CheckRequired(shared.JSONPath(path, "product_code"), data.ProductCode != 0)...)
+	if data.IP != "" {
+		if ip := net.ParseIP(data.IP); ip == nil || ip.To4() == nil {
+			r = append(r, shared.FieldError{Path: shared.JSONPath(path, "ip"), Reason: "must be an ipv4 address"})
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	serviceTypes := loadbalancer.SupportedServiceTypes(mfr)
+	r = append(r, shared.CheckRequired(shared.JSONPath(path, "service_type"), data.ServiceType != "")...)
+	r = append(r, shared.CheckEnum(shared.JSONPath(path, "service_type"), data.ServiceType, serviceTypes)...)
+	r = append(r, shared.CheckEnum(shared.JSONPath(path, "load_balancing_method"), data.LoadBalancingMethod, LoadBalancingMethods)...)
+	// Avi pools inherit the method and reject an empty one.
+	if mfr == "avi networks" && len(data.Pools) > 0 {
+		r = append(r, shared.CheckRequired(shared.JSONPath(path, "load_balancing_method"), data.LoadBalancingMethod != "")...)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	r = append(r, shared.CheckRequired(shared.JSONPath(path, "ports"), len(data.Ports) > 0)...)
+	profiles := loadbalancer.SupportedNetworkProfiles(mfr)
+	for k, v := range data.Ports {
+		portPath := shared.JSONPath(shared.JSONPath(path, "ports"), k)
+		r = append(r, shared.CheckRange(shared.JSONPath(portPath, "port"), v.Port, 1, 65535)...)
+		r = append(r, shared.CheckEnum(shared.JSONPath(portPath, "l4_profile"), v.L4Profile, profiles)...)
+		if strings.Contains(v.L4Profile, "udp") && !strings.Contains(data.ServiceType, "udp") {
+			var allowed []string
+			for _, s := range serviceTypes {
+				if strings.Contains(s, "udp") {
+					allowed = append(allowed, s)
+				}
+			}
+			r = append(r, shared.FieldError{
+				Path:    shared.JSONPath(path, "service_type"),
+				Reason:  "udp l4 profiles require a udp service type",
+				Allowed: allowed,
+			})
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for k := range data.Pools {
+		r = append(r, pool.Validate(&data.Pools[k], mfr, shared.JSONPath(shared.JSONPath(path, "pools"), k))...)
+	}
+	return
+}
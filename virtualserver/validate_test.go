@@ -0,0 +1,64 @@
+package virtualserver
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/pool"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() Data {
+		return Data{
+			Name:                "prd1-vip-web",
+			ProductCode:         1,
+			IP:                  "10.0.0.1",
+			ServiceType:         "http",
+			LoadBalancingMethod: "roundrobin",
+			Ports:               []Port{{Port: 80, L4Profile: "tcp"}},
+			Pools:               []pool.Data{{Bindings: []pool.MemberBinding{{Port: 80, Server: pool.Server{IP: "10.0.1.1"}}}}},
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	tests := []struct {
+		name  string
+		mfr   string
+		edit  func(d *Data)
+		paths []string
+	}{
+		{"valid", "", func(d *Data) {}, nil},
+		{"valid on avi", "avi networks", func(d *Data) {}, nil},
+		{"valid on netscaler", "netscaler", func(d *Data) {}, nil},
+		{"missing required", "", func(d *Data) { *d = Data{} }, []string{"data.name", "data.ports", "data.product_code", "data.service_type"}},
+		{"ipv6", "", func(d *Data) { d.IP = "::1" }, []string{"data.ip"}},
+		{"bad ip", "", func(d *Data) { d.IP = "10.0.0" }, []string{"data.ip"}},
+		{"unknown service type", "", func(d *Data) { d.ServiceType = "ftp" }, []string{"data.service_type"}},
+		{"avi only service type on netscaler", "netscaler", func(d *Data) { d.ServiceType = "syslog" }, []string{"data.service_type"}},
+		{"unknown method", "", func(d *Data) { d.LoadBalancingMethod = "random" }, []string{"data.load_balancing_method"}},
+		{"avi pools need a method", "avi networks", func(d *Data) { d.LoadBalancingMethod = "" }, []string{"data.load_balancing_method"}},
+		{"port out of range", "", func(d *Data) { d.Ports[0].Port = 70000 }, []string{"data.ports[0].port"}},
+		{"avi profile on netscaler", "netscaler", func(d *Data) { d.Ports[0].L4Profile = "udp-per-pkt" }, []string{"data.ports[0].l4_profile", "data.service_type"}},
+		{"udp profile on tcp service", "", func(d *Data) { d.Ports[0].L4Profile = "udp" }, []string{"data.service_type"}},
+		{"udp profile on udp service", "", func(d *Data) {
+			d.ServiceType = "l4-app-udp"
+			d.Ports[0].L4Profile = "udp"
+		}, nil},
+		{"pool errors are nested", "", func(d *Data) { d.Pools[0].Bindings[0].Server.IP = "" }, []string{"data.pools[0].bindings[0].server.ip"}},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.edit(&d)
+			var paths []string
+			for _, v := range Validate(&d, tt.mfr, "data") {
+				paths = append(paths, v.Path)
+			}
+			sort.Strings(paths)
+			if strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+				t.Fatalf("expected %v, got %v", tt.paths, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package webhook
+
+// Event - payload POSTed to every configured endpoint.
+type Event struct {
+	// ID - id of the event. Retries of a delivery share the id.
+	ID string `json:"id"`
+	// Type - e.g. virtualserver.status_changed, virtualserver.recycled,
+	// virtualserver.backup_completed or virtualserver.import_completed.
+	Type string `json:"type"`
+	// Time - time the event was raised.
+	Time string `json:"time"`
+	// Route - route the record belongs to.
+	Route string `json:"route"`
+	// RecordID - id of the record. Empty for backup and import events.
+	RecordID string `json:"record_id,omitempty"`
+	// ProductCode - product code of the record.
+	ProductCode int `json:"product_code,omitempty"`
+	// User - user that made the request.
+	User string `json:"user"`
+	// OldStatus - status before the transition.
+	OldStatus string `json:"old_status,omitempty"`
+	// NewStatus - status after the transition.
+	NewStatus string `json:"new_status,omitempty"`
+	// LastError - error recorded with the transition.
+	LastError string `json:"last_error,omitempty"`
+	// Count - number of records backed up or imported.
+	Count int `json:"count,omitempty"`
+}
@@ -0,0 +1,136 @@
+// Package webhook POSTs signed JSON events to the endpoints configured under
+// [Webhook]. Deliveries run in the background and are retried with
+// exponential backoff; events that still fail are written to the
+// webhook_deadletter table.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ticketmaster/lbapi/config"
+	"github.com/ticketmaster/lbapi/dao"
+	"github.com/ticketmaster/lbapi/shared"
+)
+
+const (
+	// defaultMaxAttempts - attempts per endpoint when MaxAttempts is not set.
+	defaultMaxAttempts = 5
+	// initialBackoff - wait before the first retry. It doubles after every
+	// attempt up to maxBackoff.
+	initialBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+var (
+	log    = logrus.New()
+	client = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Publish - sends e to every configured endpoint in the background. ID and
+// Time are set when empty. Does nothing unless webhooks are enabled.
+func Publish(e Event) {
+	////////////////////////////////////////////////////////////////////////////
+	if config.GlobalConfig == nil || !config.GlobalConfig.Webhook.Enable {
+		return
+	}
+	conf := config.GlobalConfig.Webhook
+	if e.ID == "" {
+		e.ID, _ = shared.NewUUID()
+	}
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	////////////////////////////////////////////////////////////////////////////
+	p, err := json.Marshal(e)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, url := range conf.URLs {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		go deliver(conf, url, e, p)
+	}
+}
+
+// deliver - POSTs p to url until it is accepted or the attempts run out.
+func deliver(conf config.Webhook, url string, e Event, p []byte) {
+	////////////////////////////////////////////////////////////////////////////
+	attempts := conf.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultMaxAttempts
+	}
+	entry := log.WithFields(logrus.Fields{"handler": "webhook", "event": e.ID, "type": e.Type, "url": url})
+	////////////////////////////////////////////////////////////////////////////
+	var err error
+	backoff := initialBackoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = post(conf.Secret, url, e, p)
+		if err == nil {
+			return
+		}
+		entry.Warnf("attempt %d of %d failed - %v", attempt, attempts, err)
+		if attempt == attempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff = backoff * 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	////////////////////////////////////////////////////////////////////////////
+	// Dead letter.
+	////////////////////////////////////////////////////////////////////////////
+	if dao.GlobalDAO == nil {
+		return
+	}
+	_, dbErr := dao.GlobalDAO.Db.Exec(`INSERT INTO public.webhook_deadletter (id, url, event_type, data, attempts, last_error, last_modified) VALUES ($1, $2, $3, $4, $5, $6, current_timestamp)`, e.ID, url, e.Type, string(p), attempts, err.Error())
+	if dbErr != nil {
+		entry.Warn(dbErr)
+	}
+}
+
+// post - sends a single delivery. Any 2xx response is a success.
+func post(secret string, url string, e Event, p []byte) (err error) {
+	////////////////////////////////////////////////////////////////////////////
+	req, err := http.NewRequest("POST", url, bytes.NewReader(p))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Lbapi-Event", e.Type)
+	req.Header.Set("X-Lbapi-Delivery", e.ID)
+	if secret != "" {
+		req.Header.Set("X-Lbapi-Signature", Sign(secret, p))
+	}
+	////////////////////////////////////////////////////////////////////////////
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign - returns the X-Lbapi-Signature of p: sha256= followed by the hex
+// HMAC-SHA256 of the body keyed with secret.
+func Sign(secret string, p []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(p)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ticketmaster/lbapi/config"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		want   string
+	}{
+		// RFC 4231 test case 2.
+		{"rfc 4231", "Jefe", "what do ya want for nothing?", "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"},
+		{"empty body", "key", "", "sha256=5d5d139563c95b5967b9bd9a8c9b233a9dedb45072794cd232dc1b74832607d0"},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := Sign(tt.secret, []byte(tt.body)); r != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestPost(t *testing.T) {
+	e := Event{ID: "event-1", Type: "virtualserver.status_changed"}
+	body := []byte(`{"id":"event-1"}`)
+	tests := []struct {
+		name   string
+		secret string
+		status int
+		ok     bool
+	}{
+		{"signed", "secret", http.StatusOK, true},
+		{"unsigned", "", http.StatusNoContent, true},
+		{"rejected", "secret", http.StatusBadRequest, false},
+		{"server error", "secret", http.StatusInternalServerError, false},
+	}
+	////////////////////////////////////////////////////////////////////////////
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				gotBody, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			err := post(tt.secret, server.URL, e, body)
+			if tt.ok != (err == nil) {
+				t.Fatalf("expected success %v, got %v", tt.ok, err)
+			}
+			if string(gotBody) != string(body) {
+				t.Fatalf("expected body %s, got %s", body, gotBody)
+			}
+			if got.Header.Get("X-Lbapi-Event") != e.Type || got.Header.Get("X-Lbapi-Delivery") != e.ID {
+				t.Fatalf("expected event headers, got %v", got.Header)
+			}
+			signature := got.Header.Get("X-Lbapi-Signature")
+			if tt.secret == "" && signature != "" {
+				t.Fatalf("expected no signature, got %s", signature)
+			}
+			if tt.secret != "" && signature != Sign(tt.secret, body) {
+				t.Fatalf("expected signature %s, got %s", Sign(tt.secret, body), signature)
+			}
+		})
+	}
+}
+
+func TestPublishDisabled(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+	config.GlobalConfig = &config.Setting{Webhook: config.Webhook{Enable: false, URLs: []string{server.URL}}}
+	defer func() { config.GlobalConfig = nil }()
+	Publish(Event{Type: "virtualserver.recycled"})
+	if calls != 0 {
+		t.Fatalf("expected no deliveries, got %d", calls)
+	}
+}